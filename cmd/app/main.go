@@ -26,6 +26,11 @@ import (
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
+
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name X-Admin-Token
+// @description Значение переменной окружения ADMIN_TOKEN.
 func main() {
 	log := logger.New("finance-service", true)
 
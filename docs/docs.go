@@ -24,9 +24,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/logout": {
+        "/admin/budgets/recalculate": {
             "post": {
-                "description": "Деактивация refresh токена и выход из системы",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Служебный эндпоинт: заново рассчитывает spent_amount бюджетов всех пользователей по таблице расходов, исправляя накопившиеся расхождения",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,21 +39,18 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "Выход из системы",
+                "summary": "Пересчет потраченных сумм бюджетов всех пользователей",
                 "responses": {
                     "200": {
-                        "description": "Успешный выход",
+                        "description": "Количество пересчитанных бюджетов",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.RecalculateBudgetsResponse"
                         }
                     },
-                    "401": {
-                        "description": "Токен не найден",
+                    "403": {
+                        "description": "Неверный токен администратора",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -62,51 +64,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/sign-in": {
+        "/admin/exchange-rates": {
             "post": {
-                "description": "Аутентификация пользователя и получение JWT токенов",
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "description": "Сохраняет курсы валют (курс за ту же дату перезаписывается) и пересчитывает по ним суммы расходов в базовой валюте пользователей и потраченные суммы бюджетов.\nПринимает JSON или CSV (Content-Type: text/csv) с заголовком date,base_currency,quote_currency,rate.\nКурс означает, сколько единиц quote_currency стоит 1 base_currency. Если прямого курса нет, используется обратный или кросс-курс через общую валюту",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Admin"
                 ],
-                "summary": "Вход в систему",
+                "summary": "Загрузка курсов валют",
                 "parameters": [
                     {
-                        "description": "Данные для входа",
-                        "name": "credentials",
+                        "description": "Курсы валют",
+                        "name": "rates",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.LoginRequest"
+                            "$ref": "#/definitions/dto.ImportExchangeRatesRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешная аутентификация",
+                        "description": "Курсы загружены",
                         "schema": {
-                            "$ref": "#/definitions/dto.AuthResponse"
+                            "$ref": "#/definitions/dto.ImportExchangeRatesResponse"
                         }
                     },
                     "400": {
-                        "description": "Неверные данные для входа",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Неверный email или пароль",
+                        "description": "Ошибка валидации данных",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
+                    "403": {
+                        "description": "Неверный токен администратора",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -114,9 +116,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/sign-up": {
-            "post": {
-                "description": "Создание нового аккаунта пользователя в системе",
+        "/alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Последние уведомления о бюджетах: достигнут порог расходования (warning, exceeded) или период скоро закончится (near_end)",
                 "consumes": [
                     "application/json"
                 ],
@@ -124,35 +131,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Authentication"
+                    "Alerts"
                 ],
-                "summary": "Регистрация нового пользователя",
+                "summary": "Уведомления о бюджетах",
                 "parameters": [
                     {
-                        "description": "Данные для регистрации",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.RegisterRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Только непрочитанные",
+                        "name": "unacknowledged",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Пользователь успешно зарегистрирован",
+                    "200": {
+                        "description": "Список уведомлений",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserInfo"
+                            "$ref": "#/definitions/dto.BudgetAlertsListResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации данных",
+                        "description": "Неверные параметры запроса",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Пользователь с таким email уже существует",
+                    "401": {
+                        "description": "Требуется авторизация",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -166,14 +170,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories": {
-            "get": {
+        "/alerts/{alert_id}/ack": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение всех категорий пользователя",
+                "description": "Отмечает уведомление о бюджете прочитанным",
                 "consumes": [
                     "application/json"
                 ],
@@ -181,14 +185,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Alerts"
+                ],
+                "summary": "Подтверждение уведомления",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID уведомления",
+                        "name": "alert_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получение списка категорий",
                 "responses": {
                     "200": {
-                        "description": "Список категорий",
+                        "description": "Уведомление подтверждено",
                         "schema": {
-                            "$ref": "#/definitions/dto.CategoriesListResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID уведомления",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
@@ -197,6 +219,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Уведомление не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -204,14 +232,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/analytics/categories": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создание новой категории расходов для пользователя",
+                "description": "Сумма расходов по каждой категории и ее доля в общих расходах за период. Категории с долей меньше min_share объединяются в последнюю строку \"other\" с category_id = 0",
                 "consumes": [
                     "application/json"
                 ],
@@ -219,29 +249,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Analytics"
                 ],
-                "summary": "Создание новой категории",
+                "summary": "Структура расходов по категориям",
                 "parameters": [
                     {
-                        "description": "Данные для создания категории",
-                        "name": "category",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateCategoryRequest"
-                        }
+                        "type": "string",
+                        "description": "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD), вместо period",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD), вместо period",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 3,
+                        "description": "Доля в процентах, меньше которой категория попадает в other",
+                        "name": "min_share",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Категория успешно создана",
+                        "description": "Доли категорий",
                         "schema": {
-                            "$ref": "#/definitions/dto.CategoryResponse"
+                            "$ref": "#/definitions/dto.CategorySharesResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации данных",
+                        "description": "Неверные параметры периода или порога",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -261,14 +307,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/top": {
+        "/analytics/timeseries": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение списка категорий, отсортированных по частоте использования",
+                "description": "Сумма и количество расходов по дням, неделям или месяцам. Интервалы без расходов возвращаются с нулями. Для day и week заполняется points, для month - months",
                 "consumes": [
                     "application/json"
                 ],
@@ -276,14 +322,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Analytics"
+                ],
+                "summary": "Временной ряд расходов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "day",
+                        "description": "Интервал: day, week, month",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID категории, по умолчанию - все категории",
+                        "name": "category_id",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Получение наиболее используемых категорий",
                 "responses": {
                     "200": {
-                        "description": "Список наиболее используемых категорий",
+                        "description": "Временной ряд расходов",
                         "schema": {
-                            "$ref": "#/definitions/dto.CategoriesListResponse"
+                            "$ref": "#/definitions/dto.TimeSeriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры периода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
@@ -301,14 +380,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}": {
+        "/analytics/trends": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение информации о конкретной категории пользователя",
+                "description": "Сравнение расходов за период с предыдущим периодом и с тем же периодом прошлого года, в целом и по каждой категории. Незаконченный период сравнивается по одинаковому числу прошедших дней",
                 "consumes": [
                     "application/json"
                 ],
@@ -316,27 +395,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Analytics"
                 ],
-                "summary": "Получение категории по ID",
+                "summary": "Тренды расходов",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD), вместо period",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD), вместо period",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "ID категории",
+                        "description": "ID категории, по умолчанию - все категории",
                         "name": "category_id",
-                        "in": "path",
-                        "required": true
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 5,
+                        "description": "Изменение в процентах, до которого тренд считается стабильным",
+                        "name": "threshold",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о категории",
+                        "description": "Тренды расходов",
                         "schema": {
-                            "$ref": "#/definitions/dto.CategoryResponse"
+                            "$ref": "#/definitions/dto.TrendsResponse"
                         }
                     },
                     "400": {
-                        "description": "Неверный ID категории",
+                        "description": "Неверные параметры периода или порога",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -347,12 +450,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Категория не найдена",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -360,14 +457,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Удаление категории и всех связанных с ней расходов",
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Удаление refresh токена и отзыв текущего access токена. Достаточно передать один из токенов",
                 "consumes": [
                     "application/json"
                 ],
@@ -375,21 +469,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
+                    "Authentication"
                 ],
-                "summary": "Удаление категории",
+                "summary": "Выход из системы",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "Bearer access токен",
+                        "name": "Authorization",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Категория успешно удалена",
+                        "description": "Успешный выход",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -397,14 +490,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "400": {
-                        "description": "Неверный ID категории",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
                     "401": {
-                        "description": "Требуется авторизация",
+                        "description": "Токен не найден или недействителен",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -418,14 +505,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}/analytics": {
-            "get": {
+        "/auth/logout-all": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение детальной аналитики расходов по конкретной категории",
+                "description": "Удаление всех refresh токенов пользователя и отзыв всех выданных ему access токенов",
                 "consumes": [
                     "application/json"
                 ],
@@ -433,38 +520,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Categories"
-                ],
-                "summary": "Получение аналитики по категории",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Период для анализа",
-                        "name": "period",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CategoryPeriod"
-                        }
-                    }
+                    "Authentication"
                 ],
+                "summary": "Выход на всех устройствах",
                 "responses": {
                     "200": {
-                        "description": "Аналитика по категории",
-                        "schema": {
-                            "$ref": "#/definitions/dto.CategoryAnalytics"
-                        }
-                    },
-                    "400": {
-                        "description": "Неверный ID категории или период",
+                        "description": "Все сеансы завершены",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "401": {
@@ -482,14 +548,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}/budgets": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Получение всех бюджетов пользователя для указанной категории",
+        "/auth/refresh": {
+            "post": {
+                "description": "Обмен refresh токена на новую пару токенов. Токен берется из cookie refresh_token или из тела запроса.\nКаждый refresh токен можно обменять один раз: повторное использование отзывает все токены сеанса",
                 "consumes": [
                     "application/json"
                 ],
@@ -497,33 +558,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Budgets"
+                    "Authentication"
                 ],
-                "summary": "Получение списка бюджетов",
+                "summary": "Обновление токенов",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Refresh токен, если он не передан в cookie",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RefreshTokenRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список бюджетов",
+                        "description": "Новая пара токенов",
                         "schema": {
-                            "$ref": "#/definitions/dto.BudgetsListResponse"
+                            "$ref": "#/definitions/dto.TokenResponse"
                         }
                     },
-                    "400": {
-                        "description": "Неверный ID категории",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Требуется авторизация",
+                    "401": {
+                        "description": "Токен не найден, истек или уже использован",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -535,14 +591,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/sign-in": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Создание нового бюджета для указанной категории",
+                "description": "Аутентификация пользователя и получение JWT токенов",
                 "consumes": [
                     "application/json"
                 ],
@@ -550,42 +603,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Budgets"
+                    "Authentication"
                 ],
-                "summary": "Создание нового бюджета",
+                "summary": "Вход в систему",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные для создания бюджета",
-                        "name": "budget",
+                        "description": "Данные для входа",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateBudgetRequest"
+                            "$ref": "#/definitions/dto.LoginRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Бюджет успешно создан",
+                        "description": "Успешная аутентификация",
                         "schema": {
-                            "$ref": "#/definitions/dto.BudgetResponse"
+                            "$ref": "#/definitions/dto.AuthResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка валидации данных",
+                        "description": "Неверные данные для входа",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Требуется авторизация",
+                        "description": "Неверный email или пароль",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -599,14 +645,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}/budgets/{budget_id}": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Удаление конкретного бюджета пользователя",
+        "/auth/sign-up": {
+            "post": {
+                "description": "Создание нового аккаунта пользователя в системе",
                 "consumes": [
                     "application/json"
                 ],
@@ -614,43 +655,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Budgets"
+                    "Authentication"
                 ],
-                "summary": "Удаление бюджета",
+                "summary": "Регистрация нового пользователя",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID бюджета",
-                        "name": "budget_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Данные для регистрации",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RegisterRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Бюджет успешно удален",
+                    "201": {
+                        "description": "Пользователь успешно зарегистрирован",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.UserInfo"
                         }
                     },
                     "400": {
-                        "description": "Неверный ID категории или бюджета",
+                        "description": "Ошибка валидации данных",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Требуется авторизация",
+                    "409": {
+                        "description": "Пользователь с таким email уже существует",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -664,14 +697,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}/expenses": {
+        "/budgets": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение всех расходов пользователя в указанной категории",
+                "description": "Все бюджеты пользователя: бюджеты категорий, групп категорий и общие",
                 "consumes": [
                     "application/json"
                 ],
@@ -679,29 +712,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Expenses"
-                ],
-                "summary": "Получение списка расходов",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Budgets"
                 ],
+                "summary": "Получение всех бюджетов",
                 "responses": {
                     "200": {
-                        "description": "Список расходов",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ExpensesListResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Неверный ID категории",
+                        "description": "Список бюджетов",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.BudgetsListResponse"
                         }
                     },
                     "401": {
@@ -724,7 +742,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Создание нового расхода в указанной категории",
+                "description": "Бюджет без category_ids учитывает все расходы пользователя, с category_ids - расходы перечисленных категорий",
                 "consumes": [
                     "application/json"
                 ],
@@ -732,32 +750,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Expenses"
+                    "Budgets"
                 ],
-                "summary": "Создание нового расхода",
+                "summary": "Создание общего бюджета или бюджета группы категорий",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Данные для создания расхода",
-                        "name": "expense",
+                        "description": "Данные для создания бюджета",
+                        "name": "budget",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreateExpenseRequest"
+                            "$ref": "#/definitions/dto.CreateBudgetRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Расход успешно создан",
+                        "description": "Бюджет успешно создан",
                         "schema": {
-                            "$ref": "#/definitions/dto.ExpenseResponse"
+                            "$ref": "#/definitions/dto.BudgetResponse"
                         }
                     },
                     "400": {
@@ -781,14 +792,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}/expenses/analytics": {
-            "post": {
+        "/budgets/status": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение аналитики расходов по категории за указанный период",
+                "description": "Получение статуса (ok, warning, exceeded), процента расходования и оставшихся дней для всех бюджетов пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -796,38 +807,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Expenses"
-                ],
-                "summary": "Получение аналитики расходов",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Период для анализа",
-                        "name": "period",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.ExpensePeriod"
-                        }
-                    }
+                    "Budgets"
                 ],
+                "summary": "Статус всех бюджетов",
                 "responses": {
                     "200": {
-                        "description": "Аналитика расходов",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ExpenseAnalytics"
-                        }
-                    },
-                    "400": {
-                        "description": "Неверный ID категории или период",
+                        "description": "Статусы бюджетов",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/dto.BudgetStatusListResponse"
                         }
                     },
                     "401": {
@@ -845,14 +832,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{category_id}/expenses/{expense_id}": {
-            "get": {
+        "/budgets/{budget_id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение информации о конкретном расходе",
+                "description": "Удаление бюджета по ID без указания категории",
                 "consumes": [
                     "application/json"
                 ],
@@ -860,34 +847,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Expenses"
+                    "Budgets"
                 ],
-                "summary": "Получение расхода по ID",
+                "summary": "Удаление любого бюджета",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID расхода",
-                        "name": "expense_id",
+                        "description": "ID бюджета",
+                        "name": "budget_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Информация о расходе",
+                        "description": "Бюджет успешно удален",
                         "schema": {
-                            "$ref": "#/definitions/dto.ExpenseResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Неверный ID категории или расхода",
+                        "description": "Неверный ID бюджета",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -906,13 +889,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "patch": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Удаление конкретного расхода пользователя",
+                "description": "Изменение бюджета по ID без указания категории. Для общих бюджетов и групп можно изменить набор категорий",
                 "consumes": [
                     "application/json"
                 ],
@@ -920,37 +903,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Expenses"
+                    "Budgets"
                 ],
-                "summary": "Удаление расхода",
+                "summary": "Обновление любого бюджета",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID категории",
-                        "name": "category_id",
+                        "description": "ID бюджета",
+                        "name": "budget_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "ID расхода",
-                        "name": "expense_id",
-                        "in": "path",
-                        "required": true
+                        "description": "Изменяемые поля бюджета",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateBudgetRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Расход успешно удален",
+                        "description": "Бюджет успешно обновлен",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.BudgetResponse"
                         }
                     },
                     "400": {
-                        "description": "Неверный ID категории или расхода",
+                        "description": "Неверный ID бюджета или данные",
                         "schema": {
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
@@ -970,14 +952,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/account": {
-            "delete": {
+        "/budgets/{budget_id}/history": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Полное удаление аккаунта пользователя и всех связанных данных",
+                "description": "Закрытые периоды бюджета по ID без указания категории, начиная с последнего",
                 "consumes": [
                     "application/json"
                 ],
@@ -985,17 +967,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Budgets"
+                ],
+                "summary": "История периодов любого бюджета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID бюджета",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Удаление аккаунта пользователя",
                 "responses": {
                     "200": {
-                        "description": "Аккаунт успешно удален",
+                        "description": "История периодов бюджета",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/dto.BudgetHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
                     "401": {
@@ -1004,6 +998,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
@@ -1013,14 +1013,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/profile": {
+        "/cashflow": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение информации о текущем пользователе",
+                "description": "Доходы, расходы и чистый поток (доходы минус расходы) по интервалам за период. По умолчанию - последние 12 месяцев по месяцам",
                 "consumes": [
                     "application/json"
                 ],
@@ -1028,16 +1028,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Incomes"
                 ],
-                "summary": "Получение профиля пользователя",
-                "responses": {
-                    "200": {
-                        "description": "Профиль пользователя",
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserProfile"
-                        }
-                    },
+                "summary": "Движение денежных средств",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "month",
+                        "description": "Интервал: day, week, month, quarter, year",
+                        "name": "granularity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Движение денежных средств",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CashFlowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры периода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Требуется авторизация",
                         "schema": {
@@ -1053,14 +1080,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user/stats": {
+        "/categories": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Получение общей статистики по расходам, категориям и бюджетам",
+                "description": "Получение всех категорий пользователя",
                 "consumes": [
                     "application/json"
                 ],
@@ -1068,14 +1095,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Categories"
                 ],
-                "summary": "Получение статистики пользователя",
+                "summary": "Получение списка категорий",
                 "responses": {
                     "200": {
-                        "description": "Статистика пользователя",
+                        "description": "Список категорий",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserStats"
+                            "$ref": "#/definitions/dto.CategoriesListResponse"
                         }
                     },
                     "401": {
@@ -1091,357 +1118,4824 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "dto.AuthResponse": {
-            "type": "object",
-            "properties": {
-                "access_token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
-                },
-                "refresh_token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
-                },
-                "user": {
-                    "$ref": "#/definitions/dto.UserInfo"
-                }
-            }
-        },
-        "dto.BudgetResponse": {
-            "type": "object",
-            "properties": {
-                "amount": {
-                    "description": "Category   CategoryResponse ` + "`" + `json:\"category\"` + "`" + `",
-                    "type": "number"
-                },
-                "category_id": {
-                    "type": "integer"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "end_date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "period": {
-                    "type": "string"
-                },
-                "remaining_amount": {
-                    "type": "number"
-                },
-                "spent_amount": {
-                    "type": "number"
-                },
-                "start_date": {
-                    "type": "string"
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создание новой категории расходов для пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Создание новой категории",
+                "parameters": [
+                    {
+                        "description": "Данные для создания категории",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Категория успешно создана",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "dto.BudgetsListResponse": {
-            "type": "object",
-            "properties": {
-                "budgets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.BudgetResponse"
+        "/categories/analytics/{category_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение детальной аналитики расходов по категории за календарный период или произвольный диапазон дат. Среднее за день считается по фактически прошедшим дням периода",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Получение аналитики по категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD), вместо period",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD), вместо period",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аналитика по категории",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryAnalytics"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или период",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "dto.CategoriesListResponse": {
-            "type": "object",
-            "properties": {
-                "categories": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.CategoryResponse"
+        "/categories/top": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение списка категорий, отсортированных по частоте использования",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Получение наиболее используемых категорий",
+                "responses": {
+                    "200": {
+                        "description": "Список наиболее используемых категорий",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoriesListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "dto.CategoryAnalytics": {
+        "/categories/{category_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение информации о конкретной категории пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Получение категории по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о категории",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Категория не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление категории и всех связанных с ней расходов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Categories"
+                ],
+                "summary": "Удаление категории",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Категория успешно удалена",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/budgets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение всех бюджетов пользователя для указанной категории",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Получение списка бюджетов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список бюджетов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BudgetsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создание нового бюджета для указанной категории",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Создание нового бюджета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для создания бюджета",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateBudgetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BudgetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/budgets/{budget_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление конкретного бюджета пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Удаление бюджета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID бюджета",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет успешно удален",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Изменение суммы или периода бюджета. При смене периода дата окончания и потраченная сумма пересчитываются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "Обновление бюджета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID бюджета",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля бюджета",
+                        "name": "budget",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateBudgetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Бюджет успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BudgetResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории, бюджета или данные",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/budgets/{budget_id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Закрытые периоды автоматически продлеваемого бюджета с лимитом, перенесенной и потраченной суммой, начиная с последнего",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Budgets"
+                ],
+                "summary": "История периодов бюджета",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID бюджета",
+                        "name": "budget_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "История периодов бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BudgetHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или бюджета",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Бюджет не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/expenses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение расходов пользователя в указанной категории с фильтрами, сортировкой и постраничной выдачей. Параметр tags оставляет расходы, у которых есть хотя бы один из перечисленных тегов. Для следующей страницы передайте next_cursor из ответа в параметре cursor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Получение списка расходов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поиск по описанию без учета регистра",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Теги через запятую, например travel,work",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "date",
+                        "description": "Поле сортировки: date, amount, created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Направление сортировки: asc, desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Размер страницы (максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список расходов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExpensesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или параметры выборки",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создание нового расхода в указанной категории",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Создание нового расхода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для создания расхода",
+                        "name": "expense",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateExpenseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расход успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExpenseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/expenses/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение аналитики расходов по категории за календарный период или произвольный диапазон дат. Среднее за день считается по фактически прошедшим дням периода",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Получение аналитики расходов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD), вместо period",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD), вместо period",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аналитика расходов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExpenseAnalytics"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или период",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/expenses/{expense_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение информации о конкретном расходе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Получение расхода по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID расхода",
+                        "name": "expense_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о расходе",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExpenseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или расхода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление конкретного расхода пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Удаление расхода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID расхода",
+                        "name": "expense_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расход успешно удален",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или расхода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Частичное обновление расхода: категория, сумма, описание, дата и теги. Переданный список тегов заменяет текущий. Суммы в бюджетах пересчитываются в той же транзакции",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Обновление расхода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID расхода",
+                        "name": "expense_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля расхода",
+                        "name": "expense",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateExpenseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расход успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExpenseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории, расхода или данные",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/recurring": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение всех регулярных расходов пользователя в указанной категории (0 - во всех категориях)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecurringExpenses"
+                ],
+                "summary": "Получение списка регулярных расходов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список регулярных расходов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecurringExpensesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создание регулярного расхода в указанной категории. Поддерживаются расписания daily, weekly, monthly (в заданный день месяца) и yearly. Наступившие платежи автоматически превращаются в обычные расходы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecurringExpenses"
+                ],
+                "summary": "Создание регулярного расхода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные регулярного расхода",
+                        "name": "recurring",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRecurringExpenseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Регулярный расход успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecurringExpenseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{category_id}/recurring/{recurring_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение информации о регулярном расходе, включая дату следующего платежа",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecurringExpenses"
+                ],
+                "summary": "Получение регулярного расхода по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID регулярного расхода",
+                        "name": "recurring_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о регулярном расходе",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecurringExpenseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или регулярного расхода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Регулярный расход не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление регулярного расхода. Уже созданные по нему расходы сохраняются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecurringExpenses"
+                ],
+                "summary": "Удаление регулярного расхода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID регулярного расхода",
+                        "name": "recurring_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Регулярный расход успешно удален",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID категории или регулярного расхода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Частичное обновление регулярного расхода. Новое расписание применяется начиная с ближайшего еще не созданного платежа. is_active=false приостанавливает создание расходов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "RecurringExpenses"
+                ],
+                "summary": "Обновление регулярного расхода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID категории",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID регулярного расхода",
+                        "name": "recurring_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля регулярного расхода",
+                        "name": "recurring",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateRecurringExpenseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Регулярный расход успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecurringExpenseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID или данные",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/expenses": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение расходов пользователя во всех категориях с теми же фильтрами, сортировкой и постраничной выдачей, что и список расходов категории",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Получение расходов во всех категориях",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Минимальная сумма",
+                        "name": "min_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Максимальная сумма",
+                        "name": "max_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поиск по описанию без учета регистра",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Теги через запятую, например travel,work",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "date",
+                        "description": "Поле сортировки: date, amount, created_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Направление сортировки: asc, desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Размер страницы (максимум 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы из next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список расходов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExpensesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры выборки",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Категория и дополнительные теги расхода выбираются правилами категоризации (/rules).\nЕсли ни одно правило не подошло, расход попадает в категорию Uncategorized, которая создается при необходимости",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Expenses"
+                ],
+                "summary": "Создание расхода с автоматической категорией",
+                "parameters": [
+                    {
+                        "description": "Данные расхода",
+                        "name": "expense",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateExpenseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расход создан",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CategorizedExpenseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выгружает все категории, расходы и бюджеты пользователя файлом. Данные читаются из одного снимка базы во временный буфер и передаются после завершения чтения.\njson - объект dto.ExportResponse, подходит для резервной копии перед удалением аккаунта.\ncsv - разделы categories, expenses и budgets друг за другом: строка с названием раздела, заголовок колонок, записи.\nxlsx - книга Excel с листами categories, expenses и budgets, суммы записаны числами, даты - датами.\nfrom и to ограничивают расходы по дате и бюджеты по пересечению периода, категории выгружаются все",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Выгрузка данных пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "description": "Формат: csv, json, xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры выгрузки",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Последние загрузки пользователя, сначала новые",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "История загрузок выписок",
+                "responses": {
+                    "200": {
+                        "description": "Список загрузок",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportsListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Разбирает выписку CSV или OFX/QFX и возвращает предпросмотр: для каждого расхода предложенная по правилам категория\nи признак дубликата (расход с той же датой, суммой, валютой и описанием уже есть). Поступления пропускаются.\nРасходы не создаются, пока загрузка не подтверждена через POST /imports/{import_id}/commit",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Загрузка банковской выписки",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл выписки (.csv, .ofx, .qfx), до 5 МБ",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат: csv или ofx. По умолчанию по расширению файла",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "date",
+                        "description": "Колонка CSV с датой",
+                        "name": "date_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "amount",
+                        "description": "Колонка CSV с суммой",
+                        "name": "amount_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "description",
+                        "description": "Колонка CSV с описанием",
+                        "name": "description_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Колонка CSV с валютой, без нее - базовая валюта",
+                        "name": "currency_column",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "YYYY-MM-DD",
+                        "description": "Шаблон даты из YYYY, YY, MM, DD, HH, mm, ss",
+                        "name": "date_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": ",",
+                        "description": "Разделитель колонок CSV: символ или tab",
+                        "name": "delimiter",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": ".",
+                        "description": "Десятичный разделитель сумм: . или ,",
+                        "name": "decimal_separator",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "default": "negative",
+                        "description": "negative - расходы со знаком минус, positive - все строки расходы",
+                        "name": "expense_sign",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Категория для операций, к которым не подошло ни одно правило",
+                        "name": "default_category_id",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Предпросмотр загрузки",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportPreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Не удалось разобрать выписку",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{import_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Загрузка вместе с операциями: предложенные категории, дубликаты и созданные расходы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Загрузка выписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID загрузки",
+                        "name": "import_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Загрузка",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportPreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID загрузки",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Загрузка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "У подтвержденной загрузки удаляет созданные ею расходы и возвращает их суммы в бюджеты, загрузка остается в истории со статусом undone.\nНеподтвержденная загрузка удаляется вместе с предпросмотром",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Отмена загрузки выписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID загрузки",
+                        "name": "import_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Загрузка отменена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UndoImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID загрузки или загрузка уже отменена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/imports/{import_id}/commit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает расходы из строк загрузки в одной транзакции: при ошибке в любой строке не создается ни один расход.\nБез решений импортируются все строки, кроме дубликатов, с предложенной категорией, строки без категории - в Uncategorized.\nРешение по строке может пропустить ее (accept=false), принять дубликат (accept=true) или заменить категорию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Imports"
+                ],
+                "summary": "Подтверждение загрузки выписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID загрузки",
+                        "name": "import_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Решения по отдельным строкам",
+                        "name": "decisions",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CommitImportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Загрузка подтверждена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportPreviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные или загрузка уже подтверждена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incomes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение всех доходов пользователя, отсортированных по дате",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incomes"
+                ],
+                "summary": "Получение списка доходов",
+                "responses": {
+                    "200": {
+                        "description": "Список доходов",
+                        "schema": {
+                            "$ref": "#/definitions/dto.IncomesListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавление записи о доходе: источник, сумма, дата и описание",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incomes"
+                ],
+                "summary": "Создание дохода",
+                "parameters": [
+                    {
+                        "description": "Данные для создания дохода",
+                        "name": "income",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateIncomeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доход успешно создан",
+                        "schema": {
+                            "$ref": "#/definitions/dto.IncomeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incomes/{income_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение информации о конкретном доходе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incomes"
+                ],
+                "summary": "Получение дохода по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID дохода",
+                        "name": "income_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Информация о доходе",
+                        "schema": {
+                            "$ref": "#/definitions/dto.IncomeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID дохода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Доход не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаление конкретного дохода пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incomes"
+                ],
+                "summary": "Удаление дохода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID дохода",
+                        "name": "income_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доход успешно удален",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID дохода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Частичное обновление дохода: источник, сумма, описание и дата",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Incomes"
+                ],
+                "summary": "Обновление дохода",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID дохода",
+                        "name": "income_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля дохода",
+                        "name": "income",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateIncomeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доход успешно обновлен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.IncomeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID дохода или данные",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Правила пользователя в порядке применения",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Правила категоризации",
+                "responses": {
+                    "200": {
+                        "description": "Список правил",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RulesListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Расходы из выписки и из POST /expenses, которые подходят под все условия правила, получают его категорию и теги.\nУсловия: pattern в описании (match_type contains - подстрока, regex - регулярное выражение, регистр не важен) и сумма от min_amount до max_amount.\nЕсли подходят несколько правил, применяется правило с большим priority, при равном - созданное раньше",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Создание правила категоризации",
+                "parameters": [
+                    {
+                        "description": "Данные правила",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правило создано",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/apply": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заново применяет правила к расходам категории Uncategorized: подходящие расходы переносятся в категорию правила,\nполучают его теги, бюджеты пересчитываются. Все переносы выполняются в одной транзакции",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Применение правил к расходам без категории",
+                "responses": {
+                    "200": {
+                        "description": "Результат применения",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ApplyRulesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/test": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Показывает, какое правило сработает для примера операции, и в какую категорию с какими тегами попадет расход. Ничего не изменяет",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Проверка правил категоризации",
+                "parameters": [
+                    {
+                        "description": "Пример операции",
+                        "name": "sample",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TestRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат проверки",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TestRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка валидации данных",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/rules/{rule_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет правило. Уже созданные расходы не меняются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rules"
+                ],
+                "summary": "Удаление правила категоризации",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID правила",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Правило удалено",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID правила",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Правило не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/analytics": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сумма и количество расходов по каждому тегу во всех категориях. Расход с несколькими тегами учитывается в каждом из них",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Аналитика расходов по тегам",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Начало периода (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода включительно (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Расходы по тегам",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TagAnalyticsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры периода",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/account": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Полное удаление аккаунта пользователя и всех связанных данных. Все выданные токены отзываются. Резервную копию можно получить заранее через GET /export?format=json",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Удаление аккаунта пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Аккаунт успешно удален",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/base-currency": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет валюту, в которую пересчитываются расходы для аналитики и статистики, и пересчитывает все расходы пользователя по курсам на их даты.\nЕсли хотя бы для одного расхода нет курса, валюта не меняется. Лимиты бюджетов остаются в своих валютах",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Смена базовой валюты",
+                "parameters": [
+                    {
+                        "description": "Новая базовая валюта",
+                        "name": "currency",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateBaseCurrencyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Профиль пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный код валюты или нет курса",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение информации о текущем пользователе",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Получение профиля пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Профиль пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserProfile"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Устройства, на которых выполнен вход: User-Agent и IP последнего входа или обновления токенов, время начала сеанса и последнего использования",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Список сеансов",
+                "responses": {
+                    "200": {
+                        "description": "Действующие сеансы",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SessionsListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершение сеанса на устройстве: refresh токены сеанса удаляются, выданные в нем access токены отзываются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Завершение сеанса",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID сеанса",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сеанс завершен",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сеанс не найден",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Получение общей статистики по расходам, категориям и бюджетам",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Получение статистики пользователя",
+                "responses": {
+                    "200": {
+                        "description": "Статистика пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Требуется авторизация",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AppliedRule": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "expense_id": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "rule_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.ApplyRulesResponse": {
+            "type": "object",
+            "properties": {
+                "categorized": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "checked": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "expenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AppliedRule"
+                    }
+                }
+            }
+        },
+        "dto.AuthResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "user": {
+                    "$ref": "#/definitions/dto.UserInfo"
+                }
+            }
+        },
+        "dto.BudgetAlert": {
+            "type": "object",
+            "properties": {
+                "acknowledged": {
+                    "type": "boolean"
+                },
+                "acknowledged_at": {
+                    "type": "string"
+                },
+                "alert_type": {
+                    "description": "\"warning\", \"exceeded\", \"near_end\"",
+                    "type": "string",
+                    "example": "warning"
+                },
+                "budget_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "message": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number",
+                    "example": 83.5
+                },
+                "threshold": {
+                    "description": "Порог в процентах, 0 для near_end",
+                    "type": "integer",
+                    "example": 80
+                }
+            }
+        },
+        "dto.BudgetAlertsListResponse": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BudgetAlert"
+                    }
+                }
+            }
+        },
+        "dto.BudgetHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "budget_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "periods": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BudgetPeriodResponse"
+                    }
+                }
+            }
+        },
+        "dto.BudgetPeriodResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 500
+                },
+                "carried_amount": {
+                    "type": "number",
+                    "example": 25
+                },
+                "end_date": {
+                    "description": "Последний день периода, входит в период",
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "monthly"
+                },
+                "remaining_amount": {
+                    "type": "number",
+                    "example": 44.5
+                },
+                "spent_amount": {
+                    "type": "number",
+                    "example": 480.5
+                },
+                "spent_percentage": {
+                    "type": "number",
+                    "example": 91.52
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"ok\", \"warning\", \"exceeded\"",
+                    "type": "string",
+                    "example": "warning"
+                }
+            }
+        },
+        "dto.BudgetResponse": {
+            "type": "object",
+            "properties": {
+                "alert_thresholds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "amount": {
+                    "description": "Category   CategoryResponse ` + "`" + `json:\"category\"` + "`" + `",
+                    "type": "number"
+                },
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "carried_amount": {
+                    "description": "Перенесено из предыдущего периода, лимит текущего периода равен amount + carried_amount",
+                    "type": "number"
+                },
+                "carry_over": {
+                    "type": "boolean"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Валюта лимита и потраченной суммы",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "end_date": {
+                    "description": "Последний день текущего периода, входит в период",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "month_start_day": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Еда"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "scope": {
+                    "description": "category - бюджет одной категории, group - группы категорий category_ids, overall - всех расходов",
+                    "type": "string",
+                    "example": "group"
+                },
+                "spent_amount": {
+                    "type": "number"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "week_start": {
+                    "type": "integer"
+                },
+                "year_start_month": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.BudgetStatus": {
+            "type": "object",
+            "properties": {
+                "budget_amount": {
+                    "type": "number"
+                },
+                "budget_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "days_remaining": {
+                    "type": "integer"
+                },
+                "remaining_amount": {
+                    "type": "number"
+                },
+                "spent_amount": {
+                    "type": "number"
+                },
+                "spent_percentage": {
+                    "type": "number"
+                },
+                "status": {
+                    "description": "\"ok\", \"warning\", \"exceeded\"",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.BudgetStatusListResponse": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BudgetStatus"
+                    }
+                }
+            }
+        },
+        "dto.BudgetsListResponse": {
+            "type": "object",
+            "properties": {
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BudgetResponse"
+                    }
+                }
+            }
+        },
+        "dto.CashFlowBucket": {
+            "type": "object",
+            "properties": {
+                "expenses": {
+                    "type": "number"
+                },
+                "income": {
+                    "type": "number"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CashFlowResponse": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CashFlowBucket"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "granularity": {
+                    "type": "string"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_expenses": {
+                    "type": "number"
+                },
+                "total_income": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CategoriesListResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryResponse"
+                    }
+                }
+            }
+        },
+        "dto.CategorizedExpenseResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Category     CategoryResponse ` + "`" + `json:\"category,omitempty\"` + "`" + `",
+                    "type": "number"
+                },
+                "base_amount": {
+                    "description": "Сумма в базовой валюте пользователя по курсу на дату расхода",
+                    "type": "number",
+                    "example": 2345.6
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "rule_id": {
+                    "description": "Правило, по которому выбраны категория и теги. Пусто, если расход попал в Uncategorized",
+                    "type": "integer",
+                    "example": 1
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CategoryAnalytics": {
+            "type": "object",
+            "properties": {
+                "average_expense_amount": {
+                    "type": "number"
+                },
+                "average_per_day": {
+                    "type": "number"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "expenses_count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "largest_expense": {
+                    "$ref": "#/definitions/dto.ExpenseResponse"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "smallest_expense": {
+                    "$ref": "#/definitions/dto.ExpenseResponse"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CategoryExpense": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CategoryResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Description *string   ` + "`" + `json:\"description\"` + "`" + `",
+                    "type": "string"
+                },
+                "expenses_count": {
+                    "description": "Дополнительная информация",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.CategorySharesResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryExpense"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "min_share": {
+                    "type": "number",
+                    "example": 3
+                },
+                "other_categories_count": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "period": {
+                    "type": "string",
+                    "example": "2024-03"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number",
+                    "example": 2450.75
+                }
+            }
+        },
+        "dto.CategoryTrend": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Продукты"
+                },
+                "compared_to_prev": {
+                    "description": "Сравнение с предыдущим периодом: разница сумм",
+                    "type": "number",
+                    "example": 180
+                },
+                "current_amount": {
+                    "type": "number",
+                    "example": 1180
+                },
+                "growth_rate": {
+                    "description": "Процент изменения, null если в предыдущем периоде расходов не было",
+                    "type": "number",
+                    "example": 18
+                },
+                "last_year_amount": {
+                    "description": "Тот же период годом раньше",
+                    "type": "number",
+                    "example": 950
+                },
+                "previous_amount": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "trend": {
+                    "description": "\"increasing\", \"decreasing\", \"stable\"",
+                    "type": "string",
+                    "example": "increasing"
+                },
+                "year_over_year_rate": {
+                    "description": "Процент изменения к прошлому году, null если расходов не было",
+                    "type": "number",
+                    "example": 24.21
+                },
+                "year_over_year_trend": {
+                    "type": "string",
+                    "example": "increasing"
+                }
+            }
+        },
+        "dto.CommitImportRequest": {
+            "type": "object",
+            "properties": {
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportRowDecision"
+                    }
+                }
+            }
+        },
+        "dto.ComparedPeriod": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "2024-02"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreateBudgetRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "period"
+            ],
+            "properties": {
+                "alert_thresholds": {
+                    "description": "Пороги уведомлений в процентах расходования, по умолчанию 80 и 100",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        80,
+                        100
+                    ]
+                },
+                "amount": {
+                    "description": "CategoryID uint       ` + "`" + `json:\"category_id\" validate:\"required\"` + "`" + `",
+                    "type": "number",
+                    "example": 500
+                },
+                "auto_renew": {
+                    "description": "Продлевать бюджет на следующий период по окончании текущего, по умолчанию true (для custom - false)",
+                    "type": "boolean",
+                    "example": true
+                },
+                "carry_over": {
+                    "description": "Переносить остаток (или перерасход) в следующий период",
+                    "type": "boolean",
+                    "example": false
+                },
+                "category_ids": {
+                    "description": "Только для POST /budgets: категории группового бюджета. Если не заданы, бюджет учитывает все расходы",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2
+                    ]
+                },
+                "currency": {
+                    "description": "Валюта лимита бюджета (код ISO 4217), по умолчанию базовая валюта пользователя",
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "end_date": {
+                    "description": "Последний день периода custom, входит в период",
+                    "type": "string",
+                    "example": "2026-11-30T00:00:00Z"
+                },
+                "month_start_day": {
+                    "description": "День начала месяца для monthly и yearly, например день зарплаты. По умолчанию 1.\nЕсли в месяце меньше дней, период начинается в последний день месяца",
+                    "type": "integer",
+                    "example": 25
+                },
+                "name": {
+                    "description": "Название бюджета, например \"Еда\" для группы или \"Всего за месяц\" для общего бюджета",
+                    "type": "string",
+                    "example": "Еда"
+                },
+                "period": {
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "yearly",
+                        "custom"
+                    ],
+                    "example": "monthly"
+                },
+                "start_date": {
+                    "description": "Для weekly, monthly и yearly - дата, период которой станет первым (по умолчанию текущий период).\nДля custom - начало периода",
+                    "type": "string",
+                    "example": "2026-11-01T00:00:00Z"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "week_start": {
+                    "description": "День начала недели для weekly: 0 - воскресенье, 1 - понедельник (по умолчанию), ..., 6 - суббота",
+                    "type": "integer",
+                    "example": 1
+                },
+                "year_start_month": {
+                    "description": "Месяц начала финансового года для yearly, по умолчанию 1 (январь)",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "dto.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "category_name"
+            ],
+            "properties": {
+                "category_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "dto.CreateExpenseRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "date"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "CategoryID  uint      ` + "`" + `json:\"category_id\" validate:\"required\"` + "`" + `",
+                    "type": "number",
+                    "example": 25.5
+                },
+                "currency": {
+                    "description": "Валюта расхода (код ISO 4217), по умолчанию базовая валюта пользователя",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.CreateIncomeRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "date",
+                "source"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 3200
+                },
+                "currency": {
+                    "description": "Валюта дохода (код ISO 4217), по умолчанию базовая валюта пользователя",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-10T10:00:00Z"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "source": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Зарплата"
+                }
+            }
+        },
+        "dto.CreateRecurringExpenseRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "frequency",
+                "start_date"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 1200
+                },
+                "day_of_month": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1,
+                    "example": 5
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Аренда квартиры"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ],
+                    "example": "monthly"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "2024-01-05T09:00:00Z"
+                }
+            }
+        },
+        "dto.CreateRuleRequest": {
+            "type": "object",
+            "required": [
+                "category_id"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "match_type": {
+                    "description": "Как сравнивать pattern с описанием: contains - подстрока, regex - регулярное выражение RE2. Регистр не важен",
+                    "type": "string",
+                    "example": "contains"
+                },
+                "max_amount": {
+                    "type": "number",
+                    "example": 500
+                },
+                "min_amount": {
+                    "description": "Границы суммы операции в ее валюте включительно",
+                    "type": "number",
+                    "example": 100
+                },
+                "pattern": {
+                    "description": "Пустой pattern подходит к любому описанию, тогда нужен диапазон суммы",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "coffee"
+                },
+                "priority": {
+                    "description": "Из нескольких подходящих правил применяется правило с большим приоритетом",
+                    "type": "integer",
+                    "example": 10
+                },
+                "tags": {
+                    "description": "Теги, которые добавляются к расходу вместе с категорией",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "coffee",
+                        "work"
+                    ]
+                }
+            }
+        },
+        "dto.DayExpense": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Email is required"
+                }
+            }
+        },
+        "dto.ExchangeRateInput": {
+            "type": "object",
+            "required": [
+                "base_currency",
+                "date",
+                "quote_currency",
+                "rate"
+            ],
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-01-15"
+                },
+                "quote_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 92.5
+                }
+            }
+        },
+        "dto.ExpenseAnalytics": {
+            "type": "object",
+            "properties": {
+                "average_expense_amount": {
+                    "type": "number"
+                },
+                "average_per_day": {
+                    "type": "number"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "expenses_count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "largest_expense": {
+                    "$ref": "#/definitions/dto.ExpenseResponse"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "smallest_expense": {
+                    "$ref": "#/definitions/dto.ExpenseResponse"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.ExpenseResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Category     CategoryResponse ` + "`" + `json:\"category,omitempty\"` + "`" + `",
+                    "type": "number"
+                },
+                "base_amount": {
+                    "description": "Сумма в базовой валюте пользователя по курсу на дату расхода",
+                    "type": "number",
+                    "example": 2345.6
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ExpenseTrends": {
+            "type": "object",
+            "properties": {
+                "compared_to_prev": {
+                    "description": "Сравнение с предыдущим периодом: разница сумм",
+                    "type": "number",
+                    "example": 180
+                },
+                "current_amount": {
+                    "type": "number",
+                    "example": 1180
+                },
+                "growth_rate": {
+                    "description": "Процент изменения, null если в предыдущем периоде расходов не было",
+                    "type": "number",
+                    "example": 18
+                },
+                "last_year_amount": {
+                    "description": "Тот же период годом раньше",
+                    "type": "number",
+                    "example": 950
+                },
+                "previous_amount": {
+                    "type": "number",
+                    "example": 1000
+                },
+                "trend": {
+                    "description": "\"increasing\", \"decreasing\", \"stable\"",
+                    "type": "string",
+                    "example": "increasing"
+                },
+                "year_over_year_rate": {
+                    "description": "Процент изменения к прошлому году, null если расходов не было",
+                    "type": "number",
+                    "example": 24.21
+                },
+                "year_over_year_trend": {
+                    "type": "string",
+                    "example": "increasing"
+                }
+            }
+        },
+        "dto.ExpensesListResponse": {
+            "type": "object",
+            "properties": {
+                "expenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ExpenseResponse"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor передается в параметре cursor для получения следующей страницы. Пустой, если страница последняя",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExportBudget": {
+            "type": "object",
+            "properties": {
+                "alert_thresholds": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "amount": {
+                    "type": "number",
+                    "example": 15000
+                },
+                "auto_renew": {
+                    "type": "boolean"
+                },
+                "carried_amount": {
+                    "type": "number",
+                    "example": 0
+                },
+                "carry_over": {
+                    "type": "boolean"
+                },
+                "category_id": {
+                    "description": "Категория бюджета со scope = category",
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_ids": {
+                    "description": "Категории бюджета со scope = group",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Кафе"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Еда"
+                },
+                "period": {
+                    "type": "string",
+                    "example": "monthly"
+                },
+                "scope": {
+                    "description": "category, group, overall",
+                    "type": "string",
+                    "example": "category"
+                },
+                "spent_amount": {
+                    "type": "number",
+                    "example": 4200.5
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExportCategory": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Кафе"
+                }
+            }
+        },
+        "dto.ExportExpense": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "base_amount": {
+                    "description": "Сумма в базовой валюте пользователя по курсу на дату расхода",
+                    "type": "number",
+                    "example": 1187.5
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Кафе"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Кофе"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.ExportResponse": {
+            "type": "object",
+            "properties": {
+                "base_currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "budgets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ExportBudget"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ExportCategory"
+                    }
+                },
+                "expenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ExportExpense"
+                    }
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-01-01"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-01-31"
+                }
+            }
+        },
+        "dto.ImportBatchResponse": {
+            "type": "object",
+            "properties": {
+                "committed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string",
+                    "example": "statement.csv"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "csv"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "imported_count": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "rows_count": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "skipped_count": {
+                    "description": "поступления и нулевые операции",
+                    "type": "integer",
+                    "example": 3
+                },
+                "status": {
+                    "description": "preview, committed, undone",
+                    "type": "string",
+                    "example": "preview"
+                },
+                "undone_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ImportExchangeRatesRequest": {
+            "type": "object",
+            "required": [
+                "rates"
+            ],
+            "properties": {
+                "rates": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.ExchangeRateInput"
+                    }
+                }
+            }
+        },
+        "dto.ImportExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "recalculated_budgets": {
+                    "description": "Бюджеты, потраченная сумма которых пересчитана",
+                    "type": "integer",
+                    "example": 4
+                },
+                "recalculated_expenses": {
+                    "description": "Расходы, сумма которых в базовой валюте пересчитана по новым курсам",
+                    "type": "integer",
+                    "example": 12
+                },
+                "recalculated_incomes": {
+                    "description": "Доходы, сумма которых в базовой валюте пересчитана по новым курсам",
+                    "type": "integer",
+                    "example": 3
+                },
+                "skipped_budgets": {
+                    "description": "Бюджеты, которые не пересчитаны: для части их расходов нет курса к валюте бюджета",
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "dto.ImportPreviewResponse": {
+            "type": "object",
+            "properties": {
+                "duplicates": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "import": {
+                    "$ref": "#/definitions/dto.ImportBatchResponse"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportRowResponse"
+                    }
+                },
+                "uncategorized": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "dto.ImportRowDecision": {
+            "type": "object",
+            "required": [
+                "row"
+            ],
+            "properties": {
+                "accept": {
+                    "description": "false - не импортировать строку, true - импортировать, даже если это дубликат",
+                    "type": "boolean",
+                    "example": true
+                },
+                "category_id": {
+                    "description": "заменяет предложенную категорию",
+                    "type": "integer",
+                    "example": 5
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "dto.ImportRowResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "example": 250
+                },
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Кафе"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "COFFEE HOUSE"
+                },
+                "duplicate": {
+                    "description": "Такой же расход (дата, сумма, валюта, описание) уже есть. По умолчанию дубликаты не импортируются",
+                    "type": "boolean"
+                },
+                "duplicate_expense_id": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "expense_id": {
+                    "description": "расход, созданный из строки",
+                    "type": "integer",
+                    "example": 150
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rule_id": {
+                    "description": "правило, по которому предложена категория",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.ImportsListResponse": {
+            "type": "object",
+            "properties": {
+                "imports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportBatchResponse"
+                    }
+                }
+            }
+        },
+        "dto.IncomeResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "base_amount": {
+                    "description": "Сумма в базовой валюте пользователя по курсу на дату дохода",
+                    "type": "number",
+                    "example": 3200
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.IncomesListResponse": {
+            "type": "object",
+            "properties": {
+                "incomes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.IncomeResponse"
+                    }
+                }
+            }
+        },
+        "dto.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "description": "UserID   uint   ` + "`" + `json:\"id\"` + "`" + `",
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "dto.MonthExpense": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "month": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.RecalculateBudgetsResponse": {
+            "type": "object",
+            "properties": {
+                "skipped_budgets": {
+                    "description": "Бюджеты, которые не пересчитаны: для части их расходов нет курса к валюте бюджета",
+                    "type": "integer",
+                    "example": 0
+                },
+                "updated_budgets": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "dto.RecurringExpenseResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "day_of_month": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "next_run_at": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RecurringExpensesListResponse": {
+            "type": "object",
+            "properties": {
+                "recurring_expenses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RecurringExpenseResponse"
+                    }
+                }
+            }
+        },
+        "dto.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RegisterRequest": {
+            "type": "object",
+            "required": [
+                "confirm_password",
+                "email",
+                "first_name",
+                "last_name",
+                "password"
+            ],
+            "properties": {
+                "confirm_password": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "John"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 2,
+                    "example": "Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 8,
+                    "example": "password123"
+                },
+                "time_of_registration": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                }
+            }
+        },
+        "dto.RuleResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Кафе"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "match_type": {
+                    "type": "string",
+                    "example": "contains"
+                },
+                "max_amount": {
+                    "type": "number",
+                    "example": 500
+                },
+                "min_amount": {
+                    "type": "number",
+                    "example": 100
+                },
+                "pattern": {
+                    "type": "string",
+                    "example": "coffee"
+                },
+                "priority": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "coffee",
+                        "work"
+                    ]
+                }
+            }
+        },
+        "dto.RulesListResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RuleResponse"
+                    }
+                }
+            }
+        },
+        "dto.SessionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "current": {
+                    "description": "Current - сеанс, которому принадлежит access-токен запроса",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-02-15T08:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "3f9a1c0e5b7d2a4c6e8f0a1b2c3d4e5f"
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "last_used_at": {
+                    "type": "string",
+                    "example": "2024-01-16T08:00:00Z"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"
+                }
+            }
+        },
+        "dto.SessionsListResponse": {
+            "type": "object",
+            "properties": {
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.SessionResponse"
+                    }
+                }
+            }
+        },
+        "dto.TagAnalyticsResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TagSummary"
+                    }
+                }
+            }
+        },
+        "dto.TagSummary": {
+            "type": "object",
+            "properties": {
+                "categories_count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "expenses_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "tag": {
+                    "type": "string",
+                    "example": "командировка"
+                },
+                "total_amount": {
+                    "type": "number",
+                    "example": 845.3
+                }
+            }
+        },
+        "dto.TestRuleRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Без суммы правила с диапазоном суммы не подходят",
+                    "type": "number",
+                    "example": 250
+                },
+                "description": {
+                    "type": "string",
+                    "example": "COFFEE HOUSE 123"
+                }
+            }
+        },
+        "dto.TestRuleResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "description": "Категория, в которую попадет расход. Без подходящего правила - Uncategorized",
+                    "type": "integer",
+                    "example": 3
+                },
+                "category_name": {
+                    "type": "string",
+                    "example": "Кафе"
+                },
+                "matched": {
+                    "type": "boolean"
+                },
+                "rule": {
+                    "$ref": "#/definitions/dto.RuleResponse"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "coffee",
+                        "work"
+                    ]
+                }
+            }
+        },
+        "dto.TimeSeriesResponse": {
             "type": "object",
             "properties": {
-                "average_expense_amount": {
-                    "type": "number"
-                },
-                "average_per_day": {
-                    "type": "number"
-                },
                 "category_id": {
                     "type": "integer"
                 },
-                "category_name": {
+                "from": {
                     "type": "string"
                 },
-                "expenses_count": {
-                    "type": "integer"
-                },
-                "largest_expense": {
-                    "$ref": "#/definitions/dto.ExpenseResponse"
+                "granularity": {
+                    "type": "string",
+                    "example": "day"
                 },
-                "period": {
-                    "type": "string"
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MonthExpense"
+                    }
                 },
-                "smallest_expense": {
-                    "$ref": "#/definitions/dto.ExpenseResponse"
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DayExpense"
+                    }
                 },
-                "total_amount": {
-                    "type": "number"
+                "to": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CategoryPeriod": {
+        "dto.TokenResponse": {
             "type": "object",
             "properties": {
-                "period": {
-                    "type": "string"
+                "access_token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2024-01-16T10:30:00Z"
+                },
+                "refresh_token": {
+                    "type": "string",
+                    "example": "pY3s8m2nQ0bK..."
                 }
             }
         },
-        "dto.CategoryResponse": {
+        "dto.TrendsResponse": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "description": "Description *string   ` + "`" + `json:\"description\"` + "`" + `",
-                    "type": "string"
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryTrend"
+                    }
                 },
-                "expenses_count": {
-                    "description": "Дополнительная информация",
-                    "type": "integer"
+                "current": {
+                    "$ref": "#/definitions/dto.ComparedPeriod"
                 },
-                "id": {
-                    "type": "integer"
+                "last_year": {
+                    "$ref": "#/definitions/dto.ComparedPeriod"
                 },
-                "name": {
-                    "type": "string"
+                "overall": {
+                    "$ref": "#/definitions/dto.ExpenseTrends"
                 },
-                "total_amount": {
-                    "type": "number"
+                "previous": {
+                    "$ref": "#/definitions/dto.ComparedPeriod"
+                },
+                "threshold": {
+                    "description": "Изменение в процентах, до которого тренд считается стабильным",
+                    "type": "number",
+                    "example": 5
                 }
             }
         },
-        "dto.CreateBudgetRequest": {
+        "dto.UndoImportResponse": {
             "type": "object",
-            "required": [
-                "amount",
-                "period"
-            ],
             "properties": {
-                "amount": {
-                    "description": "CategoryID uint       ` + "`" + `json:\"category_id\" validate:\"required\"` + "`" + `",
-                    "type": "number",
-                    "example": 500
-                },
-                "period": {
-                    "type": "string",
-                    "enum": [
-                        "weekly",
-                        "monthly",
-                        "yearly"
-                    ],
-                    "example": "monthly"
+                "import_id": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "user_id": {
-                    "type": "integer"
+                "removed_expenses": {
+                    "type": "integer",
+                    "example": 39
                 }
             }
         },
-        "dto.CreateCategoryRequest": {
+        "dto.UpdateBaseCurrencyRequest": {
             "type": "object",
             "required": [
-                "category_name"
+                "currency"
             ],
             "properties": {
-                "category_name": {
+                "currency": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 1
+                    "example": "USD"
                 }
             }
         },
-        "dto.CreateExpenseRequest": {
+        "dto.UpdateBudgetRequest": {
             "type": "object",
-            "required": [
-                "amount",
-                "date"
-            ],
             "properties": {
+                "alert_thresholds": {
+                    "description": "Пороги уведомлений в процентах расходования, если не переданы - не меняются",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        50,
+                        90,
+                        100
+                    ]
+                },
                 "amount": {
-                    "description": "CategoryID  uint      ` + "`" + `json:\"category_id\" validate:\"required\"` + "`" + `",
                     "type": "number",
-                    "example": 25.5
+                    "example": 750
                 },
-                "date": {
-                    "type": "string",
-                    "example": "2024-01-15T10:30:00Z"
+                "auto_renew": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "description": {
-                    "type": "string",
-                    "maxLength": 500
+                "carry_over": {
+                    "type": "boolean",
+                    "example": true
                 },
-                "tags": {
+                "category_ids": {
+                    "description": "Новый набор категорий общего или группового бюджета, пустой список - все расходы",
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    }
-                }
-            }
-        },
-        "dto.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        5
+                    ]
                 },
-                "error": {
+                "currency": {
+                    "description": "Смена валюты пересчитывает потраченную сумму по курсам на даты расходов, лимит не пересчитывается",
                     "type": "string",
-                    "example": "Validation failed"
+                    "example": "EUR"
                 },
-                "message": {
+                "end_date": {
+                    "description": "Последний день периода custom, входит в период",
                     "type": "string",
-                    "example": "Email is required"
-                }
-            }
-        },
-        "dto.ExpenseAnalytics": {
-            "type": "object",
-            "properties": {
-                "average_expense_amount": {
-                    "type": "number"
-                },
-                "average_per_day": {
-                    "type": "number"
+                    "example": "2026-11-30T00:00:00Z"
                 },
-                "expenses_count": {
-                    "type": "integer"
+                "month_start_day": {
+                    "type": "integer",
+                    "example": 25
                 },
-                "largest_expense": {
-                    "$ref": "#/definitions/dto.ExpenseResponse"
+                "name": {
+                    "type": "string",
+                    "example": "Еда"
                 },
                 "period": {
-                    "type": "string"
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "yearly",
+                        "custom"
+                    ],
+                    "example": "weekly"
                 },
-                "smallest_expense": {
-                    "$ref": "#/definitions/dto.ExpenseResponse"
+                "start_date": {
+                    "description": "Изменение периода или точек отсчета пересчитывает границы текущего периода от его начала",
+                    "type": "string",
+                    "example": "2026-11-01T00:00:00Z"
                 },
-                "total_amount": {
-                    "type": "number"
-                }
-            }
-        },
-        "dto.ExpensePeriod": {
-            "type": "object",
-            "properties": {
-                "period": {
-                    "type": "string"
+                "week_start": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "year_start_month": {
+                    "type": "integer",
+                    "example": 4
                 }
             }
         },
-        "dto.ExpenseResponse": {
+        "dto.UpdateExpenseRequest": {
             "type": "object",
             "properties": {
                 "amount": {
-                    "description": "Category     CategoryResponse ` + "`" + `json:\"category,omitempty\"` + "`" + `",
                     "type": "number"
                 },
                 "category_id": {
                     "type": "integer"
                 },
-                "category_name": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
                 },
                 "date": {
                     "type": "string"
                 },
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 500
                 },
-                "id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "dto.ExpensesListResponse": {
-            "type": "object",
-            "properties": {
-                "expenses": {
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/dto.ExpenseResponse"
+                        "type": "string"
                     }
                 }
             }
         },
-        "dto.LoginRequest": {
+        "dto.UpdateIncomeRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "description": "UserID   uint   ` + "`" + `json:\"id\"` + "`" + `",
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
                     "type": "string",
-                    "example": "user@example.com"
+                    "example": "USD"
                 },
-                "password": {
+                "date": {
+                    "type": "string"
+                },
+                "description": {
                     "type": "string",
-                    "example": "password123"
+                    "maxLength": 500
+                },
+                "source": {
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
-        "dto.RegisterRequest": {
+        "dto.UpdateRecurringExpenseRequest": {
             "type": "object",
-            "required": [
-                "confirm_password",
-                "email",
-                "first_name",
-                "last_name",
-                "password"
-            ],
             "properties": {
-                "confirm_password": {
-                    "type": "string"
+                "amount": {
+                    "type": "number"
                 },
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
+                "day_of_month": {
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1
                 },
-                "first_name": {
+                "description": {
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2,
-                    "example": "John"
+                    "maxLength": 500
                 },
-                "last_name": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 2,
-                    "example": "Doe"
+                "end_date": {
+                    "type": "string"
                 },
-                "password": {
+                "frequency": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 8,
-                    "example": "password123"
+                    "enum": [
+                        "daily",
+                        "weekly",
+                        "monthly",
+                        "yearly"
+                    ]
                 },
-                "time_of_registration": {
-                    "type": "string",
-                    "example": "2024-01-15T10:30:00Z"
+                "is_active": {
+                    "type": "boolean"
                 }
             }
         },
@@ -1469,6 +5963,11 @@ const docTemplate = `{
         "dto.UserProfile": {
             "type": "object",
             "properties": {
+                "base_currency": {
+                    "description": "Валюта, в которую пересчитываются расходы для аналитики и статистики",
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2024-01-15T10:30:00Z"
@@ -1490,10 +5989,20 @@ const docTemplate = `{
         "dto.UserStats": {
             "type": "object",
             "properties": {
+                "currency": {
+                    "description": "Базовая валюта, в которой посчитаны суммы",
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "monthly_expenses": {
                     "type": "number",
                     "example": 450.75
                 },
+                "savings_rate": {
+                    "description": "Доля сэкономленного дохода в процентах",
+                    "type": "number",
+                    "example": 18.5
+                },
                 "total_budgets": {
                     "type": "integer",
                     "example": 3
@@ -1506,6 +6015,10 @@ const docTemplate = `{
                     "type": "number",
                     "example": 1250.5
                 },
+                "total_income": {
+                    "type": "number",
+                    "example": 3200
+                },
                 "weekly_expenses": {
                     "type": "number",
                     "example": 125.25
@@ -1514,6 +6027,12 @@ const docTemplate = `{
         }
     },
     "securityDefinitions": {
+        "AdminToken": {
+            "description": "Значение переменной окружения ADMIN_TOKEN.",
+            "type": "apiKey",
+            "name": "X-Admin-Token",
+            "in": "header"
+        },
         "BearerAuth": {
             "description": "Type \"Bearer\" followed by a space and JWT token.",
             "type": "apiKey",
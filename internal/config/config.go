@@ -4,6 +4,7 @@ import (
 	"finance/pkg/logger"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
@@ -30,6 +31,18 @@ func SetConfig() (Config, error) {
 	return DB_config_path, nil
 }
 
+// TrustedProxies возвращает адреса и подсети из TRUSTED_PROXIES (через запятую), которым разрешено передавать
+// IP клиента в X-Forwarded-For и X-Real-IP. Пустое значение - не доверять заголовкам, IP берется из соединения
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 type ConfigServer struct {
 	Port string `yaml:"port"`
 }
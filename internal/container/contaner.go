@@ -6,10 +6,25 @@ import (
 	//"finance/internal/config"
 	"finance/internal/handler"
 	"finance/internal/repositories"
+	"finance/internal/scheduler"
 	"finance/internal/services"
 	storage "finance/internal/storages"
 	"finance/internal/storages/database"
 	"finance/pkg/logger"
+	"time"
+)
+
+const (
+	// RecurringExpensesInterval - как часто планировщик проверяет наступившие регулярные расходы
+	RecurringExpensesInterval = time.Minute
+	// BudgetAlertsInterval - как часто планировщик ищет бюджеты, период которых скоро закончится
+	BudgetAlertsInterval = time.Hour
+	// BudgetRolloverInterval - как часто планировщик продлевает бюджеты с закончившимся периодом
+	BudgetRolloverInterval = time.Hour
+	// RevokedTokensInterval - как часто кэш отозванных токенов подтягивает отзывы других экземпляров сервера
+	RevokedTokensInterval = time.Minute
+	// RefreshTokensInterval - как часто планировщик удаляет истекшие refresh-токены
+	RefreshTokensInterval = time.Hour
 )
 
 type Container struct {
@@ -19,6 +34,7 @@ type Container struct {
 	Repositories *repositories.Repositories
 	Services     *services.Services
 	Handlers     *handler.Handlers
+	Scheduler    *scheduler.Scheduler
 }
 
 func NewContainer() (*Container, error) {
@@ -44,6 +60,53 @@ func NewContainer() (*Container, error) {
 	services := services.NewServices(repositories)
 	handlers := handler.NewHandlers(services)
 
+	jobs := scheduler.NewScheduler()
+	jobs.AddJob("recurring_expenses", RecurringExpensesInterval, func(ctx context.Context) error {
+		created, err := services.RecurringExpenseServiceInterface.ProcessDueRecurringExpenses(ctx, time.Now())
+		if created > 0 {
+			log.Info("Recurring expenses materialized", map[string]interface{}{
+				"created": created,
+			})
+		}
+		return err
+	})
+	jobs.AddJob("budget_rollover", BudgetRolloverInterval, func(ctx context.Context) error {
+		rolled, err := services.BudgetServiceInterface.RolloverDueBudgets(ctx, time.Now())
+		if rolled > 0 {
+			log.Info("Budget periods rolled over", map[string]interface{}{
+				"rolled": rolled,
+			})
+		}
+		return err
+	})
+	jobs.AddJob("budget_alerts", BudgetAlertsInterval, func(ctx context.Context) error {
+		created, err := services.BudgetAlertServiceInterface.CreateNearEndAlerts(ctx, time.Now())
+		if created > 0 {
+			log.Info("Budget near end alerts created", map[string]interface{}{
+				"created": created,
+			})
+		}
+		return err
+	})
+	jobs.AddJob("revoked_tokens", RevokedTokensInterval, func(ctx context.Context) error {
+		deleted, err := services.AuthServiceInterface.SyncRevokedTokens(ctx)
+		if deleted > 0 {
+			log.Info("Expired revoked tokens deleted", map[string]interface{}{
+				"deleted": deleted,
+			})
+		}
+		return err
+	})
+	jobs.AddJob("refresh_tokens", RefreshTokensInterval, func(ctx context.Context) error {
+		deleted, err := services.AuthServiceInterface.DeleteExpiredRefreshTokens(ctx)
+		if deleted > 0 {
+			log.Info("Expired refresh tokens deleted", map[string]interface{}{
+				"deleted": deleted,
+			})
+		}
+		return err
+	})
+
 	return &Container{
 		//Config: cfg,
 		DB:           DB,
@@ -51,6 +114,7 @@ func NewContainer() (*Container, error) {
 		Repositories: repositories,
 		Services:     services,
 		Handlers:     handlers,
+		Scheduler:    jobs,
 	}, nil
 }
 
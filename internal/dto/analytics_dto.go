@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// TimeSeriesRequest - параметры временного ряда расходов
+type TimeSeriesRequest struct {
+	Granularity string    `form:"granularity" example:"day"`
+	From        time.Time `form:"from" time_format:"2006-01-02" example:"2024-01-01"`
+	To          time.Time `form:"to" time_format:"2006-01-02" example:"2024-01-31"`
+	CategoryID  int       `form:"category_id" example:"1"`
+}
+
+// TimeSeriesResponse - расходы по интервалам. Для day и week заполняется points, для month - months
+type TimeSeriesResponse struct {
+	Granularity string         `json:"granularity" example:"day"`
+	From        time.Time      `json:"from"`
+	To          time.Time      `json:"to"`
+	CategoryID  int            `json:"category_id,omitempty"`
+	Points      []DayExpense   `json:"points,omitempty"`
+	Months      []MonthExpense `json:"months,omitempty"`
+}
+
+// TrendsRequest - период и порог стабильности для трендов расходов
+type TrendsRequest struct {
+	Period     string    `form:"period" example:"monthly"`
+	From       time.Time `form:"from" time_format:"2006-01-02" example:"2024-03-01"`
+	To         time.Time `form:"to" time_format:"2006-01-02" example:"2024-03-31"`
+	CategoryID int       `form:"category_id" example:"1"`
+	Threshold  *float64  `form:"threshold" example:"5"`
+}
+
+// ComparedPeriod - границы периода сравнения, to включительно
+type ComparedPeriod struct {
+	Period string    `json:"period" example:"2024-02"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// CategoryTrend - тренд расходов по одной категории
+type CategoryTrend struct {
+	CategoryID   uint   `json:"category_id" example:"1"`
+	CategoryName string `json:"category_name" example:"Продукты"`
+	ExpenseTrends
+}
+
+// TrendsResponse - сравнение расходов с предыдущим периодом и тем же периодом прошлого года.
+// Если текущий период еще не закончился, все периоды сравниваются по одинаковому числу прошедших дней
+type TrendsResponse struct {
+	Current    ComparedPeriod  `json:"current"`
+	Previous   ComparedPeriod  `json:"previous"`
+	LastYear   ComparedPeriod  `json:"last_year"`
+	Threshold  float64         `json:"threshold" example:"5"` // Изменение в процентах, до которого тренд считается стабильным
+	Overall    ExpenseTrends   `json:"overall"`
+	Categories []CategoryTrend `json:"categories"`
+}
+
+// CategorySharesRequest - период и порог для структуры расходов по категориям
+type CategorySharesRequest struct {
+	Period   string    `form:"period" example:"monthly"`
+	From     time.Time `form:"from" time_format:"2006-01-02" example:"2024-03-01"`
+	To       time.Time `form:"to" time_format:"2006-01-02" example:"2024-03-31"`
+	MinShare *float64  `form:"min_share" example:"3"`
+}
+
+// CategorySharesResponse - доли категорий в расходах за период. Категории с долей меньше min_share
+// объединены в последнюю строку "other" с category_id = 0
+type CategorySharesResponse struct {
+	Period               string            `json:"period" example:"2024-03"`
+	From                 time.Time         `json:"from"`
+	To                   time.Time         `json:"to"`
+	TotalAmount          money.Amount      `json:"total_amount" swaggertype:"number" example:"2450.75"`
+	MinShare             float64           `json:"min_share" example:"3"`
+	OtherCategoriesCount int               `json:"other_categories_count" example:"4"`
+	Categories           []CategoryExpense `json:"categories"`
+}
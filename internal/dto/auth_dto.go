@@ -32,6 +32,12 @@ type AccessTokenRequest struct {
 	ExpiresAt   time.Time `json:"expires_at"`
 }
 
+// ClientInfo - устройство и адрес, с которых выполняется вход или обновление токенов
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
 type LogoutRequest struct {
 	AccessToken  string `json:"access_token" validate:"required"`
 	RefreshToken string `json:"refresh_token" validate:"required"`
@@ -45,3 +51,29 @@ type AuthResponse struct {
 	RefreshToken string   `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 	User         UserInfo `json:"user"`
 }
+
+// TokenResponse - новая пара токенов после обновления
+type TokenResponse struct {
+	AccessToken  string    `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	RefreshToken string    `json:"refresh_token" example:"pY3s8m2nQ0bK..."`
+	ExpiresAt    time.Time `json:"expires_at" example:"2024-01-16T10:30:00Z"`
+}
+
+// Сеансы пользователя
+
+// SessionResponse - сеанс (вход на устройстве). ID совпадает для всех токенов, полученных обновлением от одного входа
+type SessionResponse struct {
+	ID         string    `json:"id" example:"3f9a1c0e5b7d2a4c6e8f0a1b2c3d4e5f"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)"`
+	IPAddress  string    `json:"ip_address" example:"203.0.113.7"`
+	CreatedAt  time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2024-01-16T08:00:00Z"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2024-02-15T08:00:00Z"`
+	// Current - сеанс, которому принадлежит access-токен запроса
+	Current bool `json:"current"`
+}
+
+// SessionsListResponse - действующие сеансы пользователя, последние использованные первыми
+type SessionsListResponse struct {
+	Sessions []SessionResponse `json:"sessions"`
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"finance/pkg/money"
+	"time"
+)
 
 // Запросы для бюджетов
 
@@ -8,13 +11,57 @@ import "time"
 type CreateBudgetRequest struct {
 	UserID uint `json:"user_id"`
 	//CategoryID uint       `json:"category_id" validate:"required"`
-	Amount float64 `json:"amount" validate:"required,gt=0" example:"500.00"`
-	Period string  `json:"period" validate:"required,oneof=weekly monthly yearly" example:"monthly"`
-	//StartDate time.Time `json:"start_date,omitempty"`
-	//EndDate   time.Time `json:"end_date,omitempty"`
+	Amount money.Amount `json:"amount" swaggertype:"number" validate:"required,gt=0" example:"500.00"`
+	Period string       `json:"period" validate:"required,oneof=weekly monthly yearly custom" example:"monthly"`
+	// Пороги уведомлений в процентах расходования, по умолчанию 80 и 100
+	AlertThresholds []int `json:"alert_thresholds,omitempty" example:"80,100"`
+	// Название бюджета, например "Еда" для группы или "Всего за месяц" для общего бюджета
+	Name string `json:"name,omitempty" example:"Еда"`
+	// Только для POST /budgets: категории группового бюджета. Если не заданы, бюджет учитывает все расходы
+	CategoryIDs []uint `json:"category_ids,omitempty" example:"1,2"`
+	// Для weekly, monthly и yearly - дата, период которой станет первым (по умолчанию текущий период).
+	// Для custom - начало периода
+	StartDate *time.Time `json:"start_date,omitempty" example:"2026-11-01T00:00:00Z"`
+	// Последний день периода custom, входит в период
+	EndDate *time.Time `json:"end_date,omitempty" example:"2026-11-30T00:00:00Z"`
+	// День начала недели для weekly: 0 - воскресенье, 1 - понедельник (по умолчанию), ..., 6 - суббота
+	WeekStart *int `json:"week_start,omitempty" example:"1"`
+	// День начала месяца для monthly и yearly, например день зарплаты. По умолчанию 1.
+	// Если в месяце меньше дней, период начинается в последний день месяца
+	MonthStartDay *int `json:"month_start_day,omitempty" example:"25"`
+	// Месяц начала финансового года для yearly, по умолчанию 1 (январь)
+	YearStartMonth *int `json:"year_start_month,omitempty" example:"4"`
+	// Продлевать бюджет на следующий период по окончании текущего, по умолчанию true (для custom - false)
+	AutoRenew *bool `json:"auto_renew,omitempty" example:"true"`
+	// Переносить остаток (или перерасход) в следующий период
+	CarryOver bool `json:"carry_over" example:"false"`
+	// Валюта лимита бюджета (код ISO 4217), по умолчанию базовая валюта пользователя
+	Currency string `json:"currency,omitempty" example:"EUR"`
 	//IsActive  bool      `json:"is_active" default:"true"`
 }
 
+// UpdateBudgetRequest - обновление бюджета
+type UpdateBudgetRequest struct {
+	Amount *money.Amount `json:"amount,omitempty" swaggertype:"number" validate:"omitempty,gt=0" example:"750.00"`
+	Period *string       `json:"period,omitempty" validate:"omitempty,oneof=weekly monthly yearly custom" example:"weekly"`
+	// Пороги уведомлений в процентах расходования, если не переданы - не меняются
+	AlertThresholds []int `json:"alert_thresholds,omitempty" example:"50,90,100"`
+	AutoRenew       *bool `json:"auto_renew,omitempty" example:"true"`
+	CarryOver       *bool `json:"carry_over,omitempty" example:"true"`
+	// Изменение периода или точек отсчета пересчитывает границы текущего периода от его начала
+	StartDate *time.Time `json:"start_date,omitempty" example:"2026-11-01T00:00:00Z"`
+	// Последний день периода custom, входит в период
+	EndDate        *time.Time `json:"end_date,omitempty" example:"2026-11-30T00:00:00Z"`
+	WeekStart      *int       `json:"week_start,omitempty" example:"1"`
+	MonthStartDay  *int       `json:"month_start_day,omitempty" example:"25"`
+	YearStartMonth *int       `json:"year_start_month,omitempty" example:"4"`
+	Name           *string    `json:"name,omitempty" example:"Еда"`
+	// Новый набор категорий общего или группового бюджета, пустой список - все расходы
+	CategoryIDs []uint `json:"category_ids,omitempty" example:"1,2,5"`
+	// Смена валюты пересчитывает потраченную сумму по курсам на даты расходов, лимит не пересчитывается
+	Currency *string `json:"currency,omitempty" example:"EUR"`
+}
+
 // Ответы для бюджетов
 
 // BudgetResponse - информация о бюджете
@@ -22,13 +69,28 @@ type BudgetResponse struct {
 	ID         uint `json:"id"`
 	CategoryID uint `json:"category_id"`
 	//Category   CategoryResponse `json:"category"`
-	Amount          float64   `json:"amount"`
-	CreatedAt       time.Time `json:"created_at"`
-	SpentAmount     float64   `json:"spent_amount"`
-	RemainingAmount float64   `json:"remaining_amount"`
-	Period          string    `json:"period"`
-	StartDate       time.Time `json:"start_date,omitempty"`
+	Amount          money.Amount `json:"amount" swaggertype:"number"`
+	CreatedAt       time.Time    `json:"created_at"`
+	SpentAmount     money.Amount `json:"spent_amount" swaggertype:"number"`
+	RemainingAmount money.Amount `json:"remaining_amount" swaggertype:"number"`
+	Period          string       `json:"period"`
+	StartDate       time.Time    `json:"start_date,omitempty"`
+	// Последний день текущего периода, входит в период
 	EndDate         time.Time `json:"end_date,omitempty"`
+	AlertThresholds []int     `json:"alert_thresholds"`
+	AutoRenew       bool      `json:"auto_renew"`
+	CarryOver       bool      `json:"carry_over"`
+	// Перенесено из предыдущего периода, лимит текущего периода равен amount + carried_amount
+	CarriedAmount  money.Amount `json:"carried_amount" swaggertype:"number"`
+	WeekStart      int          `json:"week_start"`
+	MonthStartDay  int          `json:"month_start_day"`
+	YearStartMonth int          `json:"year_start_month"`
+	// category - бюджет одной категории, group - группы категорий category_ids, overall - всех расходов
+	Scope       string `json:"scope" example:"group"`
+	Name        string `json:"name" example:"Еда"`
+	CategoryIDs []uint `json:"category_ids"`
+	// Валюта лимита и потраченной суммы
+	Currency string `json:"currency" example:"EUR"`
 	//IsActive   bool             `json:"is_active"`
 	//UpdatedAt  time.Time        `json:"updated_at"`
 
@@ -44,22 +106,70 @@ type BudgetsListResponse struct {
 
 // BudgetStatus - статус бюджета
 type BudgetStatus struct {
-	BudgetID        uint    `json:"budget_id"`
-	CategoryName    string  `json:"category_name"`
-	BudgetAmount    float64 `json:"budget_amount"`
-	SpentAmount     float64 `json:"spent_amount"`
-	RemainingAmount float64 `json:"remaining_amount"`
-	SpentPercentage float64 `json:"spent_percentage"`
-	Status          string  `json:"status"` // "ok", "warning", "exceeded"
-	DaysRemaining   int     `json:"days_remaining"`
+	BudgetID        uint         `json:"budget_id"`
+	CategoryName    string       `json:"category_name"`
+	BudgetAmount    money.Amount `json:"budget_amount" swaggertype:"number"`
+	SpentAmount     money.Amount `json:"spent_amount" swaggertype:"number"`
+	RemainingAmount money.Amount `json:"remaining_amount" swaggertype:"number"`
+	SpentPercentage float64      `json:"spent_percentage"`
+	Status          string       `json:"status"` // "ok", "warning", "exceeded"
+	DaysRemaining   int          `json:"days_remaining"`
+	Currency        string       `json:"currency"`
+}
+
+// BudgetStatusListResponse - статусы всех бюджетов пользователя
+type BudgetStatusListResponse struct {
+	Budgets []*BudgetStatus `json:"budgets"`
+}
+
+// RecalculateBudgetsResponse - результат пересчета потраченных сумм
+type RecalculateBudgetsResponse struct {
+	UpdatedBudgets int64 `json:"updated_budgets" example:"4"`
+	// Бюджеты, которые не пересчитаны: для части их расходов нет курса к валюте бюджета
+	SkippedBudgets int64 `json:"skipped_budgets" example:"0"`
+}
+
+// BudgetPeriodResponse - закрытый период бюджета
+type BudgetPeriodResponse struct {
+	Period    string    `json:"period" example:"monthly"`
+	StartDate time.Time `json:"start_date"`
+	// Последний день периода, входит в период
+	EndDate         time.Time    `json:"end_date"`
+	Amount          money.Amount `json:"amount" swaggertype:"number" example:"500.00"`
+	CarriedAmount   money.Amount `json:"carried_amount" swaggertype:"number" example:"25.00"`
+	SpentAmount     money.Amount `json:"spent_amount" swaggertype:"number" example:"480.50"`
+	RemainingAmount money.Amount `json:"remaining_amount" swaggertype:"number" example:"44.50"`
+	SpentPercentage float64      `json:"spent_percentage" example:"91.52"`
+	Status          string       `json:"status" example:"warning"` // "ok", "warning", "exceeded"
+}
+
+// BudgetHistoryResponse - история периодов бюджета, начиная с последнего
+type BudgetHistoryResponse struct {
+	BudgetID uint                   `json:"budget_id" example:"3"`
+	Periods  []BudgetPeriodResponse `json:"periods"`
 }
 
 // BudgetAlert - уведомление о бюджете
 type BudgetAlert struct {
-	BudgetID     uint      `json:"budget_id"`
-	CategoryName string    `json:"category_name"`
-	AlertType    string    `json:"alert_type"` // "warning", "exceeded", "near_end"
-	Message      string    `json:"message"`
-	Percentage   float64   `json:"percentage"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID             uint       `json:"id" example:"1"`
+	BudgetID       uint       `json:"budget_id" example:"3"`
+	CategoryID     uint       `json:"category_id" example:"2"`
+	CategoryName   string     `json:"category_name" example:"Продукты"`
+	AlertType      string     `json:"alert_type" example:"warning"` // "warning", "exceeded", "near_end"
+	Threshold      int        `json:"threshold" example:"80"`       // Порог в процентах, 0 для near_end
+	Message        string     `json:"message"`
+	Percentage     float64    `json:"percentage" example:"83.5"`
+	Acknowledged   bool       `json:"acknowledged"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// BudgetAlertsQuery - фильтр списка уведомлений
+type BudgetAlertsQuery struct {
+	Unacknowledged bool `form:"unacknowledged" example:"true"`
+}
+
+// BudgetAlertsListResponse - список уведомлений о бюджетах
+type BudgetAlertsListResponse struct {
+	Alerts []BudgetAlert `json:"alerts"`
 }
@@ -1,29 +1,38 @@
 package dto
 
-import "time"
+import (
+	"finance/pkg/money"
+	"time"
+)
 
 // CategoryExpense - расходы по категории
 type CategoryExpense struct {
-	CategoryID   uint    `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	Amount       float64 `json:"amount"`
-	Percentage   float64 `json:"percentage"`
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Amount       money.Amount `json:"amount" swaggertype:"number"`
+	Percentage   float64      `json:"percentage"`
 }
 
 type CategoryAnalytics struct {
 	CategoryID           uint            `json:"category_id"`
 	CategoryName         string          `json:"category_name"`
 	Period               string          `json:"period"`
-	TotalAmount          float64         `json:"total_amount"`
+	From                 time.Time       `json:"from"`
+	To                   time.Time       `json:"to"`
+	Days                 int             `json:"days"`
+	TotalAmount          money.Amount    `json:"total_amount" swaggertype:"number"`
 	ExpensesCount        int             `json:"expenses_count"`
-	AveragePerDay        float64         `json:"average_per_day"`
-	AverageExpenseAmount float64         `json:"average_expense_amount"`
+	AveragePerDay        money.Amount    `json:"average_per_day" swaggertype:"number"`
+	AverageExpenseAmount money.Amount    `json:"average_expense_amount" swaggertype:"number"`
 	LargestExpense       ExpenseResponse `json:"largest_expense"`
 	SmallestExpense      ExpenseResponse `json:"smallest_expense"`
 }
 
+// CategoryPeriod - период аналитики по категории: календарный период либо явный диапазон from/to
 type CategoryPeriod struct {
-	Period string `json:"period"`
+	Period string    `form:"period"`
+	From   time.Time `form:"from" time_format:"2006-01-02"`
+	To     time.Time `form:"to" time_format:"2006-01-02"`
 }
 
 // Запросы для категорий
@@ -42,8 +51,8 @@ type CategoryResponse struct {
 	//Description *string   `json:"description"`
 	CreatedAt time.Time `json:"created_at"`
 	// Дополнительная информация
-	ExpensesCount int     `json:"expenses_count"`
-	TotalAmount   float64 `json:"total_amount"`
+	ExpensesCount int          `json:"expenses_count"`
+	TotalAmount   money.Amount `json:"total_amount" swaggertype:"number"`
 }
 
 // CategoriesListResponse
@@ -0,0 +1,27 @@
+package dto
+
+// ExchangeRateInput - курс валюты на дату: 1 base_currency = rate quote_currency
+type ExchangeRateInput struct {
+	BaseCurrency  string  `json:"base_currency" validate:"required,len=3" example:"USD"`
+	QuoteCurrency string  `json:"quote_currency" validate:"required,len=3" example:"RUB"`
+	Rate          float64 `json:"rate" validate:"required,gt=0" example:"92.5"`
+	Date          string  `json:"date" validate:"required" example:"2024-01-15"`
+}
+
+// ImportExchangeRatesRequest - загрузка курсов валют
+type ImportExchangeRatesRequest struct {
+	Rates []ExchangeRateInput `json:"rates" validate:"required,min=1"`
+}
+
+// ImportExchangeRatesResponse - результат загрузки курсов
+type ImportExchangeRatesResponse struct {
+	Imported int64 `json:"imported" example:"30"`
+	// Расходы, сумма которых в базовой валюте пересчитана по новым курсам
+	RecalculatedExpenses int64 `json:"recalculated_expenses" example:"12"`
+	// Доходы, сумма которых в базовой валюте пересчитана по новым курсам
+	RecalculatedIncomes int64 `json:"recalculated_incomes" example:"3"`
+	// Бюджеты, потраченная сумма которых пересчитана
+	RecalculatedBudgets int64 `json:"recalculated_budgets" example:"4"`
+	// Бюджеты, которые не пересчитаны: для части их расходов нет курса к валюте бюджета
+	SkippedBudgets int64 `json:"skipped_budgets" example:"0"`
+}
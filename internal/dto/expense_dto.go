@@ -1,25 +1,45 @@
 package dto
 
-import "time"
+import (
+	"finance/pkg/money"
+	"time"
+)
 
 // Запросы для расходов
 
 // CreateExpenseRequest - создание расхода
 type CreateExpenseRequest struct {
 	//CategoryID  uint      `json:"category_id" validate:"required"`
-	Amount      float64   `json:"amount" validate:"required,gt=0" example:"25.50"`
-	Description string    `json:"description,omitempty" validate:"omitempty,max=500"`
-	Date        time.Time `json:"date" validate:"required" example:"2024-01-15T10:30:00Z"`
-	Tags        []string  `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	Amount      money.Amount `json:"amount" swaggertype:"number" validate:"required,gt=0" example:"25.50"`
+	Description string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Date        time.Time    `json:"date" validate:"required" example:"2024-01-15T10:30:00Z"`
+	Tags        []string     `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	// Валюта расхода (код ISO 4217), по умолчанию базовая валюта пользователя
+	Currency string `json:"currency,omitempty" example:"USD"`
 }
 
 // UpdateExpenseRequest - обновление расхода
 type UpdateExpenseRequest struct {
-	CategoryID  *uint      `json:"category_id,omitempty" validate:"omitempty"`
-	Amount      *float64   `json:"amount,omitempty" validate:"omitempty,gt=0"`
-	Description *string    `json:"description,omitempty" validate:"omitempty,max=500"`
-	Date        *time.Time `json:"date,omitempty"`
-	Tags        *[]string  `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	CategoryID  *uint         `json:"category_id,omitempty" validate:"omitempty"`
+	Amount      *money.Amount `json:"amount,omitempty" swaggertype:"number" validate:"omitempty,gt=0"`
+	Description *string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Date        *time.Time    `json:"date,omitempty"`
+	Tags        *[]string     `json:"tags,omitempty" validate:"omitempty,dive,min=1,max=50"`
+	Currency    *string       `json:"currency,omitempty" example:"USD"`
+}
+
+// ExpenseListQuery - фильтры, сортировка и пагинация списка расходов
+type ExpenseListQuery struct {
+	From      time.Time     `form:"from" time_format:"2006-01-02" example:"2024-01-01"`
+	To        time.Time     `form:"to" time_format:"2006-01-02" example:"2024-01-31"`
+	MinAmount *money.Amount `form:"min_amount" swaggertype:"number" example:"10"`  // в базовой валюте
+	MaxAmount *money.Amount `form:"max_amount" swaggertype:"number" example:"500"` // в базовой валюте
+	Search    string        `form:"search" example:"кофе"`
+	Tags      string        `form:"tags" example:"travel,work"`
+	Sort      string        `form:"sort" example:"date"`  // date, amount (в базовой валюте), created_at
+	Order     string        `form:"order" example:"desc"` // asc, desc
+	Limit     int           `form:"limit" example:"50"`
+	Cursor    string        `form:"cursor"`
 }
 
 // Ответы для расходов
@@ -30,60 +50,84 @@ type ExpenseResponse struct {
 	CategoryID   uint   `json:"category_id,omitempty"`
 	CategoryName string `json:"category_name,omitempty"`
 	// Category     CategoryResponse `json:"category,omitempty"`
-	Amount      float64   `json:"amount"`
-	Description *string   `json:"description,omitempty"`
-	Date        time.Time `json:"date"`
-	CreatedAt   time.Time `json:"created_at"`
+	Amount      money.Amount `json:"amount" swaggertype:"number"`
+	Description *string      `json:"description,omitempty"`
+	Date        time.Time    `json:"date"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Tags        []string     `json:"tags,omitempty"`
+	Currency    string       `json:"currency" example:"USD"`
+	// Сумма в базовой валюте пользователя по курсу на дату расхода
+	BaseAmount money.Amount `json:"base_amount" swaggertype:"number" example:"2345.60"`
 	// UpdatedAt    time.Time        `json:"updated_at"`
 }
 
+// CategorizedExpenseResponse - расход, категория которого выбрана правилами
+type CategorizedExpenseResponse struct {
+	ExpenseResponse
+	// Правило, по которому выбраны категория и теги. Пусто, если расход попал в Uncategorized
+	RuleID *uint `json:"rule_id,omitempty" example:"1"`
+}
+
 // ExpensesListResponse - список расходов с пагинацией
 type ExpensesListResponse struct {
 	Expenses []ExpenseResponse `json:"expenses"`
+	// NextCursor передается в параметре cursor для получения следующей страницы. Пустой, если страница последняя
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // ExpenseSummary - сводка по расходам
 type ExpenseSummary struct {
-	TotalAmount   float64 `json:"total_amount"`
-	TotalCount    int     `json:"total_count"`
-	AverageAmount float64 `json:"average_amount"`
-	MinAmount     float64 `json:"min_amount"`
-	MaxAmount     float64 `json:"max_amount"`
+	TotalAmount   money.Amount `json:"total_amount" swaggertype:"number"`
+	TotalCount    int          `json:"total_count"`
+	AverageAmount money.Amount `json:"average_amount" swaggertype:"number"`
+	MinAmount     money.Amount `json:"min_amount" swaggertype:"number"`
+	MaxAmount     money.Amount `json:"max_amount" swaggertype:"number"`
 }
 
+// ExpensePeriod - период аналитики: календарный период либо явный диапазон from/to
 type ExpensePeriod struct {
-	Period string `json:"period"`
+	Period string    `form:"period"`
+	From   time.Time `form:"from" time_format:"2006-01-02"`
+	To     time.Time `form:"to" time_format:"2006-01-02"`
 }
 
 // ExpenseAnalytics - аналитика расходов
 type ExpenseAnalytics struct {
 	Period               string          `json:"period"`
-	TotalAmount          float64         `json:"total_amount"`
+	From                 time.Time       `json:"from"`
+	To                   time.Time       `json:"to"`
+	Days                 int             `json:"days"`
+	TotalAmount          money.Amount    `json:"total_amount" swaggertype:"number"`
 	ExpensesCount        int             `json:"expenses_count"`
-	AveragePerDay        float64         `json:"average_per_day"`
+	AveragePerDay        money.Amount    `json:"average_per_day" swaggertype:"number"`
 	LargestExpense       ExpenseResponse `json:"largest_expense"`
 	SmallestExpense      ExpenseResponse `json:"smallest_expense"`
-	AverageExpenseAmount float64         `json:"average_expense_amount"`
+	AverageExpenseAmount money.Amount    `json:"average_expense_amount" swaggertype:"number"`
 }
 
 // DayExpense - расходы по дням
 type DayExpense struct {
-	Date   time.Time `json:"date"`
-	Amount float64   `json:"amount"`
-	Count  int       `json:"count"`
+	Date   time.Time    `json:"date"`
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+	Count  int          `json:"count"`
 }
 
 // MonthExpense - расходы по месяцам
 type MonthExpense struct {
-	Year   int     `json:"year"`
-	Month  int     `json:"month"`
-	Amount float64 `json:"amount"`
-	Count  int     `json:"count"`
+	Year   int          `json:"year"`
+	Month  int          `json:"month"`
+	Amount money.Amount `json:"amount" swaggertype:"number"`
+	Count  int          `json:"count"`
 }
 
 // ExpenseTrends - тренды расходов
 type ExpenseTrends struct {
-	GrowthRate     float64 `json:"growth_rate"`      // Процент изменения
-	Trend          string  `json:"trend"`            // "increasing", "decreasing", "stable"
-	ComparedToPrev float64 `json:"compared_to_prev"` // Сравнение с предыдущим периодом
+	CurrentAmount     money.Amount `json:"current_amount" swaggertype:"number" example:"1180"`
+	PreviousAmount    money.Amount `json:"previous_amount" swaggertype:"number" example:"1000"`
+	GrowthRate        *float64     `json:"growth_rate" example:"18"`                            // Процент изменения, null если в предыдущем периоде расходов не было
+	Trend             string       `json:"trend" example:"increasing"`                          // "increasing", "decreasing", "stable"
+	ComparedToPrev    money.Amount `json:"compared_to_prev" swaggertype:"number" example:"180"` // Сравнение с предыдущим периодом: разница сумм
+	LastYearAmount    money.Amount `json:"last_year_amount" swaggertype:"number" example:"950"` // Тот же период годом раньше
+	YearOverYearRate  *float64     `json:"year_over_year_rate" example:"24.21"`                 // Процент изменения к прошлому году, null если расходов не было
+	YearOverYearTrend string       `json:"year_over_year_trend" example:"increasing"`
 }
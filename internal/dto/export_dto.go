@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// ExportRequest - параметры выгрузки данных пользователя
+type ExportRequest struct {
+	Format string    `form:"format" example:"xlsx"` // csv, json, xlsx
+	From   time.Time `form:"from" time_format:"2006-01-02" example:"2024-01-01"`
+	To     time.Time `form:"to" time_format:"2006-01-02" example:"2024-01-31"`
+}
+
+// ExportCategory - категория в выгрузке
+type ExportCategory struct {
+	ID        uint      `json:"id" example:"3"`
+	Name      string    `json:"name" example:"Кафе"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExportExpense - расход в выгрузке
+type ExportExpense struct {
+	ID           uint         `json:"id" example:"120"`
+	Date         time.Time    `json:"date"`
+	CategoryID   uint         `json:"category_id" example:"3"`
+	CategoryName string       `json:"category_name" example:"Кафе"`
+	Amount       money.Amount `json:"amount" swaggertype:"number" example:"12.50"`
+	Currency     string       `json:"currency" example:"EUR"`
+	// Сумма в базовой валюте пользователя по курсу на дату расхода
+	BaseAmount  money.Amount `json:"base_amount" swaggertype:"number" example:"1187.50"`
+	Description string       `json:"description" example:"Кофе"`
+	Tags        []string     `json:"tags"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// ExportBudget - бюджет в выгрузке
+type ExportBudget struct {
+	ID    uint   `json:"id" example:"7"`
+	Scope string `json:"scope" example:"category"` // category, group, overall
+	Name  string `json:"name,omitempty" example:"Еда"`
+	// Категория бюджета со scope = category
+	CategoryID   uint   `json:"category_id,omitempty" example:"3"`
+	CategoryName string `json:"category_name,omitempty" example:"Кафе"`
+	// Категории бюджета со scope = group
+	CategoryIDs     []uint       `json:"category_ids"`
+	Period          string       `json:"period" example:"monthly"`
+	StartDate       time.Time    `json:"start_date"`
+	EndDate         time.Time    `json:"end_date"`
+	Amount          money.Amount `json:"amount" swaggertype:"number" example:"15000"`
+	SpentAmount     money.Amount `json:"spent_amount" swaggertype:"number" example:"4200.50"`
+	CarriedAmount   money.Amount `json:"carried_amount" swaggertype:"number" example:"0"`
+	Currency        string       `json:"currency" example:"RUB"`
+	AutoRenew       bool         `json:"auto_renew"`
+	CarryOver       bool         `json:"carry_over"`
+	AlertThresholds []int        `json:"alert_thresholds"`
+}
+
+// ExportResponse - выгрузка в формате JSON. Расходы и бюджеты отбираются по from и to, категории выгружаются все
+type ExportResponse struct {
+	ExportedAt   time.Time        `json:"exported_at"`
+	From         string           `json:"from,omitempty" example:"2024-01-01"`
+	To           string           `json:"to,omitempty" example:"2024-01-31"`
+	BaseCurrency string           `json:"base_currency" example:"RUB"`
+	Categories   []ExportCategory `json:"categories"`
+	Expenses     []ExportExpense  `json:"expenses"`
+	Budgets      []ExportBudget   `json:"budgets"`
+}
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// ImportRequest - параметры загрузки выписки. Файл передается в поле file формы multipart/form-data
+type ImportRequest struct {
+	Format string `form:"format" example:"csv"` // csv, ofx; по умолчанию определяется по расширению файла
+	// Разметка CSV: названия колонок в заголовке файла
+	DateColumn        string `form:"date_column" example:"date"`
+	AmountColumn      string `form:"amount_column" example:"amount"`
+	DescriptionColumn string `form:"description_column" example:"description"`
+	CurrencyColumn    string `form:"currency_column" example:"currency"`
+	// Шаблон даты из YYYY, YY, MM, DD, HH, mm, ss
+	DateFormat       string `form:"date_format" example:"DD.MM.YYYY"`
+	Delimiter        string `form:"delimiter" example:";"`         // ",", ";" или "tab"
+	DecimalSeparator string `form:"decimal_separator" example:","` // "." или ","
+	// Знак суммы расхода в выписке: negative - списания со знаком минус, positive - все строки расходы
+	ExpenseSign string `form:"expense_sign" example:"negative"`
+	// Категория для операций, к которым не подошло ни одно правило
+	DefaultCategoryID *uint `form:"default_category_id" example:"3"`
+}
+
+// ImportBatchResponse - загрузка выписки
+type ImportBatchResponse struct {
+	ID            uint       `json:"id" example:"1"`
+	FileName      string     `json:"file_name" example:"statement.csv"`
+	Format        string     `json:"format" example:"csv"`
+	Status        string     `json:"status" example:"preview"` // preview, committed, undone
+	RowsCount     int        `json:"rows_count" example:"42"`
+	SkippedCount  int        `json:"skipped_count" example:"3"` // поступления и нулевые операции
+	ImportedCount int        `json:"imported_count" example:"0"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CommittedAt   *time.Time `json:"committed_at,omitempty"`
+	UndoneAt      *time.Time `json:"undone_at,omitempty"`
+}
+
+// ImportRowResponse - операция из выписки
+type ImportRowResponse struct {
+	Row          int          `json:"row" example:"1"`
+	Date         time.Time    `json:"date"`
+	Amount       money.Amount `json:"amount" swaggertype:"number" example:"250.00"`
+	Currency     string       `json:"currency,omitempty" example:"RUB"`
+	Description  string       `json:"description" example:"COFFEE HOUSE"`
+	CategoryID   *uint        `json:"category_id,omitempty" example:"3"`
+	CategoryName string       `json:"category_name,omitempty" example:"Кафе"`
+	RuleID       *uint        `json:"rule_id,omitempty" example:"1"` // правило, по которому предложена категория
+	// Такой же расход (дата, сумма, валюта, описание) уже есть. По умолчанию дубликаты не импортируются
+	Duplicate          bool  `json:"duplicate"`
+	DuplicateExpenseID *uint `json:"duplicate_expense_id,omitempty" example:"120"`
+	ExpenseID          *uint `json:"expense_id,omitempty" example:"150"` // расход, созданный из строки
+}
+
+// ImportPreviewResponse - загрузка вместе с операциями
+type ImportPreviewResponse struct {
+	Import        ImportBatchResponse `json:"import"`
+	Duplicates    int                 `json:"duplicates" example:"2"`
+	Uncategorized int                 `json:"uncategorized" example:"5"`
+	Rows          []ImportRowResponse `json:"rows"`
+}
+
+// ImportsListResponse - последние загрузки пользователя
+type ImportsListResponse struct {
+	Imports []ImportBatchResponse `json:"imports"`
+}
+
+// ImportRowDecision - решение по строке загрузки
+type ImportRowDecision struct {
+	Row int `json:"row" validate:"required" example:"4"`
+	// false - не импортировать строку, true - импортировать, даже если это дубликат
+	Accept     *bool `json:"accept,omitempty" example:"true"`
+	CategoryID *uint `json:"category_id,omitempty" example:"5"` // заменяет предложенную категорию
+}
+
+// CommitImportRequest - подтверждение загрузки. Строки без решения импортируются с предложенной категорией,
+// если они не дубликаты
+type CommitImportRequest struct {
+	Rows []ImportRowDecision `json:"rows,omitempty"`
+}
+
+// UndoImportResponse - результат отмены загрузки
+type UndoImportResponse struct {
+	ImportID        uint `json:"import_id" example:"1"`
+	RemovedExpenses int  `json:"removed_expenses" example:"39"`
+}
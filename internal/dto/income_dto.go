@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// Запросы для доходов
+
+// CreateIncomeRequest - создание дохода
+type CreateIncomeRequest struct {
+	Source      string       `json:"source" validate:"required,max=255" example:"Зарплата"`
+	Amount      money.Amount `json:"amount" swaggertype:"number" validate:"required,gt=0" example:"3200.00"`
+	Description string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Date        time.Time    `json:"date" validate:"required" example:"2024-01-10T10:00:00Z"`
+	// Валюта дохода (код ISO 4217), по умолчанию базовая валюта пользователя
+	Currency string `json:"currency,omitempty" example:"USD"`
+}
+
+// UpdateIncomeRequest - обновление дохода
+type UpdateIncomeRequest struct {
+	Source      *string       `json:"source,omitempty" validate:"omitempty,max=255"`
+	Amount      *money.Amount `json:"amount,omitempty" swaggertype:"number" validate:"omitempty,gt=0"`
+	Description *string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Date        *time.Time    `json:"date,omitempty"`
+	Currency    *string       `json:"currency,omitempty" example:"USD"`
+}
+
+// CashFlowRequest - параметры отчета о движении денежных средств
+type CashFlowRequest struct {
+	From        time.Time `form:"from" time_format:"2006-01-02" example:"2024-01-01"`
+	To          time.Time `form:"to" time_format:"2006-01-02" example:"2024-12-31"`
+	Granularity string    `form:"granularity" example:"month"` // day, week, month, quarter, year
+}
+
+// Ответы для доходов
+
+// IncomeResponse - информация о доходе
+type IncomeResponse struct {
+	ID          uint         `json:"id"`
+	Source      string       `json:"source"`
+	Amount      money.Amount `json:"amount" swaggertype:"number"`
+	Description string       `json:"description,omitempty"`
+	Date        time.Time    `json:"date"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Currency    string       `json:"currency" example:"USD"`
+	// Сумма в базовой валюте пользователя по курсу на дату дохода
+	BaseAmount money.Amount `json:"base_amount" swaggertype:"number" example:"3200.00"`
+}
+
+// IncomesListResponse - список доходов
+type IncomesListResponse struct {
+	Incomes []IncomeResponse `json:"incomes"`
+}
+
+// CashFlowBucket - доходы, расходы и чистый поток за один интервал
+type CashFlowBucket struct {
+	Period   time.Time    `json:"period"`
+	Income   money.Amount `json:"income" swaggertype:"number"`
+	Expenses money.Amount `json:"expenses" swaggertype:"number"`
+	Net      money.Amount `json:"net" swaggertype:"number"`
+}
+
+// CashFlowResponse - движение денежных средств за период
+type CashFlowResponse struct {
+	From          time.Time        `json:"from"`
+	To            time.Time        `json:"to"`
+	Granularity   string           `json:"granularity"`
+	TotalIncome   money.Amount     `json:"total_income" swaggertype:"number"`
+	TotalExpenses money.Amount     `json:"total_expenses" swaggertype:"number"`
+	Net           money.Amount     `json:"net" swaggertype:"number"`
+	Buckets       []CashFlowBucket `json:"buckets"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// Запросы для регулярных расходов
+
+// CreateRecurringExpenseRequest - создание регулярного расхода
+type CreateRecurringExpenseRequest struct {
+	Amount      money.Amount `json:"amount" swaggertype:"number" validate:"required,gt=0" example:"1200.00"`
+	Description string       `json:"description,omitempty" validate:"omitempty,max=500" example:"Аренда квартиры"`
+	Frequency   string       `json:"frequency" validate:"required,oneof=daily weekly monthly yearly" example:"monthly"`
+	DayOfMonth  *int         `json:"day_of_month,omitempty" validate:"omitempty,min=1,max=31" example:"5"`
+	StartDate   time.Time    `json:"start_date" validate:"required" example:"2024-01-05T09:00:00Z"`
+	EndDate     *time.Time   `json:"end_date,omitempty" example:"2024-12-31T23:59:59Z"`
+}
+
+// UpdateRecurringExpenseRequest - обновление регулярного расхода
+type UpdateRecurringExpenseRequest struct {
+	Amount      *money.Amount `json:"amount,omitempty" swaggertype:"number" validate:"omitempty,gt=0"`
+	Description *string       `json:"description,omitempty" validate:"omitempty,max=500"`
+	Frequency   *string       `json:"frequency,omitempty" validate:"omitempty,oneof=daily weekly monthly yearly"`
+	DayOfMonth  *int          `json:"day_of_month,omitempty" validate:"omitempty,min=1,max=31"`
+	EndDate     *time.Time    `json:"end_date,omitempty"`
+	IsActive    *bool         `json:"is_active,omitempty"`
+}
+
+// Ответы для регулярных расходов
+
+// RecurringExpenseResponse - информация о регулярном расходе
+type RecurringExpenseResponse struct {
+	ID           uint         `json:"id"`
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name,omitempty"`
+	Amount       money.Amount `json:"amount" swaggertype:"number"`
+	Description  string       `json:"description,omitempty"`
+	Frequency    string       `json:"frequency"`
+	DayOfMonth   *int         `json:"day_of_month,omitempty"`
+	StartDate    time.Time    `json:"start_date"`
+	EndDate      *time.Time   `json:"end_date,omitempty"`
+	NextRunAt    time.Time    `json:"next_run_at"`
+	IsActive     bool         `json:"is_active"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// RecurringExpensesListResponse - список регулярных расходов
+type RecurringExpensesListResponse struct {
+	RecurringExpenses []RecurringExpenseResponse `json:"recurring_expenses"`
+}
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// CreateRuleRequest - создание правила категоризации. Операция подходит под правило, если выполнены все заданные условия
+type CreateRuleRequest struct {
+	CategoryID uint `json:"category_id" validate:"required" example:"3"`
+	// Как сравнивать pattern с описанием: contains - подстрока, regex - регулярное выражение RE2. Регистр не важен
+	MatchType string `json:"match_type,omitempty" example:"contains"`
+	// Пустой pattern подходит к любому описанию, тогда нужен диапазон суммы
+	Pattern string `json:"pattern,omitempty" validate:"max=200" example:"coffee"`
+	// Границы суммы операции в ее валюте включительно
+	MinAmount *money.Amount `json:"min_amount,omitempty" swaggertype:"number" example:"100"`
+	MaxAmount *money.Amount `json:"max_amount,omitempty" swaggertype:"number" example:"500"`
+	// Теги, которые добавляются к расходу вместе с категорией
+	Tags []string `json:"tags,omitempty" example:"coffee,work"`
+	// Из нескольких подходящих правил применяется правило с большим приоритетом
+	Priority int `json:"priority" example:"10"`
+}
+
+// RuleResponse - правило категоризации
+type RuleResponse struct {
+	ID           uint          `json:"id" example:"1"`
+	CategoryID   uint          `json:"category_id" example:"3"`
+	CategoryName string        `json:"category_name" example:"Кафе"`
+	MatchType    string        `json:"match_type" example:"contains"`
+	Pattern      string        `json:"pattern" example:"coffee"`
+	MinAmount    *money.Amount `json:"min_amount,omitempty" swaggertype:"number" example:"100"`
+	MaxAmount    *money.Amount `json:"max_amount,omitempty" swaggertype:"number" example:"500"`
+	Tags         []string      `json:"tags" example:"coffee,work"`
+	Priority     int           `json:"priority" example:"10"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+// RulesListResponse - правила пользователя в порядке применения
+type RulesListResponse struct {
+	Rules []RuleResponse `json:"rules"`
+}
+
+// TestRuleRequest - пример операции для проверки правил
+type TestRuleRequest struct {
+	Description string `json:"description" example:"COFFEE HOUSE 123"`
+	// Без суммы правила с диапазоном суммы не подходят
+	Amount *money.Amount `json:"amount,omitempty" swaggertype:"number" example:"250"`
+}
+
+// TestRuleResponse - результат применения правил к примеру операции
+type TestRuleResponse struct {
+	Matched bool          `json:"matched"`
+	Rule    *RuleResponse `json:"rule,omitempty"`
+	// Категория, в которую попадет расход. Без подходящего правила - Uncategorized
+	CategoryID   *uint    `json:"category_id,omitempty" example:"3"`
+	CategoryName string   `json:"category_name" example:"Кафе"`
+	Tags         []string `json:"tags" example:"coffee,work"`
+}
+
+// AppliedRule - расход, перенесенный из Uncategorized по правилу
+type AppliedRule struct {
+	ExpenseID  uint `json:"expense_id" example:"120"`
+	RuleID     uint `json:"rule_id" example:"1"`
+	CategoryID uint `json:"category_id" example:"3"`
+}
+
+// ApplyRulesResponse - результат повторного применения правил к расходам без категории
+type ApplyRulesResponse struct {
+	Checked     int           `json:"checked" example:"12"`
+	Categorized int           `json:"categorized" example:"9"`
+	Expenses    []AppliedRule `json:"expenses"`
+}
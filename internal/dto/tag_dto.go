@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// TagAnalyticsRequest - период для аналитики по тегам
+type TagAnalyticsRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02" example:"2024-01-01"`
+	To   time.Time `form:"to" time_format:"2006-01-02" example:"2024-12-31"`
+}
+
+// TagSummary - расходы по одному тегу во всех категориях
+type TagSummary struct {
+	Tag             string       `json:"tag" example:"командировка"`
+	TotalAmount     money.Amount `json:"total_amount" swaggertype:"number" example:"845.30"`
+	ExpensesCount   int          `json:"expenses_count" example:"12"`
+	CategoriesCount int          `json:"categories_count" example:"3"`
+}
+
+// TagAnalyticsResponse - аналитика расходов по тегам
+type TagAnalyticsResponse struct {
+	Tags []TagSummary `json:"tags"`
+}
@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"finance/pkg/money"
+	"time"
+)
 
 // UserInfo - краткая информация о пользователе для ответа
 type UserInfo struct {
@@ -11,6 +14,8 @@ type UserInfo struct {
 }
 type UserID struct {
 	UserID int `json:"id"`
+	// SessionID - сеанс, которому выдан access-токен
+	SessionID string `json:"session_id,omitempty"`
 }
 
 // Профиль пользователя
@@ -21,15 +26,25 @@ type UserProfile struct {
 	FirstName string    `json:"first_name" example:"John"`
 	LastName  string    `json:"last_name" example:"Doe"`
 	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	// Валюта, в которую пересчитываются расходы для аналитики и статистики
+	BaseCurrency string `json:"base_currency" example:"RUB"`
 }
 
 // UserStats структура статистики пользователя
 type UserStats struct {
-	TotalExpenses   float64 `json:"total_expenses" example:"1250.50"`
-	TotalCategories int     `json:"total_categories" example:"5"`
-	TotalBudgets    int     `json:"total_budgets" example:"3"`
-	MonthlyExpenses float64 `json:"monthly_expenses" example:"450.75"`
-	WeeklyExpenses  float64 `json:"weekly_expenses" example:"125.25"`
+	TotalExpenses   float64      `json:"total_expenses" example:"1250.50"`
+	TotalCategories int          `json:"total_categories" example:"5"`
+	TotalBudgets    int          `json:"total_budgets" example:"3"`
+	MonthlyExpenses money.Amount `json:"monthly_expenses" swaggertype:"number" example:"450.75"`
+	WeeklyExpenses  money.Amount `json:"weekly_expenses" swaggertype:"number" example:"125.25"`
+	TotalIncome     money.Amount `json:"total_income" swaggertype:"number" example:"3200.00"`
+	SavingsRate     float64      `json:"savings_rate" example:"18.5"` // Доля сэкономленного дохода в процентах
+	Currency        string       `json:"currency" example:"RUB"`      // Базовая валюта, в которой посчитаны суммы
+}
+
+// UpdateBaseCurrencyRequest - смена базовой валюты
+type UpdateBaseCurrencyRequest struct {
+	Currency string `json:"currency" validate:"required,len=3" example:"USD"`
 }
 
 // ChangePasswordRequest - смена пароля
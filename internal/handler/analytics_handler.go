@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	analyticsService services.AnalyticsServiceInterface
+}
+
+func NewAnalyticsHandler(analyticsService services.AnalyticsServiceInterface) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetTimeSeries godoc
+// @Summary Временной ряд расходов
+// @Description Сумма и количество расходов по дням, неделям или месяцам. Интервалы без расходов возвращаются с нулями. Для day и week заполняется points, для month - months
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param granularity query string false "Интервал: day, week, month" default(day)
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param category_id query int false "ID категории, по умолчанию - все категории"
+// @Success 200 {object} dto.TimeSeriesResponse "Временной ряд расходов"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры периода"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /analytics/timeseries [get]
+func (h *AnalyticsHandler) GetTimeSeries(c *gin.Context) {
+	log := logger.New("analytics_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.TimeSeriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	series, err := h.analyticsService.GetExpenseTimeSeries(ctx, userID, req)
+	if err != nil {
+		log.Error("getting time series failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting time series succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, series)
+}
+
+// GetTrends godoc
+// @Summary Тренды расходов
+// @Description Сравнение расходов за период с предыдущим периодом и с тем же периодом прошлого года, в целом и по каждой категории. Незаконченный период сравнивается по одинаковому числу прошедших дней
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц"
+// @Param from query string false "Начало периода (YYYY-MM-DD), вместо period"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD), вместо period"
+// @Param category_id query int false "ID категории, по умолчанию - все категории"
+// @Param threshold query number false "Изменение в процентах, до которого тренд считается стабильным" default(5)
+// @Success 200 {object} dto.TrendsResponse "Тренды расходов"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры периода или порога"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /analytics/trends [get]
+func (h *AnalyticsHandler) GetTrends(c *gin.Context) {
+	log := logger.New("analytics_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.TrendsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	trends, err := h.analyticsService.GetExpenseTrends(ctx, userID, req)
+	if err != nil {
+		log.Error("getting trends failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting trends succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, trends)
+}
+
+// GetCategoryShares godoc
+// @Summary Структура расходов по категориям
+// @Description Сумма расходов по каждой категории и ее доля в общих расходах за период. Категории с долей меньше min_share объединяются в последнюю строку "other" с category_id = 0
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param period query string false "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц"
+// @Param from query string false "Начало периода (YYYY-MM-DD), вместо period"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD), вместо period"
+// @Param min_share query number false "Доля в процентах, меньше которой категория попадает в other" default(3)
+// @Success 200 {object} dto.CategorySharesResponse "Доли категорий"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры периода или порога"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /analytics/categories [get]
+func (h *AnalyticsHandler) GetCategoryShares(c *gin.Context) {
+	log := logger.New("analytics_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.CategorySharesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	shares, err := h.analyticsService.GetCategoryShares(ctx, userID, req)
+	if err != nil {
+		log.Error("getting category shares failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting category shares succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, shares)
+}
@@ -2,11 +2,13 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"finance/internal/dto"
 	"finance/internal/middleware"
 	"finance/internal/services"
 	"finance/pkg/logger"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -90,7 +92,7 @@ func (h *AuthHandler) SignIn(c *gin.Context) {
 		return
 	}
 
-	token, err := h.authService.SignIn(ctx, userAuth)
+	token, err := h.authService.SignIn(ctx, userAuth, clientInfo(c))
 	if err != nil {
 		log.Error("Login failed", map[string]interface{}{
 			"error":  err.Error(),
@@ -121,19 +123,88 @@ func (h *AuthHandler) SignIn(c *gin.Context) {
 	)
 }
 
+// Refresh godoc
+// @Summary Обновление токенов
+// @Description Обмен refresh токена на новую пару токенов. Токен берется из cookie refresh_token или из тела запроса.
+// @Description Каждый refresh токен можно обменять один раз: повторное использование отзывает все токены сеанса
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest false "Refresh токен, если он не передан в cookie"
+// @Success 200 {object} dto.TokenResponse "Новая пара токенов"
+// @Failure 401 {object} dto.ErrorResponse "Токен не найден, истек или уже использован"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	log := logger.New("auth-handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	refresh_token, _ := c.Cookie("refresh_token")
+	if refresh_token == "" && c.Request.ContentLength != 0 {
+		var req dto.RefreshTokenRequest
+		if err := c.BindJSON(&req); err != nil {
+			log.Error("Invalid refresh request", map[string]interface{}{
+				"error":  err.Error(),
+				"status": http.StatusBadRequest,
+			})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		refresh_token = req.RefreshToken
+	}
+	if refresh_token == "" {
+		log.Error("Refresh token is required", map[string]interface{}{
+			"status": http.StatusUnauthorized,
+		})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token is required"})
+		return
+	}
+
+	tokens, err := h.authService.Refresh(ctx, refresh_token, clientInfo(c))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenExpired) || errors.Is(err, services.ErrRefreshTokenReused) {
+			status = http.StatusUnauthorized
+			clearRefreshTokenCookie(c)
+		}
+		log.Error("Token refresh failed", map[string]interface{}{
+			"error":  err.Error(),
+			"status": status,
+		})
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	middleware.SetRefreshTokenCookie(c, tokens.RefreshToken)
+	c.Header("Authorization", "Bearer "+tokens.AccessToken)
+	c.JSON(http.StatusOK, tokens)
+}
+
 // Logout godoc
 // @Summary Выход из системы
-// @Description Деактивация refresh токена и выход из системы
+// @Description Удаление refresh токена и отзыв текущего access токена. Достаточно передать один из токенов
 // @Tags Authentication
 // @Accept json
 // @Produce json
+// @Param Authorization header string false "Bearer access токен"
 // @Success 200 {object} map[string]string "Успешный выход"
-// @Failure 401 {object} dto.ErrorResponse "Токен не найден"
+// @Failure 401 {object} dto.ErrorResponse "Токен не найден или недействителен"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /auth/logout [post]
-func (h *AuthHandler) Logout(c *gin.Context) { //нужно придумать, как диактивировать access-токены, которые были выданы
+func (h *AuthHandler) Logout(c *gin.Context) {
 	log := logger.New("auth-handler", true)
-	refresh_token, err := c.Cookie("refresh_token")
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	refresh_token, _ := c.Cookie("refresh_token")
+	access_token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if refresh_token == "" && access_token == "" {
+		log.Error("Logout failed", map[string]interface{}{
+			"error":  "no refresh token or access token",
+			"status": http.StatusUnauthorized,
+		})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token or access token is required"})
+		return
+	}
+	err := h.authService.Logout(ctx, access_token, refresh_token)
 	if err != nil {
 		log.Error("Logout failed", map[string]interface{}{
 			"error":  err.Error(),
@@ -142,15 +213,156 @@ func (h *AuthHandler) Logout(c *gin.Context) { //нужно придумать,
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
+	clearRefreshTokenCookie(c)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+
+}
+
+// LogoutAll godoc
+// @Summary Выход на всех устройствах
+// @Description Удаление всех refresh токенов пользователя и отзыв всех выданных ему access токенов
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "Все сеансы завершены"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	log := logger.New("auth-handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusUnauthorized,
+		})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	err = h.authService.LogoutAll(ctx, int(userID))
+	if err != nil {
+		log.Error("Logout from all sessions failed", map[string]interface{}{
+			"error":   err.Error(),
+			"user_id": userID,
+			"status":  http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	clearRefreshTokenCookie(c)
+	log.Info("User logged out from all sessions", map[string]interface{}{
+		"user_id": userID,
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out from all sessions"})
+}
+
+// GetSessions godoc
+// @Summary Список сеансов
+// @Description Устройства, на которых выполнен вход: User-Agent и IP последнего входа или обновления токенов, время начала сеанса и последнего использования
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SessionsListResponse "Действующие сеансы"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /user/sessions [get]
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	log := logger.New("auth-handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusUnauthorized,
+		})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	sessions, err := h.authService.GetSessions(ctx, int(userID), c.GetString("session_id"))
+	if err != nil {
+		log.Error("getting sessions failed", map[string]interface{}{
+			"error":  err.Error(),
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// DeleteSession godoc
+// @Summary Завершение сеанса
+// @Description Завершение сеанса на устройстве: refresh токены сеанса удаляются, выданные в нем access токены отзываются
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID сеанса"
+// @Success 200 {object} map[string]string "Сеанс завершен"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Сеанс не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /user/sessions/{id} [delete]
+func (h *AuthHandler) DeleteSession(c *gin.Context) {
+	log := logger.New("auth-handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusUnauthorized,
+		})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	sessionID := c.Param("id")
+	err = h.authService.DeleteSession(ctx, int(userID), sessionID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrSessionNotFound) {
+			status = http.StatusNotFound
+		}
+		log.Error("deleting session failed", map[string]interface{}{
+			"error":      err.Error(),
+			"session_id": sessionID,
+			"status":     status,
+		})
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+	if sessionID == c.GetString("session_id") {
+		clearRefreshTokenCookie(c)
+	}
+	log.Info("Session deleted", map[string]interface{}{
+		"user_id":    userID,
+		"session_id": sessionID,
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Session deleted"})
+}
+
+// clientInfo возвращает устройство и адрес клиента для сохранения в сеансе
+func clientInfo(c *gin.Context) dto.ClientInfo {
+	return dto.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// clearRefreshTokenCookie удаляет cookie с refresh токеном
+func clearRefreshTokenCookie(c *gin.Context) {
 	c.SetCookie(
 		"refresh_token",
-		refresh_token,
+		"",
 		-1,
 		"/",
 		"",
 		true,
 		true,
 	)
-	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
-
 }
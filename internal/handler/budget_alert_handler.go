@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BudgetAlertHandler struct {
+	budgetAlertService services.BudgetAlertServiceInterface
+}
+
+func NewBudgetAlertHandler(budgetAlertService services.BudgetAlertServiceInterface) *BudgetAlertHandler {
+	return &BudgetAlertHandler{
+		budgetAlertService: budgetAlertService,
+	}
+}
+
+// GetAlerts godoc
+// @Summary Уведомления о бюджетах
+// @Description Последние уведомления о бюджетах: достигнут порог расходования (warning, exceeded) или период скоро закончится (near_end)
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param unacknowledged query bool false "Только непрочитанные"
+// @Success 200 {object} dto.BudgetAlertsListResponse "Список уведомлений"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры запроса"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /alerts [get]
+func (h *BudgetAlertHandler) GetAlerts(c *gin.Context) {
+	log := logger.New("budget_alert_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var query dto.BudgetAlertsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	alerts, err := h.budgetAlertService.GetAlerts(ctx, userID, query)
+	if err != nil {
+		log.Error("getting budget alerts failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting budget alerts succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, alerts)
+}
+
+// AcknowledgeAlert godoc
+// @Summary Подтверждение уведомления
+// @Description Отмечает уведомление о бюджете прочитанным
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param alert_id path int true "ID уведомления"
+// @Success 200 {object} map[string]string "Уведомление подтверждено"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID уведомления"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Уведомление не найдено"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /alerts/{alert_id}/ack [post]
+func (h *BudgetAlertHandler) AcknowledgeAlert(c *gin.Context) {
+	log := logger.New("budget_alert_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	alertID, err := strconv.Atoi(c.Param("alert_id"))
+	if err != nil {
+		log.Error("getting alert_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid alert id",
+		})
+		return
+	}
+	if err := h.budgetAlertService.AcknowledgeAlert(ctx, userID, alertID); err != nil {
+		log.Error("acknowledging budget alert failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusNotFound,
+		})
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("acknowledging budget alert succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "alert acknowledged",
+	})
+}
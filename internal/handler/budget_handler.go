@@ -53,7 +53,7 @@ func (b *BudgetHandler) CreateBudget(c *gin.Context) {
 		})
 		return
 	}
-	category_id, err := strconv.Atoi(c.Param("category_id"))
+	category_id, err := budgetCategoryID(c)
 	if err != nil {
 		log.Error("getting category_id failed", map[string]interface{}{
 			"error":  err,
@@ -96,6 +96,7 @@ func (b *BudgetHandler) CreateBudget(c *gin.Context) {
 		Period:          newbudget.Period,
 		StartDate:       newbudget.StartDate,
 		EndDate:         newbudget.EndDate,
+		AlertThresholds: newbudget.AlertThresholds,
 	})
 }
 
@@ -127,7 +128,7 @@ func (b *BudgetHandler) GetBudgets(c *gin.Context) {
 		})
 		return
 	}
-	category_id, err := strconv.Atoi(c.Param("category_id"))
+	category_id, err := budgetCategoryID(c)
 	if err != nil {
 		log.Error("getting category_id failed", map[string]interface{}{
 			"error":  err,
@@ -156,6 +157,158 @@ func (b *BudgetHandler) GetBudgets(c *gin.Context) {
 	})
 }
 
+// UpdateBudget godoc
+// @Summary Обновление бюджета
+// @Description Изменение суммы или периода бюджета. При смене периода дата окончания и потраченная сумма пересчитываются
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Param budget_id path int true "ID бюджета"
+// @Param budget body dto.UpdateBudgetRequest true "Изменяемые поля бюджета"
+// @Success 200 {object} dto.BudgetResponse "Бюджет успешно обновлен"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории, бюджета или данные"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/budgets/{budget_id} [patch]
+func (b *BudgetHandler) UpdateBudget(c *gin.Context) {
+	log := logger.New("budget_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	category_id, err := budgetCategoryID(c)
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	budgetID, err := strconv.Atoi(c.Param("budget_id"))
+	if err != nil {
+		log.Error("getting budget_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid budget id",
+		})
+		return
+	}
+	var req dto.UpdateBudgetRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	updatedBudget, err := b.budgetService.UpdateBudget(ctx, userID, category_id, budgetID, req)
+	if err != nil {
+		log.Error("updating budget failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	log.Info("updating budget succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, updatedBudget)
+}
+
+// GetBudgetsStatus godoc
+// @Summary Статус всех бюджетов
+// @Description Получение статуса (ok, warning, exceeded), процента расходования и оставшихся дней для всех бюджетов пользователя
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.BudgetStatusListResponse "Статусы бюджетов"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /budgets/status [get]
+func (b *BudgetHandler) GetBudgetsStatus(c *gin.Context) {
+	log := logger.New("budget_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	statuses, err := b.budgetService.CheckBudgetStatus(ctx, userID)
+	if err != nil {
+		log.Error("checking budget status failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	log.Info("checking budget status succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, dto.BudgetStatusListResponse{
+		Budgets: statuses,
+	})
+}
+
+// RecalculateAllBudgets godoc
+// @Summary Пересчет потраченных сумм бюджетов всех пользователей
+// @Description Служебный эндпоинт: заново рассчитывает spent_amount бюджетов всех пользователей по таблице расходов, исправляя накопившиеся расхождения
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} dto.RecalculateBudgetsResponse "Количество пересчитанных бюджетов"
+// @Failure 403 {object} dto.ErrorResponse "Неверный токен администратора"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/budgets/recalculate [post]
+func (b *BudgetHandler) RecalculateAllBudgets(c *gin.Context) {
+	log := logger.New("budget_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	// userID = 0 - бюджеты всех пользователей
+	res, err := b.budgetService.RecalculateBudgets(ctx, 0)
+	if err != nil {
+		log.Error("recalculating all budgets failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	log.Info("recalculating all budgets succeed", map[string]interface{}{
+		"updated_budgets": res.UpdatedBudgets,
+		"skipped_budgets": res.SkippedBudgets,
+		"status":          http.StatusOK,
+	})
+	c.JSON(http.StatusOK, res)
+}
+
 // DeleteBudget godoc
 // @Summary Удаление бюджета
 // @Description Удаление конкретного бюджета пользователя
@@ -185,7 +338,7 @@ func (b *BudgetHandler) DeleteBudget(c *gin.Context) {
 		})
 		return
 	}
-	category_id, err := strconv.Atoi(c.Param("category_id"))
+	category_id, err := budgetCategoryID(c)
 	if err != nil {
 		log.Error("getting category_id failed", map[string]interface{}{
 			"error":  err,
@@ -224,3 +377,166 @@ func (b *BudgetHandler) DeleteBudget(c *gin.Context) {
 	})
 
 }
+
+// GetBudgetHistory godoc
+// @Summary История периодов бюджета
+// @Description Закрытые периоды автоматически продлеваемого бюджета с лимитом, перенесенной и потраченной суммой, начиная с последнего
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Param budget_id path int true "ID бюджета"
+// @Success 200 {object} dto.BudgetHistoryResponse "История периодов бюджета"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории или бюджета"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Бюджет не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/budgets/{budget_id}/history [get]
+func (b *BudgetHandler) GetBudgetHistory(c *gin.Context) {
+	log := logger.New("budget_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	category_id, err := budgetCategoryID(c)
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	budgetID, err := strconv.Atoi(c.Param("budget_id"))
+	if err != nil {
+		log.Error("getting budget_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid budget id",
+		})
+		return
+	}
+	history, err := b.budgetService.GetBudgetHistory(ctx, userID, category_id, budgetID)
+	if err != nil {
+		log.Error("getting budget history failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusNotFound,
+		})
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	log.Info("getting budget history succeed", map[string]interface{}{
+		"budget_id": budgetID,
+		"periods":   len(history.Periods),
+		"status":    http.StatusOK,
+	})
+	c.JSON(http.StatusOK, history)
+}
+
+// CreateCrossCategoryBudget godoc
+// @Summary Создание общего бюджета или бюджета группы категорий
+// @Description Бюджет без category_ids учитывает все расходы пользователя, с category_ids - расходы перечисленных категорий
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param budget body dto.CreateBudgetRequest true "Данные для создания бюджета"
+// @Success 200 {object} dto.BudgetResponse "Бюджет успешно создан"
+// @Failure 400 {object} dto.ErrorResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /budgets [post]
+func (b *BudgetHandler) CreateCrossCategoryBudget(c *gin.Context) {
+	b.CreateBudget(c)
+}
+
+// GetAllBudgets godoc
+// @Summary Получение всех бюджетов
+// @Description Все бюджеты пользователя: бюджеты категорий, групп категорий и общие
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.BudgetsListResponse "Список бюджетов"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /budgets [get]
+func (b *BudgetHandler) GetAllBudgets(c *gin.Context) {
+	b.GetBudgets(c)
+}
+
+// UpdateBudgetByID godoc
+// @Summary Обновление любого бюджета
+// @Description Изменение бюджета по ID без указания категории. Для общих бюджетов и групп можно изменить набор категорий
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param budget_id path int true "ID бюджета"
+// @Param budget body dto.UpdateBudgetRequest true "Изменяемые поля бюджета"
+// @Success 200 {object} dto.BudgetResponse "Бюджет успешно обновлен"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID бюджета или данные"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /budgets/{budget_id} [patch]
+func (b *BudgetHandler) UpdateBudgetByID(c *gin.Context) {
+	b.UpdateBudget(c)
+}
+
+// DeleteBudgetByID godoc
+// @Summary Удаление любого бюджета
+// @Description Удаление бюджета по ID без указания категории
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param budget_id path int true "ID бюджета"
+// @Success 200 {object} map[string]string "Бюджет успешно удален"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID бюджета"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /budgets/{budget_id} [delete]
+func (b *BudgetHandler) DeleteBudgetByID(c *gin.Context) {
+	b.DeleteBudget(c)
+}
+
+// GetBudgetHistoryByID godoc
+// @Summary История периодов любого бюджета
+// @Description Закрытые периоды бюджета по ID без указания категории, начиная с последнего
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param budget_id path int true "ID бюджета"
+// @Success 200 {object} dto.BudgetHistoryResponse "История периодов бюджета"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID бюджета"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Бюджет не найден"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /budgets/{budget_id}/history [get]
+func (b *BudgetHandler) GetBudgetHistoryByID(c *gin.Context) {
+	b.GetBudgetHistory(c)
+}
+
+// budgetCategoryID возвращает категорию из маршрута /categories/:category_id/budgets
+// или 0 для маршрутов /budgets, где бюджет не привязан к одной категории
+func budgetCategoryID(c *gin.Context) (int, error) {
+	if c.Param("category_id") == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(c.Param("category_id"))
+}
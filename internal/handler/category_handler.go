@@ -295,18 +295,20 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 
 // GetAnalyticsByCategory godoc
 // @Summary Получение аналитики по категории
-// @Description Получение детальной аналитики расходов по конкретной категории
+// @Description Получение детальной аналитики расходов по категории за календарный период или произвольный диапазон дат. Среднее за день считается по фактически прошедшим дням периода
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param category_id path int true "ID категории"
-// @Param period body dto.CategoryPeriod true "Период для анализа"
+// @Param period query string false "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц"
+// @Param from query string false "Начало периода (YYYY-MM-DD), вместо period"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD), вместо period"
 // @Success 200 {object} dto.CategoryAnalytics "Аналитика по категории"
 // @Failure 400 {object} dto.ErrorResponse "Неверный ID категории или период"
 // @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /categories/{category_id}/analytics [get]
+// @Router /categories/analytics/{category_id} [get]
 func (h *CategoryHandler) GetAnalyticsByCategory(c *gin.Context) {
 	log := logger.New("category_handler", true)
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
@@ -334,8 +336,8 @@ func (h *CategoryHandler) GetAnalyticsByCategory(c *gin.Context) {
 		return
 	}
 	var period dto.CategoryPeriod
-	if err := c.BindJSON(&period); err != nil {
-		log.Error("parsing JSON failed", map[string]interface{}{
+	if err := c.ShouldBindQuery(&period); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
 			"error":  err,
 			"status": http.StatusBadRequest,
 		})
@@ -348,9 +350,9 @@ func (h *CategoryHandler) GetAnalyticsByCategory(c *gin.Context) {
 	if err != nil {
 		log.Error("getting category analytics failed", map[string]interface{}{
 			"error":  err,
-			"status": http.StatusInternalServerError,
+			"status": http.StatusBadRequest,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
 		return
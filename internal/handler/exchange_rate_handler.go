@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateService services.ExchangeRateServiceInterface
+}
+
+func NewExchangeRateHandler(exchangeRateService services.ExchangeRateServiceInterface) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// ImportExchangeRates godoc
+// @Summary Загрузка курсов валют
+// @Description Сохраняет курсы валют (курс за ту же дату перезаписывается) и пересчитывает по ним суммы расходов в базовой валюте пользователей и потраченные суммы бюджетов.
+// @Description Принимает JSON или CSV (Content-Type: text/csv) с заголовком date,base_currency,quote_currency,rate.
+// @Description Курс означает, сколько единиц quote_currency стоит 1 base_currency. Если прямого курса нет, используется обратный или кросс-курс через общую валюту
+// @Tags Admin
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Security AdminToken
+// @Param rates body dto.ImportExchangeRatesRequest true "Курсы валют"
+// @Success 200 {object} dto.ImportExchangeRatesResponse "Курсы загружены"
+// @Failure 400 {object} dto.ErrorResponse "Ошибка валидации данных"
+// @Failure 403 {object} dto.ErrorResponse "Неверный токен администратора"
+// @Router /admin/exchange-rates [post]
+func (h *ExchangeRateHandler) ImportExchangeRates(c *gin.Context) {
+	log := logger.New("exchange_rate_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	var res dto.ImportExchangeRatesResponse
+	var err error
+	if c.ContentType() == "text/csv" {
+		res, err = h.exchangeRateService.ImportRatesCSV(ctx, c.Request.Body)
+	} else {
+		var req dto.ImportExchangeRatesRequest
+		if err := c.BindJSON(&req); err != nil {
+			log.Error("parsing JSON failed", map[string]interface{}{
+				"error":  err,
+				"status": http.StatusBadRequest,
+			})
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		res, err = h.exchangeRateService.ImportRates(ctx, req)
+	}
+	if err != nil {
+		log.Error("importing exchange rates failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("importing exchange rates succeed", map[string]interface{}{
+		"imported": res.Imported,
+		"status":   http.StatusOK,
+	})
+	c.JSON(http.StatusOK, res)
+}
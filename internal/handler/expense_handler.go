@@ -94,9 +94,12 @@ func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
 		CategoryID:   uint(category_id),
 		CategoryName: createdExpense.CategoryName, //!!!!!!!!!!!!!!!!!!
 		Amount:       createdExpense.Amount,
+		Currency:     createdExpense.Currency,
+		BaseAmount:   createdExpense.BaseAmount,
 		Description:  createdExpense.Description,
 		Date:         createdExpense.Date,
 		CreatedAt:    createdExpense.CreatedAt,
+		Tags:         createdExpense.Tags,
 	})
 
 }
@@ -172,23 +175,36 @@ func (h *ExpenseHandler) GetExpense(c *gin.Context) {
 		CategoryID:   expense.CategoryID,
 		CategoryName: expense.CategoryName, // !!!!!!!!!!!!!!!!!!!!!!!!
 		Amount:       expense.Amount,
+		Currency:     expense.Currency,
+		BaseAmount:   expense.BaseAmount,
 		Description:  expense.Description,
 		Date:         expense.Date,
 		CreatedAt:    expense.CreatedAt,
+		Tags:         expense.Tags,
 	})
 
 }
 
 // GetExpenses godoc
 // @Summary Получение списка расходов
-// @Description Получение всех расходов пользователя в указанной категории
+// @Description Получение расходов пользователя в указанной категории с фильтрами, сортировкой и постраничной выдачей. Параметр tags оставляет расходы, у которых есть хотя бы один из перечисленных тегов. Для следующей страницы передайте next_cursor из ответа в параметре cursor
 // @Tags Expenses
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param category_id path int true "ID категории"
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param min_amount query number false "Минимальная сумма"
+// @Param max_amount query number false "Максимальная сумма"
+// @Param search query string false "Поиск по описанию без учета регистра"
+// @Param tags query string false "Теги через запятую, например travel,work"
+// @Param sort query string false "Поле сортировки: date, amount, created_at" default(date)
+// @Param order query string false "Направление сортировки: asc, desc" default(desc)
+// @Param limit query int false "Размер страницы (максимум 200)" default(50)
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
 // @Success 200 {object} dto.ExpensesListResponse "Список расходов"
-// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории или параметры выборки"
 // @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /categories/{category_id}/expenses [get]
@@ -219,9 +235,63 @@ func (h *ExpenseHandler) GetExpenses(c *gin.Context) {
 		return
 
 	}
-	expenses, err := h.expenseService.GetUserExpenses(ctx, category_id, userID)
+	var query dto.ExpenseListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	expenses, err := h.expenseService.GetUserExpenses(ctx, category_id, userID, query)
 	if err != nil {
 		log.Error("getting user expenses failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting user expenses succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, expenses)
+}
+
+// GetAllExpenses godoc
+// @Summary Получение расходов во всех категориях
+// @Description Получение расходов пользователя во всех категориях с теми же фильтрами, сортировкой и постраничной выдачей, что и список расходов категории
+// @Tags Expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param min_amount query number false "Минимальная сумма"
+// @Param max_amount query number false "Максимальная сумма"
+// @Param search query string false "Поиск по описанию без учета регистра"
+// @Param tags query string false "Теги через запятую, например travel,work"
+// @Param sort query string false "Поле сортировки: date, amount, created_at" default(date)
+// @Param order query string false "Направление сортировки: asc, desc" default(desc)
+// @Param limit query int false "Размер страницы (максимум 200)" default(50)
+// @Param cursor query string false "Курсор следующей страницы из next_cursor"
+// @Success 200 {object} dto.ExpensesListResponse "Список расходов"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры выборки"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /expenses [get]
+func (h *ExpenseHandler) GetAllExpenses(c *gin.Context) {
+	log := logger.New("user_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
 			"error":  err,
 			"status": http.StatusInternalServerError,
 		})
@@ -230,12 +300,113 @@ func (h *ExpenseHandler) GetExpenses(c *gin.Context) {
 		})
 		return
 	}
+	var query dto.ExpenseListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	// category_id = 0 снимает ограничение по категории
+	expenses, err := h.expenseService.GetUserExpenses(ctx, 0, userID, query)
+	if err != nil {
+		log.Error("getting user expenses failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 	log.Info("getting user expenses succeed", map[string]interface{}{
 		"status": http.StatusOK,
 	})
-	c.JSON(http.StatusOK, dto.ExpensesListResponse{ // нужно возвращать инфу про категорию и дальше уже информвцию по каждому расходу
-		Expenses: expenses,
+	c.JSON(http.StatusOK, expenses)
+}
+
+// UpdateExpense godoc
+// @Summary Обновление расхода
+// @Description Частичное обновление расхода: категория, сумма, описание, дата и теги. Переданный список тегов заменяет текущий. Суммы в бюджетах пересчитываются в той же транзакции
+// @Tags Expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Param expense_id path int true "ID расхода"
+// @Param expense body dto.UpdateExpenseRequest true "Изменяемые поля расхода"
+// @Success 200 {object} dto.ExpenseResponse "Расход успешно обновлен"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории, расхода или данные"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/expenses/{expense_id} [patch]
+func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
+	log := logger.New("user_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	expenseID, err := strconv.Atoi(c.Param("expense_id"))
+	if err != nil {
+		log.Error("getting expense_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid expense id",
+		})
+		return
+	}
+	categoryID, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid category id",
+		})
+		return
+	}
+	var req dto.UpdateExpenseRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	updatedExpense, err := h.expenseService.UpdateExpense(ctx, userID, categoryID, expenseID, req)
+	if err != nil {
+		log.Error("updating expense failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("updating expense succeed", map[string]interface{}{
+		"status": http.StatusOK,
 	})
+	c.JSON(http.StatusOK, updatedExpense)
 }
 
 // DeleteExpense godoc
@@ -310,18 +481,20 @@ func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
 
 // GetAnalytics godoc
 // @Summary Получение аналитики расходов
-// @Description Получение аналитики расходов по категории за указанный период
+// @Description Получение аналитики расходов по категории за календарный период или произвольный диапазон дат. Среднее за день считается по фактически прошедшим дням периода
 // @Tags Expenses
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param category_id path int true "ID категории"
-// @Param period body dto.ExpensePeriod true "Период для анализа"
+// @Param period query string false "Период: weekly, monthly, quarterly, yearly (текущие), YYYY-MM, YYYY-Qn, Qn, YYYY. По умолчанию - текущий месяц"
+// @Param from query string false "Начало периода (YYYY-MM-DD), вместо period"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD), вместо period"
 // @Success 200 {object} dto.ExpenseAnalytics "Аналитика расходов"
 // @Failure 400 {object} dto.ErrorResponse "Неверный ID категории или период"
 // @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
-// @Router /categories/{category_id}/expenses/analytics [post]
+// @Router /categories/{category_id}/expenses/analytics [get]
 func (h *ExpenseHandler) GetAnalytics(c *gin.Context) {
 	log := logger.New("user_handler", true)
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
@@ -338,8 +511,8 @@ func (h *ExpenseHandler) GetAnalytics(c *gin.Context) {
 		return
 	}
 	var period dto.ExpensePeriod
-	if err := c.BindJSON(&period); err != nil {
-		log.Error("parsing JSON failed", map[string]interface{}{
+	if err := c.ShouldBindQuery(&period); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
 			"error":  err,
 			"status": http.StatusBadRequest,
 		})
@@ -364,9 +537,9 @@ func (h *ExpenseHandler) GetAnalytics(c *gin.Context) {
 	if err != nil {
 		log.Error("getting expense analytics failed", map[string]interface{}{
 			"error":  err,
-			"status": http.StatusInternalServerError,
+			"status": http.StatusBadRequest,
 		})
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
 		return
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportTimeout - время на чтение всех данных пользователя из базы. Готовый файл передается клиенту
+// после завершения чтения, и передача в это время не входит
+const exportTimeout = 2 * time.Minute
+
+type ExportHandler struct {
+	exportService services.ExportServiceInterface
+}
+
+func NewExportHandler(exportService services.ExportServiceInterface) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// Export godoc
+// @Summary Выгрузка данных пользователя
+// @Description Выгружает все категории, расходы и бюджеты пользователя файлом. Данные читаются из одного снимка базы во временный буфер и передаются после завершения чтения.
+// @Description json - объект dto.ExportResponse, подходит для резервной копии перед удалением аккаунта.
+// @Description csv - разделы categories, expenses и budgets друг за другом: строка с названием раздела, заголовок колонок, записи.
+// @Description xlsx - книга Excel с листами categories, expenses и budgets, суммы записаны числами, даты - датами.
+// @Description from и to ограничивают расходы по дате и бюджеты по пересечению периода, категории выгружаются все
+// @Tags Export
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "Формат: csv, json, xlsx" default(json)
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} dto.ExportResponse "Файл выгрузки"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры выгрузки"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /export [get]
+func (h *ExportHandler) Export(c *gin.Context) {
+	log := logger.New("export_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), exportTimeout)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.ExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	file, err := h.exportService.Export(ctx, userID, req)
+	if err != nil {
+		log.Error("preparing export failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	c.Header("Content-Type", file.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.FileName}))
+	c.Status(http.StatusOK)
+	if err := file.Write(c.Writer); err != nil {
+		// Если данные не удалось прочитать из базы, в ответ еще ничего не записано и можно вернуть ошибку.
+		// Иначе статус ответа уже отправлен, и клиент получает неполный файл
+		if !c.Writer.Written() {
+			log.Error("reading export failed", map[string]interface{}{
+				"error":  err,
+				"status": http.StatusInternalServerError,
+			})
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		log.Error("streaming export failed", map[string]interface{}{
+			"error": err,
+		})
+		c.Abort()
+		return
+	}
+	log.Info("export succeed", map[string]interface{}{
+		"file":   file.FileName,
+		"status": http.StatusOK,
+	})
+}
@@ -3,19 +3,37 @@ package handler
 import "finance/internal/services"
 
 type Handlers struct {
+	AnalyticsHandlerInterface
 	AuthHandlerInterface
 	BudgetHandlerInterface
+	BudgetAlertHandlerInterface
 	CategoryHandlerInterface
 	ExpenseHandlerInterface
+	ExchangeRateHandlerInterface
+	ExportHandlerInterface
+	ImportHandlerInterface
+	IncomeHandlerInterface
+	RecurringExpenseHandlerInterface
+	RuleHandlerInterface
+	TagHandlerInterface
 	UserHandlerInterface
 }
 
 func NewHandlers(service *services.Services) *Handlers {
 	return &Handlers{
-		AuthHandlerInterface:     NewAuthHandler(service.AuthServiceInterface),
-		BudgetHandlerInterface:   NewBudgetHandler(service.BudgetServiceInterface),
-		CategoryHandlerInterface: NewCategoryHandler(service.CategoryServiceInterface),
-		ExpenseHandlerInterface:  NewExpenseHandler(service.ExpenseServiceInterface),
-		UserHandlerInterface:     NewUserHandler(service.UserServiceInterface),
+		AnalyticsHandlerInterface:        NewAnalyticsHandler(service.AnalyticsServiceInterface),
+		AuthHandlerInterface:             NewAuthHandler(service.AuthServiceInterface),
+		BudgetHandlerInterface:           NewBudgetHandler(service.BudgetServiceInterface),
+		BudgetAlertHandlerInterface:      NewBudgetAlertHandler(service.BudgetAlertServiceInterface),
+		CategoryHandlerInterface:         NewCategoryHandler(service.CategoryServiceInterface),
+		ExpenseHandlerInterface:          NewExpenseHandler(service.ExpenseServiceInterface),
+		ExchangeRateHandlerInterface:     NewExchangeRateHandler(service.ExchangeRateServiceInterface),
+		ExportHandlerInterface:           NewExportHandler(service.ExportServiceInterface),
+		ImportHandlerInterface:           NewImportHandler(service.ImportServiceInterface),
+		IncomeHandlerInterface:           NewIncomeHandler(service.IncomeServiceInterface),
+		RecurringExpenseHandlerInterface: NewRecurringExpenseHandler(service.RecurringExpenseServiceInterface),
+		RuleHandlerInterface:             NewRuleHandler(service.RuleServiceInterface),
+		TagHandlerInterface:              NewTagHandler(service.TagServiceInterface),
+		UserHandlerInterface:             NewUserHandler(service.UserServiceInterface),
 	}
 }
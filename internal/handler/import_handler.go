@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importTimeout - время на разбор выписки и на подтверждение или отмену загрузки, в которых тысячи строк
+const importTimeout = 60 * time.Second
+
+type ImportHandler struct {
+	importService services.ImportServiceInterface
+}
+
+func NewImportHandler(importService services.ImportServiceInterface) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+	}
+}
+
+// CreateImport godoc
+// @Summary Загрузка банковской выписки
+// @Description Разбирает выписку CSV или OFX/QFX и возвращает предпросмотр: для каждого расхода предложенная по правилам категория
+// @Description и признак дубликата (расход с той же датой, суммой, валютой и описанием уже есть). Поступления пропускаются.
+// @Description Расходы не создаются, пока загрузка не подтверждена через POST /imports/{import_id}/commit
+// @Tags Imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Файл выписки (.csv, .ofx, .qfx), до 5 МБ"
+// @Param format formData string false "Формат: csv или ofx. По умолчанию по расширению файла"
+// @Param date_column formData string false "Колонка CSV с датой" default(date)
+// @Param amount_column formData string false "Колонка CSV с суммой" default(amount)
+// @Param description_column formData string false "Колонка CSV с описанием" default(description)
+// @Param currency_column formData string false "Колонка CSV с валютой, без нее - базовая валюта"
+// @Param date_format formData string false "Шаблон даты из YYYY, YY, MM, DD, HH, mm, ss" default(YYYY-MM-DD)
+// @Param delimiter formData string false "Разделитель колонок CSV: символ или tab" default(,)
+// @Param decimal_separator formData string false "Десятичный разделитель сумм: . или ," default(.)
+// @Param expense_sign formData string false "negative - расходы со знаком минус, positive - все строки расходы" default(negative)
+// @Param default_category_id formData int false "Категория для операций, к которым не подошло ни одно правило"
+// @Success 200 {object} dto.ImportPreviewResponse "Предпросмотр загрузки"
+// @Failure 400 {object} dto.ErrorResponse "Не удалось разобрать выписку"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /imports [post]
+func (h *ImportHandler) CreateImport(c *gin.Context) {
+	log := logger.New("import_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), importTimeout)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	// Запас сверх размера файла - на остальные поля формы
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxImportFileSize+64<<10)
+	var req dto.ImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		log.Error("parsing form failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Error("getting file failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "file is required",
+		})
+		return
+	}
+	if file.Size > services.MaxImportFileSize {
+		log.Error("statement file is too large", map[string]interface{}{
+			"size":   file.Size,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("file is too large, maximum is %d MB", services.MaxImportFileSize>>20),
+		})
+		return
+	}
+	content, err := file.Open()
+	if err != nil {
+		log.Error("opening file failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer content.Close()
+	preview, err := h.importService.PreviewImport(ctx, userID, file.Filename, content, req)
+	if err != nil {
+		log.Error("parsing statement failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("parsing statement succeed", map[string]interface{}{
+		"import_id": preview.Import.ID,
+		"rows":      preview.Import.RowsCount,
+		"status":    http.StatusOK,
+	})
+	c.JSON(http.StatusOK, preview)
+}
+
+// GetImports godoc
+// @Summary История загрузок выписок
+// @Description Последние загрузки пользователя, сначала новые
+// @Tags Imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.ImportsListResponse "Список загрузок"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /imports [get]
+func (h *ImportHandler) GetImports(c *gin.Context) {
+	log := logger.New("import_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	imports, err := h.importService.GetImports(ctx, userID)
+	if err != nil {
+		log.Error("getting imports failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting imports succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, imports)
+}
+
+// GetImport godoc
+// @Summary Загрузка выписки
+// @Description Загрузка вместе с операциями: предложенные категории, дубликаты и созданные расходы
+// @Tags Imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param import_id path int true "ID загрузки"
+// @Success 200 {object} dto.ImportPreviewResponse "Загрузка"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID загрузки"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Загрузка не найдена"
+// @Router /imports/{import_id} [get]
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	log := logger.New("import_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	importID, err := strconv.Atoi(c.Param("import_id"))
+	if err != nil {
+		log.Error("getting import_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid import id",
+		})
+		return
+	}
+	preview, err := h.importService.GetImport(ctx, userID, importID)
+	if err != nil {
+		log.Error("getting import failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusNotFound,
+		})
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting import succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, preview)
+}
+
+// CommitImport godoc
+// @Summary Подтверждение загрузки выписки
+// @Description Создает расходы из строк загрузки в одной транзакции: при ошибке в любой строке не создается ни один расход.
+// @Description Без решений импортируются все строки, кроме дубликатов, с предложенной категорией, строки без категории - в Uncategorized.
+// @Description Решение по строке может пропустить ее (accept=false), принять дубликат (accept=true) или заменить категорию
+// @Tags Imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param import_id path int true "ID загрузки"
+// @Param decisions body dto.CommitImportRequest false "Решения по отдельным строкам"
+// @Success 200 {object} dto.ImportPreviewResponse "Загрузка подтверждена"
+// @Failure 400 {object} dto.ErrorResponse "Неверные данные или загрузка уже подтверждена"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Router /imports/{import_id}/commit [post]
+func (h *ImportHandler) CommitImport(c *gin.Context) {
+	log := logger.New("import_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), importTimeout)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	importID, err := strconv.Atoi(c.Param("import_id"))
+	if err != nil {
+		log.Error("getting import_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid import id",
+		})
+		return
+	}
+	var req dto.CommitImportRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&req); err != nil {
+			log.Error("parsing JSON failed", map[string]interface{}{
+				"error":  err,
+				"status": http.StatusBadRequest,
+			})
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+	preview, err := h.importService.CommitImport(ctx, userID, importID, req)
+	if err != nil {
+		log.Error("committing import failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("committing import succeed", map[string]interface{}{
+		"import_id": preview.Import.ID,
+		"imported":  preview.Import.ImportedCount,
+		"status":    http.StatusOK,
+	})
+	c.JSON(http.StatusOK, preview)
+}
+
+// UndoImport godoc
+// @Summary Отмена загрузки выписки
+// @Description У подтвержденной загрузки удаляет созданные ею расходы и возвращает их суммы в бюджеты, загрузка остается в истории со статусом undone.
+// @Description Неподтвержденная загрузка удаляется вместе с предпросмотром
+// @Tags Imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param import_id path int true "ID загрузки"
+// @Success 200 {object} dto.UndoImportResponse "Загрузка отменена"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID загрузки или загрузка уже отменена"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Router /imports/{import_id} [delete]
+func (h *ImportHandler) UndoImport(c *gin.Context) {
+	log := logger.New("import_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), importTimeout)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	importID, err := strconv.Atoi(c.Param("import_id"))
+	if err != nil {
+		log.Error("getting import_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid import id",
+		})
+		return
+	}
+	res, err := h.importService.UndoImport(ctx, userID, importID)
+	if err != nil {
+		log.Error("undoing import failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("undoing import succeed", map[string]interface{}{
+		"import_id": res.ImportID,
+		"removed":   res.RemovedExpenses,
+		"status":    http.StatusOK,
+	})
+	c.JSON(http.StatusOK, res)
+}
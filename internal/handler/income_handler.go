@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IncomeHandler struct {
+	incomeService services.IncomeServiceInterface
+}
+
+func NewIncomeHandler(incomeService services.IncomeServiceInterface) *IncomeHandler {
+	return &IncomeHandler{
+		incomeService: incomeService,
+	}
+}
+
+// CreateIncome godoc
+// @Summary Создание дохода
+// @Description Добавление записи о доходе: источник, сумма, дата и описание
+// @Tags Incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param income body dto.CreateIncomeRequest true "Данные для создания дохода"
+// @Success 200 {object} dto.IncomeResponse "Доход успешно создан"
+// @Failure 400 {object} dto.ErrorResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /incomes [post]
+func (h *IncomeHandler) CreateIncome(c *gin.Context) {
+	log := logger.New("income_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.CreateIncomeRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	income, err := h.incomeService.CreateIncome(ctx, userID, req)
+	if err != nil {
+		log.Error("creating income failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("creating income succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, income)
+}
+
+// GetIncomes godoc
+// @Summary Получение списка доходов
+// @Description Получение всех доходов пользователя, отсортированных по дате
+// @Tags Incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.IncomesListResponse "Список доходов"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /incomes [get]
+func (h *IncomeHandler) GetIncomes(c *gin.Context) {
+	log := logger.New("income_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	incomes, err := h.incomeService.GetIncomes(ctx, userID)
+	if err != nil {
+		log.Error("getting incomes failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting incomes succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, dto.IncomesListResponse{
+		Incomes: incomes,
+	})
+}
+
+// GetIncome godoc
+// @Summary Получение дохода по ID
+// @Description Получение информации о конкретном доходе
+// @Tags Incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param income_id path int true "ID дохода"
+// @Success 200 {object} dto.IncomeResponse "Информация о доходе"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID дохода"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Доход не найден"
+// @Router /incomes/{income_id} [get]
+func (h *IncomeHandler) GetIncome(c *gin.Context) {
+	log := logger.New("income_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	incomeID, err := strconv.Atoi(c.Param("income_id"))
+	if err != nil {
+		log.Error("getting income_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid income id",
+		})
+		return
+	}
+	income, err := h.incomeService.GetIncome(ctx, userID, incomeID)
+	if err != nil {
+		log.Error("getting income failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusNotFound,
+		})
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting income succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, income)
+}
+
+// UpdateIncome godoc
+// @Summary Обновление дохода
+// @Description Частичное обновление дохода: источник, сумма, описание и дата
+// @Tags Incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param income_id path int true "ID дохода"
+// @Param income body dto.UpdateIncomeRequest true "Изменяемые поля дохода"
+// @Success 200 {object} dto.IncomeResponse "Доход успешно обновлен"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID дохода или данные"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /incomes/{income_id} [patch]
+func (h *IncomeHandler) UpdateIncome(c *gin.Context) {
+	log := logger.New("income_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	incomeID, err := strconv.Atoi(c.Param("income_id"))
+	if err != nil {
+		log.Error("getting income_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid income id",
+		})
+		return
+	}
+	var req dto.UpdateIncomeRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	income, err := h.incomeService.UpdateIncome(ctx, userID, incomeID, req)
+	if err != nil {
+		log.Error("updating income failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("updating income succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, income)
+}
+
+// DeleteIncome godoc
+// @Summary Удаление дохода
+// @Description Удаление конкретного дохода пользователя
+// @Tags Incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param income_id path int true "ID дохода"
+// @Success 200 {object} map[string]string "Доход успешно удален"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID дохода"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /incomes/{income_id} [delete]
+func (h *IncomeHandler) DeleteIncome(c *gin.Context) {
+	log := logger.New("income_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	incomeID, err := strconv.Atoi(c.Param("income_id"))
+	if err != nil {
+		log.Error("getting income_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid income id",
+		})
+		return
+	}
+	if err := h.incomeService.DeleteIncome(ctx, userID, incomeID); err != nil {
+		log.Error("deleting income failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("deleting income succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "income deleted successfully",
+	})
+}
+
+// GetCashFlow godoc
+// @Summary Движение денежных средств
+// @Description Доходы, расходы и чистый поток (доходы минус расходы) по интервалам за период. По умолчанию - последние 12 месяцев по месяцам
+// @Tags Incomes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Param granularity query string false "Интервал: day, week, month, quarter, year" default(month)
+// @Success 200 {object} dto.CashFlowResponse "Движение денежных средств"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры периода"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /cashflow [get]
+func (h *IncomeHandler) GetCashFlow(c *gin.Context) {
+	log := logger.New("income_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.CashFlowRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	cashflow, err := h.incomeService.GetCashFlow(ctx, userID, req)
+	if err != nil {
+		log.Error("getting cash flow failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting cash flow succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, cashflow)
+}
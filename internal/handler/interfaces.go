@@ -4,17 +4,40 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+type AnalyticsHandlerInterface interface {
+	GetTimeSeries(c *gin.Context)
+	GetTrends(c *gin.Context)
+	GetCategoryShares(c *gin.Context)
+}
+
 type AuthHandlerInterface interface {
 	SignUp(c *gin.Context)
 	SignIn(c *gin.Context)
+	Refresh(c *gin.Context)
 	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	GetSessions(c *gin.Context)
+	DeleteSession(c *gin.Context)
 }
 
 type BudgetHandlerInterface interface {
 	CreateBudget(c *gin.Context)
 	GetBudgets(c *gin.Context)
-	//UpdateBudget(c *gin.Context)
+	UpdateBudget(c *gin.Context)
 	DeleteBudget(c *gin.Context)
+	GetBudgetsStatus(c *gin.Context)
+	RecalculateAllBudgets(c *gin.Context)
+	GetBudgetHistory(c *gin.Context)
+	CreateCrossCategoryBudget(c *gin.Context)
+	GetAllBudgets(c *gin.Context)
+	UpdateBudgetByID(c *gin.Context)
+	DeleteBudgetByID(c *gin.Context)
+	GetBudgetHistoryByID(c *gin.Context)
+}
+
+type BudgetAlertHandlerInterface interface {
+	GetAlerts(c *gin.Context)
+	AcknowledgeAlert(c *gin.Context)
 }
 
 type CategoryHandlerInterface interface {
@@ -29,13 +52,62 @@ type CategoryHandlerInterface interface {
 type ExpenseHandlerInterface interface {
 	CreateExpense(c *gin.Context)
 	GetExpenses(c *gin.Context)
+	GetAllExpenses(c *gin.Context)
 	GetExpense(c *gin.Context)
+	UpdateExpense(c *gin.Context)
 	DeleteExpense(c *gin.Context)
 	GetAnalytics(c *gin.Context)
 }
 
+type ExchangeRateHandlerInterface interface {
+	ImportExchangeRates(c *gin.Context)
+}
+
+type ExportHandlerInterface interface {
+	Export(c *gin.Context)
+}
+
+type ImportHandlerInterface interface {
+	CreateImport(c *gin.Context)
+	GetImports(c *gin.Context)
+	GetImport(c *gin.Context)
+	CommitImport(c *gin.Context)
+	UndoImport(c *gin.Context)
+}
+
+type IncomeHandlerInterface interface {
+	CreateIncome(c *gin.Context)
+	GetIncomes(c *gin.Context)
+	GetIncome(c *gin.Context)
+	UpdateIncome(c *gin.Context)
+	DeleteIncome(c *gin.Context)
+	GetCashFlow(c *gin.Context)
+}
+
+type RecurringExpenseHandlerInterface interface {
+	CreateRecurringExpense(c *gin.Context)
+	GetRecurringExpenses(c *gin.Context)
+	GetRecurringExpense(c *gin.Context)
+	UpdateRecurringExpense(c *gin.Context)
+	DeleteRecurringExpense(c *gin.Context)
+}
+
+type RuleHandlerInterface interface {
+	CreateRule(c *gin.Context)
+	GetRules(c *gin.Context)
+	DeleteRule(c *gin.Context)
+	TestRule(c *gin.Context)
+	ApplyRules(c *gin.Context)
+	CreateExpense(c *gin.Context)
+}
+
+type TagHandlerInterface interface {
+	GetTagAnalytics(c *gin.Context)
+}
+
 type UserHandlerInterface interface {
 	GetProfile(c *gin.Context)
 	GetStats(c *gin.Context)
 	DeleteAccount(c *gin.Context)
+	UpdateBaseCurrency(c *gin.Context)
 }
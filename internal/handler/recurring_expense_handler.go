@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RecurringExpenseHandler struct {
+	recurringExpenseService services.RecurringExpenseServiceInterface
+}
+
+func NewRecurringExpenseHandler(recurringExpenseService services.RecurringExpenseServiceInterface) *RecurringExpenseHandler {
+	return &RecurringExpenseHandler{
+		recurringExpenseService: recurringExpenseService,
+	}
+}
+
+// CreateRecurringExpense godoc
+// @Summary Создание регулярного расхода
+// @Description Создание регулярного расхода в указанной категории. Поддерживаются расписания daily, weekly, monthly (в заданный день месяца) и yearly. Наступившие платежи автоматически превращаются в обычные расходы
+// @Tags RecurringExpenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Param recurring body dto.CreateRecurringExpenseRequest true "Данные регулярного расхода"
+// @Success 200 {object} dto.RecurringExpenseResponse "Регулярный расход успешно создан"
+// @Failure 400 {object} dto.ErrorResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/recurring [post]
+func (h *RecurringExpenseHandler) CreateRecurringExpense(c *gin.Context) {
+	log := logger.New("recurring_expense_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	category_id, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid category id",
+		})
+		return
+	}
+	var req dto.CreateRecurringExpenseRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	recurring, err := h.recurringExpenseService.CreateRecurringExpense(ctx, userID, category_id, req)
+	if err != nil {
+		log.Error("creating recurring expense failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("creating recurring expense succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, recurring)
+}
+
+// GetRecurringExpenses godoc
+// @Summary Получение списка регулярных расходов
+// @Description Получение всех регулярных расходов пользователя в указанной категории (0 - во всех категориях)
+// @Tags RecurringExpenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Success 200 {object} dto.RecurringExpensesListResponse "Список регулярных расходов"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/recurring [get]
+func (h *RecurringExpenseHandler) GetRecurringExpenses(c *gin.Context) {
+	log := logger.New("recurring_expense_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	category_id, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid category id",
+		})
+		return
+	}
+	recurring_expenses, err := h.recurringExpenseService.GetRecurringExpenses(ctx, userID, category_id)
+	if err != nil {
+		log.Error("getting recurring expenses failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting recurring expenses succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, dto.RecurringExpensesListResponse{
+		RecurringExpenses: recurring_expenses,
+	})
+}
+
+// GetRecurringExpense godoc
+// @Summary Получение регулярного расхода по ID
+// @Description Получение информации о регулярном расходе, включая дату следующего платежа
+// @Tags RecurringExpenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Param recurring_id path int true "ID регулярного расхода"
+// @Success 200 {object} dto.RecurringExpenseResponse "Информация о регулярном расходе"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории или регулярного расхода"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Регулярный расход не найден"
+// @Router /categories/{category_id}/recurring/{recurring_id} [get]
+func (h *RecurringExpenseHandler) GetRecurringExpense(c *gin.Context) {
+	log := logger.New("recurring_expense_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	category_id, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid category id",
+		})
+		return
+	}
+	recurringID, err := strconv.Atoi(c.Param("recurring_id"))
+	if err != nil {
+		log.Error("getting recurring_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid recurring expense id",
+		})
+		return
+	}
+	recurring, err := h.recurringExpenseService.GetRecurringExpense(ctx, userID, category_id, recurringID)
+	if err != nil {
+		log.Error("getting recurring expense failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusNotFound,
+		})
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting recurring expense succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, recurring)
+}
+
+// UpdateRecurringExpense godoc
+// @Summary Обновление регулярного расхода
+// @Description Частичное обновление регулярного расхода. Новое расписание применяется начиная с ближайшего еще не созданного платежа. is_active=false приостанавливает создание расходов
+// @Tags RecurringExpenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Param recurring_id path int true "ID регулярного расхода"
+// @Param recurring body dto.UpdateRecurringExpenseRequest true "Изменяемые поля регулярного расхода"
+// @Success 200 {object} dto.RecurringExpenseResponse "Регулярный расход успешно обновлен"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID или данные"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/recurring/{recurring_id} [patch]
+func (h *RecurringExpenseHandler) UpdateRecurringExpense(c *gin.Context) {
+	log := logger.New("recurring_expense_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	category_id, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid category id",
+		})
+		return
+	}
+	recurringID, err := strconv.Atoi(c.Param("recurring_id"))
+	if err != nil {
+		log.Error("getting recurring_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid recurring expense id",
+		})
+		return
+	}
+	var req dto.UpdateRecurringExpenseRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	recurring, err := h.recurringExpenseService.UpdateRecurringExpense(ctx, userID, category_id, recurringID, req)
+	if err != nil {
+		log.Error("updating recurring expense failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("updating recurring expense succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, recurring)
+}
+
+// DeleteRecurringExpense godoc
+// @Summary Удаление регулярного расхода
+// @Description Удаление регулярного расхода. Уже созданные по нему расходы сохраняются
+// @Tags RecurringExpenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category_id path int true "ID категории"
+// @Param recurring_id path int true "ID регулярного расхода"
+// @Success 200 {object} map[string]string "Регулярный расход успешно удален"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID категории или регулярного расхода"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /categories/{category_id}/recurring/{recurring_id} [delete]
+func (h *RecurringExpenseHandler) DeleteRecurringExpense(c *gin.Context) {
+	log := logger.New("recurring_expense_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	category_id, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		log.Error("getting category_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid category id",
+		})
+		return
+	}
+	recurringID, err := strconv.Atoi(c.Param("recurring_id"))
+	if err != nil {
+		log.Error("getting recurring_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid recurring expense id",
+		})
+		return
+	}
+	if err := h.recurringExpenseService.DeleteRecurringExpense(ctx, userID, category_id, recurringID); err != nil {
+		log.Error("deleting recurring expense failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("deleting recurring expense succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "recurring expense deleted successfully",
+	})
+}
@@ -0,0 +1,342 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RuleHandler struct {
+	ruleService services.RuleServiceInterface
+}
+
+func NewRuleHandler(ruleService services.RuleServiceInterface) *RuleHandler {
+	return &RuleHandler{
+		ruleService: ruleService,
+	}
+}
+
+// CreateRule godoc
+// @Summary Создание правила категоризации
+// @Description Расходы из выписки и из POST /expenses, которые подходят под все условия правила, получают его категорию и теги.
+// @Description Условия: pattern в описании (match_type contains - подстрока, regex - регулярное выражение, регистр не важен) и сумма от min_amount до max_amount.
+// @Description Если подходят несколько правил, применяется правило с большим priority, при равном - созданное раньше
+// @Tags Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule body dto.CreateRuleRequest true "Данные правила"
+// @Success 200 {object} dto.RuleResponse "Правило создано"
+// @Failure 400 {object} dto.ErrorResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /rules [post]
+func (h *RuleHandler) CreateRule(c *gin.Context) {
+	log := logger.New("rule_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.CreateRuleRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	rule, err := h.ruleService.CreateRule(ctx, userID, req)
+	if err != nil {
+		log.Error("creating rule failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("creating rule succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, rule)
+}
+
+// GetRules godoc
+// @Summary Правила категоризации
+// @Description Правила пользователя в порядке применения
+// @Tags Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.RulesListResponse "Список правил"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /rules [get]
+func (h *RuleHandler) GetRules(c *gin.Context) {
+	log := logger.New("rule_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	rules, err := h.ruleService.GetRules(ctx, userID)
+	if err != nil {
+		log.Error("getting rules failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting rules succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, rules)
+}
+
+// DeleteRule godoc
+// @Summary Удаление правила категоризации
+// @Description Удаляет правило. Уже созданные расходы не меняются
+// @Tags Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule_id path int true "ID правила"
+// @Success 200 {object} map[string]string "Правило удалено"
+// @Failure 400 {object} dto.ErrorResponse "Неверный ID правила"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 404 {object} dto.ErrorResponse "Правило не найдено"
+// @Router /rules/{rule_id} [delete]
+func (h *RuleHandler) DeleteRule(c *gin.Context) {
+	log := logger.New("rule_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	ruleID, err := strconv.Atoi(c.Param("rule_id"))
+	if err != nil {
+		log.Error("getting rule_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid rule id",
+		})
+		return
+	}
+	if err := h.ruleService.DeleteRule(ctx, userID, ruleID); err != nil {
+		log.Error("deleting rule failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusNotFound,
+		})
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("deleting rule succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "rule deleted",
+	})
+}
+
+// TestRule godoc
+// @Summary Проверка правил категоризации
+// @Description Показывает, какое правило сработает для примера операции, и в какую категорию с какими тегами попадет расход. Ничего не изменяет
+// @Tags Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sample body dto.TestRuleRequest true "Пример операции"
+// @Success 200 {object} dto.TestRuleResponse "Результат проверки"
+// @Failure 400 {object} dto.ErrorResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /rules/test [post]
+func (h *RuleHandler) TestRule(c *gin.Context) {
+	log := logger.New("rule_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.TestRuleRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	result, err := h.ruleService.TestRule(ctx, userID, req)
+	if err != nil {
+		log.Error("testing rules failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("testing rules succeed", map[string]interface{}{
+		"matched": result.Matched,
+		"status":  http.StatusOK,
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+// ApplyRules godoc
+// @Summary Применение правил к расходам без категории
+// @Description Заново применяет правила к расходам категории Uncategorized: подходящие расходы переносятся в категорию правила,
+// @Description получают его теги, бюджеты пересчитываются. Все переносы выполняются в одной транзакции
+// @Tags Rules
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.ApplyRulesResponse "Результат применения"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /rules/apply [post]
+func (h *RuleHandler) ApplyRules(c *gin.Context) {
+	log := logger.New("rule_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	result, err := h.ruleService.ApplyRules(ctx, userID)
+	if err != nil {
+		log.Error("applying rules failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("applying rules succeed", map[string]interface{}{
+		"checked":     result.Checked,
+		"categorized": result.Categorized,
+		"status":      http.StatusOK,
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+// CreateExpense godoc
+// @Summary Создание расхода с автоматической категорией
+// @Description Категория и дополнительные теги расхода выбираются правилами категоризации (/rules).
+// @Description Если ни одно правило не подошло, расход попадает в категорию Uncategorized, которая создается при необходимости
+// @Tags Expenses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param expense body dto.CreateExpenseRequest true "Данные расхода"
+// @Success 200 {object} dto.CategorizedExpenseResponse "Расход создан"
+// @Failure 400 {object} dto.ErrorResponse "Ошибка валидации данных"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Router /expenses [post]
+func (h *RuleHandler) CreateExpense(c *gin.Context) {
+	log := logger.New("rule_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.CreateExpenseRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	expense, err := h.ruleService.CreateExpense(ctx, userID, req)
+	if err != nil {
+		log.Error("creating categorized expense failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("creating categorized expense succeed", map[string]interface{}{
+		"category_id": expense.CategoryID,
+		"status":      http.StatusOK,
+	})
+	c.JSON(http.StatusOK, expense)
+}
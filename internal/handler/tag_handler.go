@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/middleware"
+	"finance/internal/services"
+	"finance/pkg/logger"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService services.TagServiceInterface
+}
+
+func NewTagHandler(tagService services.TagServiceInterface) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// GetTagAnalytics godoc
+// @Summary Аналитика расходов по тегам
+// @Description Сумма и количество расходов по каждому тегу во всех категориях. Расход с несколькими тегами учитывается в каждом из них
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Начало периода (YYYY-MM-DD)"
+// @Param to query string false "Конец периода включительно (YYYY-MM-DD)"
+// @Success 200 {object} dto.TagAnalyticsResponse "Расходы по тегам"
+// @Failure 400 {object} dto.ErrorResponse "Неверные параметры периода"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /tags/analytics [get]
+func (h *TagHandler) GetTagAnalytics(c *gin.Context) {
+	log := logger.New("tag_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	var req dto.TagAnalyticsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error("parsing query failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	analytics, err := h.tagService.GetTagAnalytics(ctx, userID, req)
+	if err != nil {
+		log.Error("getting tag analytics failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	log.Info("getting tag analytics succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, analytics)
+}
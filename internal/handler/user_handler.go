@@ -59,16 +59,17 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 		"status": http.StatusOK,
 	})
 	c.JSON(http.StatusOK, dto.UserProfile{
-		Email:     profile.Email,
-		FirstName: profile.FirstName,
-		LastName:  profile.LastName,
-		CreatedAt: profile.CreatedAt,
+		Email:        profile.Email,
+		FirstName:    profile.FirstName,
+		LastName:     profile.LastName,
+		CreatedAt:    profile.CreatedAt,
+		BaseCurrency: profile.BaseCurrency,
 	})
 }
 
 // DeleteAccount godoc
 // @Summary Удаление аккаунта пользователя
-// @Description Полное удаление аккаунта пользователя и всех связанных данных
+// @Description Полное удаление аккаунта пользователя и всех связанных данных. Все выданные токены отзываются. Резервную копию можно получить заранее через GET /export?format=json
 // @Tags User
 // @Accept json
 // @Produce json
@@ -77,7 +78,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 // @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
 // @Failure 500 {object} dto.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /user/account [delete]
-func (h *UserHandler) DeleteAccount(c *gin.Context) { // нужно придумать, как диактивировать токены, которые были выданы
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
 	log := logger.New("user_handler", true)
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
@@ -99,6 +100,7 @@ func (h *UserHandler) DeleteAccount(c *gin.Context) { // нужно придум
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	clearRefreshTokenCookie(c)
 	log.Info("deleting user account succeed", map[string]interface{}{
 		"status": http.StatusOK,
 	})
@@ -142,12 +144,56 @@ func (h *UserHandler) GetStats(c *gin.Context) {
 	log.Info("getting user stats succeed", map[string]interface{}{
 		"status": http.StatusOK,
 	})
-	c.JSON(http.StatusOK, dto.UserStats{
-		TotalExpenses:   stats.TotalExpenses,
-		TotalCategories: stats.TotalCategories,
-		TotalBudgets:    stats.TotalBudgets,
-		MonthlyExpenses: stats.MonthlyExpenses,
-		WeeklyExpenses:  stats.WeeklyExpenses,
-	})
+	c.JSON(http.StatusOK, stats)
+
+}
 
+// UpdateBaseCurrency godoc
+// @Summary Смена базовой валюты
+// @Description Меняет валюту, в которую пересчитываются расходы для аналитики и статистики, и пересчитывает все расходы пользователя по курсам на их даты.
+// @Description Если хотя бы для одного расхода нет курса, валюта не меняется. Лимиты бюджетов остаются в своих валютах
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param currency body dto.UpdateBaseCurrencyRequest true "Новая базовая валюта"
+// @Success 200 {object} dto.UserProfile "Профиль пользователя"
+// @Failure 400 {object} dto.ErrorResponse "Неверный код валюты или нет курса"
+// @Failure 401 {object} dto.ErrorResponse "Требуется авторизация"
+// @Router /user/base-currency [put]
+func (h *UserHandler) UpdateBaseCurrency(c *gin.Context) {
+	log := logger.New("user_handler", true)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	userID, err := middleware.GetUserId(c)
+	if err != nil {
+		log.Error("getting user_id failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusInternalServerError,
+		})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var req dto.UpdateBaseCurrencyRequest
+	if err := c.BindJSON(&req); err != nil {
+		log.Error("parsing JSON failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	profile, err := h.userService.SetBaseCurrency(ctx, userID, req.Currency)
+	if err != nil {
+		log.Error("updating base currency failed", map[string]interface{}{
+			"error":  err,
+			"status": http.StatusBadRequest,
+		})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	log.Info("updating base currency succeed", map[string]interface{}{
+		"status": http.StatusOK,
+	})
+	c.JSON(http.StatusOK, profile)
 }
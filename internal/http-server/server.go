@@ -26,7 +26,15 @@ type Server struct {
 }
 
 func NewServer(container *container.Container) *Server {
+	log := logger.New("http-server", true)
 	router := gin.Default()
+	// По умолчанию gin доверяет X-Forwarded-For от любого клиента, и IP сеанса можно подделать
+	if err := router.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		log.Error("Invalid TRUSTED_PROXIES, proxy headers are ignored", map[string]interface{}{
+			"error": err,
+		})
+		router.SetTrustedProxies(nil)
+	}
 
 	return &Server{
 		container: container,
@@ -42,6 +50,11 @@ func (s *Server) Run() error {
 		return err
 	}
 
+	// Фоновые задачи работают, пока не отменен jobsCtx
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	s.container.Scheduler.Start(jobsCtx)
+
 	// Канал для ошибок сервера
 	serverErr := make(chan error, 1)
 	go func() {
@@ -81,6 +94,8 @@ func (s *Server) Run() error {
 				"error": logger.PrettyPrint(err),
 			})
 		}
+		stopJobs()
+		s.container.Scheduler.Wait()
 		log.Info("Server gracefully stopped", nil)
 		return nil
 	}
@@ -94,13 +109,29 @@ func (s *Server) setupRoutes() {
 	// Public routes
 	routes.SetupAuthRoutes(api, s.container.Handlers.AuthHandlerInterface)
 
+	// Admin routes
+	admin := api.Group("/admin")
+	admin.Use(middleware.AdminMiddleware())
+	{
+		routes.SetupAdminRoutes(admin, s.container.Handlers.ExchangeRateHandlerInterface, s.container.Handlers.BudgetHandlerInterface)
+	}
+
 	// Protected routes
 	protected := api.Group("")
 	protected.Use(middleware.AuthMiddleware(s.container.Services.AuthServiceInterface))
 	{
+		routes.SetupSessionRoutes(protected, s.container.Handlers.AuthHandlerInterface)
 		routes.SetupUserRoutes(protected, s.container.Handlers.UserHandlerInterface)
 		routes.SetupCategoryRoutes(protected, s.container.Handlers.CategoryHandlerInterface)
 		routes.SetupExpenseRoutes(protected, s.container.Handlers.ExpenseHandlerInterface)
 		routes.SetupBudgetRoutes(protected, s.container.Handlers.BudgetHandlerInterface)
+		routes.SetupBudgetAlertRoutes(protected, s.container.Handlers.BudgetAlertHandlerInterface)
+		routes.SetupIncomeRoutes(protected, s.container.Handlers.IncomeHandlerInterface)
+		routes.SetupRecurringExpenseRoutes(protected, s.container.Handlers.RecurringExpenseHandlerInterface)
+		routes.SetupTagRoutes(protected, s.container.Handlers.TagHandlerInterface)
+		routes.SetupAnalyticsRoutes(protected, s.container.Handlers.AnalyticsHandlerInterface)
+		routes.SetupImportRoutes(protected, s.container.Handlers.ImportHandlerInterface)
+		routes.SetupRuleRoutes(protected, s.container.Handlers.RuleHandlerInterface)
+		routes.SetupExportRoutes(protected, s.container.Handlers.ExportHandlerInterface)
 	}
 }
@@ -2,146 +2,99 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"finance/internal/dto"
 	"finance/internal/services"
 	"finance/pkg/logger"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// AuthMiddleware пропускает запрос только с действительным access-токеном в заголовке Authorization.
+// Истекший access-токен клиент обменивает на новый через POST /auth/refresh
 func AuthMiddleware(authService services.AuthServiceInterface) gin.HandlerFunc {
 	return gin.HandlerFunc(func(c *gin.Context) {
 		log := logger.New("middleware", true)
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			// Извлекаем токен из заголовка "Bearer TOKEN"
-			tokenParts := strings.Split(authHeader, " ")
-			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-				log.Error("Invalid authorization header format", map[string]interface{}{
-					"header": authHeader,
-					"status": http.StatusUnauthorized,
-				})
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-				c.Abort()
-				return
-			} else {
-				req := dto.AccessTokenRequest{
-					AccessToken: tokenParts[1],
-				}
-
-				// Валидация токена через сервис
-				userID, err := authService.ValidateToken(ctx, req)
-				if err != nil {
-					log.Error("Invalid token", map[string]interface{}{
-						"error":  err,
-						"status": http.StatusUnauthorized,
-					})
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-					c.Abort()
-					return
-				}
-				c.Set("user_id", uint(userID.UserID))
-				c.Next()
-				return
-			}
-		} else {
-			refresh_token, err := c.Cookie("refresh_token")
-			if err != nil {
-				log.Error("Refresh token is required", map[string]interface{}{
-					"error":  err,
-					"status": http.StatusUnauthorized,
-				})
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token is required"})
-				c.Abort()
-				return
-			}
-			if refresh_token != "" {
-				userID, err := authService.GetUserIDbyRefreshToken(ctx, refresh_token) //нужно проверку сделать что refresh_token не истек
-				if err != nil {
-					log.Error("Invalid refresh token", map[string]interface{}{
-						"error":  err,
-						"status": http.StatusUnauthorized,
-					})
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
-					c.Abort()
-					return
-				}
-				if userID != 0 {
-					err = authService.RemoveOldRefreshToken(ctx, userID)
-					if err != nil {
-						log.Error("Failed to remove old refresh token", map[string]interface{}{
-							"error":  err,
-							"status": http.StatusInternalServerError,
-						})
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove old refresh token"})
-						c.Abort()
-						return
-					}
-					new_access_token, err := authService.GenerateAccessToken(userID)
-					if err != nil {
-						log.Error("Failed to generate new access token", map[string]interface{}{
-							"error":  err,
-							"status": http.StatusInternalServerError,
-						})
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate new access token"})
-						c.Abort()
-						return
-					}
-					new_refresh_token, err := authService.GenerateRefreshToken()
-					if err != nil {
-						log.Error("Failed to generate new refresh token", map[string]interface{}{
-							"error":  err,
-							"status": http.StatusInternalServerError,
-						})
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate new refresh token"})
-						c.Abort()
-						return
-					}
-					err = authService.SaveNewRefreshToken(ctx, userID, new_refresh_token)
-					if err != nil {
-						log.Error("Failed to save new refresh token", map[string]interface{}{
-							"error":  err,
-							"status": http.StatusInternalServerError,
-						})
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save new refresh token"})
-						c.Abort()
-						return
-					}
-					c.Header("Authorization", "Bearer "+new_access_token.AccessToken)
-					SetRefreshTokenCookie(c, new_refresh_token.RefreshToken)
-					c.Set("user_id", uint(userID))
-					c.Next()
-					return
-
-				} else {
-					log.Error("Authorization is required", map[string]interface{}{
-						"error":  err,
-						"status": http.StatusUnauthorized,
-					})
-					c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization is required"})
-					c.Abort()
-					return
-				}
-
-			} else {
-				log.Error("Authorization is required", map[string]interface{}{
-					"error":  err,
-					"status": http.StatusUnauthorized,
-				})
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization is required"})
-				c.Abort()
-				return
+		if authHeader == "" {
+			log.Error("Authorization is required", map[string]interface{}{
+				"status": http.StatusUnauthorized,
+			})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization is required"})
+			c.Abort()
+			return
+		}
+		// Извлекаем токен из заголовка "Bearer TOKEN"
+		tokenParts := strings.Split(authHeader, " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			log.Error("Invalid authorization header format", map[string]interface{}{
+				"header": authHeader,
+				"status": http.StatusUnauthorized,
+			})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
+			c.Abort()
+			return
+		}
+		req := dto.AccessTokenRequest{
+			AccessToken: tokenParts[1],
+		}
 
-			}
+		// Валидация токена через сервис
+		userID, err := authService.ValidateToken(ctx, req)
+		if err != nil {
+			log.Error("Invalid token", map[string]interface{}{
+				"error":  err,
+				"status": http.StatusUnauthorized,
+			})
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+		// Время последнего использования сеанса не должно мешать самому запросу
+		if err := authService.TouchSession(ctx, userID.UserID, userID.SessionID); err != nil {
+			log.Error("Updating session last use failed", map[string]interface{}{
+				"error":   err,
+				"user_id": userID.UserID,
+			})
 		}
+		c.Set("user_id", uint(userID.UserID))
+		c.Set("session_id", userID.SessionID)
+		c.Next()
+	})
+}
 
+// AdminMiddleware пропускает запрос, только если заголовок X-Admin-Token совпадает с переменной окружения ADMIN_TOKEN.
+// Если ADMIN_TOKEN не задан, административные маршруты закрыты
+func AdminMiddleware() gin.HandlerFunc {
+	return gin.HandlerFunc(func(c *gin.Context) {
+		log := logger.New("middleware", true)
+		adminToken := os.Getenv("ADMIN_TOKEN")
+		if adminToken == "" {
+			log.Error("Admin token is not configured", map[string]interface{}{
+				"status": http.StatusForbidden,
+			})
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access is disabled"})
+			c.Abort()
+			return
+		}
+		token := c.GetHeader("X-Admin-Token")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			log.Error("Invalid admin token", map[string]interface{}{
+				"status": http.StatusForbidden,
+			})
+			c.JSON(http.StatusForbidden, gin.H{"error": "Invalid admin token"})
+			c.Abort()
+			return
+		}
+		c.Next()
 	})
 }
 
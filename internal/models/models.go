@@ -1,30 +1,55 @@
 package models
 
 import (
+	"finance/pkg/money"
 	"time"
 )
 
 type Expense struct {
-	ID           uint      `json:"id"`
-	UserID       uint      `json:"user_id"`
-	CategoryID   uint      `json:"category_id"`
-	CategoryName string    `json:"category_name"`
-	Amount       float64   `json:"amount"`
-	Description  string    `json:"description"`
-	Date         time.Time `json:"date"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint         `json:"id"`
+	UserID       uint         `json:"user_id"`
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Amount       money.Amount `json:"amount"`
+	Description  string       `json:"description"`
+	Date         time.Time    `json:"date"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	Tags         []string     `json:"tags,omitempty"`
+	// Currency - валюта, в которой внесен расход; Amount хранится в ней
+	Currency string `json:"currency"`
+	// BaseAmount - сумма расхода в базовой валюте пользователя по курсу на дату расхода
+	BaseAmount money.Amount `json:"base_amount"`
 }
 
 type Budget struct {
-	ID          uint      `json:"budget_id"`
-	UserID      uint      `json:"user_id"`
-	CategoryID  uint      `json:"category_id"`
-	Amount      float64   `json:"amount"`
-	SpentAmount float64   `json:"spent_amount"`
-	Period      string    `json:"period"` // monthly, weekly, yearly
-	StartDate   time.Time `json:"start_date,omitempty"`
-	EndDate     time.Time `json:"end_date,omitempty"`
+	ID           uint         `json:"budget_id"`
+	UserID       uint         `json:"user_id"`
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Amount       money.Amount `json:"amount"`
+	SpentAmount  money.Amount `json:"spent_amount"`
+	Period       string       `json:"period"` // monthly, weekly, yearly
+	StartDate    time.Time    `json:"start_date,omitempty"`
+	EndDate      time.Time    `json:"end_date,omitempty"`
+	// Проценты расходования бюджета, при достижении которых создаются уведомления
+	AlertThresholds []int `json:"alert_thresholds"`
+	// AutoRenew - по окончании периода бюджет переходит на следующий период
+	AutoRenew bool `json:"auto_renew"`
+	// CarryOver - остаток (или перерасход) периода переносится в следующий период
+	CarryOver bool `json:"carry_over"`
+	// CarriedAmount - сумма, перенесенная из предыдущего периода; лимит периода равен Amount + CarriedAmount
+	CarriedAmount money.Amount `json:"carried_amount"`
+	// Точки отсчета периода: день недели (0 - воскресенье), день месяца и месяц начала года
+	WeekStart      int `json:"week_start"`
+	MonthStartDay  int `json:"month_start_day"`
+	YearStartMonth int `json:"year_start_month"`
+	// Scope - что учитывает бюджет: category (одна категория CategoryID), group (категории CategoryIDs) или overall (все расходы)
+	Scope       string `json:"scope"`
+	Name        string `json:"name"`
+	CategoryIDs []uint `json:"category_ids"`
+	// Currency - валюта лимита и потраченной суммы бюджета
+	Currency string `json:"currency"`
 	//CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -35,11 +60,11 @@ type Category struct {
 	// Timestamps
 	CreatedAt time.Time `json:"created_at"`
 	// Relationships
-	User         User      `json:"user,omitempty"`
-	Expenses     []Expense `json:"expenses,omitempty"`
-	Budgets      []Budget  `json:"budgets,omitempty"`
-	ExpenseCount int       `json:"expense_count"`
-	TotalAmount  float64   `json:"total_amount"`
+	User         User         `json:"user,omitempty"`
+	Expenses     []Expense    `json:"expenses,omitempty"`
+	Budgets      []Budget     `json:"budgets,omitempty"`
+	ExpenseCount int          `json:"expense_count"`
+	TotalAmount  money.Amount `json:"total_amount"`
 }
 
 type AccessToken struct {
@@ -50,15 +75,224 @@ type AccessToken struct {
 }
 
 type RefreshToken struct {
-	Token     string    `json:"refresh_token"`
+	ID     int    `json:"id"`
+	UserID int    `json:"user_id"`
+	Token  string `json:"refresh_token"`
+	// FamilyID - общий идентификатор всех токенов, полученных обновлением от одного входа
+	FamilyID  string    `json:"family_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	// RotatedAt - когда токен был обменян на новый. Такой токен больше не принимается
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+	// Данные сеанса: устройство и адрес последнего входа или обновления
+	UserAgent        string    `json:"user_agent"`
+	IPAddress        string    `json:"ip_address"`
+	SessionCreatedAt time.Time `json:"session_created_at"`
+	LastUsedAt       time.Time `json:"last_used_at"`
+}
+
+// RevokedToken - отозванный access-токен. Если JTI задан, отозван один токен, если задан FamilyID - все токены сеанса,
+// иначе отозваны все токены пользователя, выданные не позже RevokedAt
+type RevokedToken struct {
+	ID        int       `json:"id"`
+	UserID    int       `json:"user_id"`
+	JTI       string    `json:"jti"`
+	FamilyID  string    `json:"family_id"`
+	RevokedAt time.Time `json:"revoked_at"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type UserStats struct {
-	TotalExpenses   float64 `json:"total_expenses"`
-	TotalCategories int     `json:"total_categories"`
-	TotalBudgets    int     `json:"total_budgets"`
-	MonthlyExpenses float64 `json:"monthly_expenses"`
-	WeeklyExpenses  float64 `json:"weekly_expenses"`
+	TotalExpenses       float64      `json:"total_expenses"`
+	TotalCategories     int          `json:"total_categories"`
+	TotalBudgets        int          `json:"total_budgets"`
+	MonthlyExpenses     money.Amount `json:"monthly_expenses"`
+	WeeklyExpenses      money.Amount `json:"weekly_expenses"`
+	TotalExpensesAmount money.Amount `json:"total_expenses_amount"`
+	TotalIncome         money.Amount `json:"total_income"`
+	// Currency - базовая валюта пользователя, в которой посчитаны суммы
+	Currency string `json:"currency"`
 	// TopCategories   []Category `json:"categories"`
 }
+
+type RecurringExpense struct {
+	ID           uint         `json:"id"`
+	UserID       uint         `json:"user_id"`
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Amount       money.Amount `json:"amount"`
+	Description  string       `json:"description"`
+	Frequency    string       `json:"frequency"` // daily, weekly, monthly, yearly
+	DayOfMonth   *int         `json:"day_of_month,omitempty"`
+	StartDate    time.Time    `json:"start_date"`
+	EndDate      *time.Time   `json:"end_date,omitempty"`
+	NextRunAt    time.Time    `json:"next_run_at"`
+	IsActive     bool         `json:"is_active"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type Income struct {
+	ID          uint         `json:"id"`
+	UserID      uint         `json:"user_id"`
+	Source      string       `json:"source"`
+	Amount      money.Amount `json:"amount"`
+	Description string       `json:"description"`
+	Date        time.Time    `json:"date"`
+	CreatedAt   time.Time    `json:"created_at"`
+	// Currency - валюта, в которой внесен доход; Amount хранится в ней
+	Currency string `json:"currency"`
+	// BaseAmount - сумма дохода в базовой валюте пользователя по курсу на дату дохода
+	BaseAmount money.Amount `json:"base_amount"`
+}
+
+// CashFlowBucket - доходы и расходы за один интервал (день, неделя, месяц...)
+type CashFlowBucket struct {
+	Period   time.Time    `json:"period"`
+	Income   money.Amount `json:"income"`
+	Expenses money.Amount `json:"expenses"`
+}
+
+// TagSummary - сумма расходов по одному тегу
+type TagSummary struct {
+	Tag             string       `json:"tag"`
+	TotalAmount     money.Amount `json:"total_amount"`
+	ExpensesCount   int          `json:"expenses_count"`
+	CategoriesCount int          `json:"categories_count"`
+}
+
+// ExpenseFilter - условия выборки расходов. Пустые поля не ограничивают выборку
+type ExpenseFilter struct {
+	UserID     uint
+	CategoryID int // 0 - все категории
+	From       *time.Time
+	To         *time.Time // не включается в выборку
+	MinAmount  *money.Amount
+	MaxAmount  *money.Amount
+	Search     string   // подстрока в описании без учета регистра
+	Tags       []string // хотя бы один из тегов
+	SortBy     string   // date, amount, created_at
+	Desc       bool
+	Limit      int
+	// Позиция, после которой продолжается выборка (keyset-пагинация): значение поля сортировки
+	// (time.Time для date и created_at, money.Amount для amount) и ID последнего расхода предыдущей страницы
+	AfterValue any
+	AfterID    uint
+}
+
+// ExpenseBucket - сумма и количество расходов за один интервал временного ряда
+type ExpenseBucket struct {
+	Period time.Time    `json:"period"`
+	Amount money.Amount `json:"amount"`
+	Count  int          `json:"count"`
+}
+
+// CategoryPeriodTotals - расходы по категории за текущий, предыдущий период и тот же период прошлого года
+type CategoryPeriodTotals struct {
+	CategoryID   uint         `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	Current      money.Amount `json:"current"`
+	Previous     money.Amount `json:"previous"`
+	LastYear     money.Amount `json:"last_year"`
+}
+
+// TimeRange - полуоткрытый интервал [From, To)
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// CategoryShare - расходы категории и ее доля в общих расходах за период.
+// Категории с долей меньше порога объединяются в одну строку с CategoryID = 0
+type CategoryShare struct {
+	CategoryID      uint         `json:"category_id"`
+	CategoryName    string       `json:"category_name"`
+	Amount          money.Amount `json:"amount"`
+	Percentage      float64      `json:"percentage"`
+	CategoriesCount int          `json:"categories_count"`
+}
+
+// BudgetAlert - уведомление о бюджете: достигнут порог расходования или период скоро закончится
+type BudgetAlert struct {
+	ID             uint       `json:"id"`
+	UserID         uint       `json:"user_id"`
+	BudgetID       uint       `json:"budget_id"`
+	CategoryID     uint       `json:"category_id"`
+	CategoryName   string     `json:"category_name"`
+	AlertType      string     `json:"alert_type"` // warning, exceeded, near_end
+	Threshold      int        `json:"threshold"`  // процент расходования, 0 для near_end
+	Percentage     float64    `json:"percentage"` // процент расходования в момент срабатывания
+	BudgetEndDate  *time.Time `json:"budget_end_date,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// BudgetPeriod - закрытый период бюджета с итоговой потраченной суммой
+type BudgetPeriod struct {
+	ID            uint         `json:"id"`
+	BudgetID      uint         `json:"budget_id"`
+	UserID        uint         `json:"user_id"`
+	Period        string       `json:"period"`
+	StartDate     time.Time    `json:"start_date"`
+	EndDate       time.Time    `json:"end_date"`
+	Amount        money.Amount `json:"amount"`
+	CarriedAmount money.Amount `json:"carried_amount"`
+	SpentAmount   money.Amount `json:"spent_amount"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// ExchangeRate - курс валюты: 1 единица BaseCurrency стоит Rate единиц QuoteCurrency на дату Date
+type ExchangeRate struct {
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          float64   `json:"rate"`
+	Date          time.Time `json:"date"`
+}
+
+// CategorizationRule - правило категоризации: операции, которые подходят под все заданные условия,
+// относятся к категории CategoryID и получают теги Tags. Из подходящих правил выбирается правило с большим Priority
+type CategorizationRule struct {
+	ID           uint   `json:"id"`
+	UserID       uint   `json:"user_id"`
+	CategoryID   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	// MatchType - как Pattern сравнивается с описанием: contains (подстрока) или regex. Пустой Pattern подходит к любому описанию
+	MatchType string `json:"match_type"`
+	Pattern   string `json:"pattern"`
+	// Границы суммы операции включительно, nil - граница не задана
+	MinAmount *money.Amount `json:"min_amount,omitempty"`
+	MaxAmount *money.Amount `json:"max_amount,omitempty"`
+	Tags      []string      `json:"tags"`
+	Priority  int           `json:"priority"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// ImportBatch - загрузка банковской выписки. Пока загрузка не подтверждена (Status = preview),
+// ее строки - только предпросмотр
+type ImportBatch struct {
+	ID            uint       `json:"id"`
+	UserID        uint       `json:"user_id"`
+	FileName      string     `json:"file_name"`
+	Format        string     `json:"format"` // csv, ofx
+	Status        string     `json:"status"` // preview, committed, undone
+	RowsCount     int        `json:"rows_count"`
+	SkippedCount  int        `json:"skipped_count"` // поступления и нулевые операции, которые не являются расходами
+	ImportedCount int        `json:"imported_count"`
+	CreatedAt     time.Time  `json:"created_at"`
+	CommittedAt   *time.Time `json:"committed_at,omitempty"`
+	UndoneAt      *time.Time `json:"undone_at,omitempty"`
+}
+
+// ImportRow - операция из выписки с предложенной категорией и найденным дубликатом среди расходов
+type ImportRow struct {
+	BatchID            uint         `json:"batch_id"`
+	RowNumber          int          `json:"row_number"`
+	Date               time.Time    `json:"date"`
+	Amount             money.Amount `json:"amount"`
+	Currency           string       `json:"currency"` // пусто - базовая валюта пользователя
+	Description        string       `json:"description"`
+	CategoryID         *uint        `json:"category_id,omitempty"`
+	CategoryName       string       `json:"category_name"`
+	RuleID             *uint        `json:"rule_id,omitempty"`
+	DuplicateExpenseID *uint        `json:"duplicate_expense_id,omitempty"`
+	ExpenseID          *uint        `json:"expense_id,omitempty"`
+}
@@ -9,4 +9,6 @@ type User struct {
 	Email              string    `json:"email" binding:"required"`
 	Password           string    `json:"password" binding:"required"`
 	TimeOfRegistration time.Time `json:"time_of_registration"`
+	// BaseCurrency - валюта, в которую пересчитываются расходы для аналитики и статистики
+	BaseCurrency string `json:"base_currency"`
 }
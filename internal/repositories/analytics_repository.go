@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"time"
+)
+
+type AnalyticsRepository struct {
+	storage storage.AnalyticsStorageInterface
+}
+
+func NewAnalyticsRepository(storage storage.AnalyticsStorageInterface) *AnalyticsRepository { //конструктор
+	return &AnalyticsRepository{
+		storage: storage,
+	}
+}
+
+// GetExpenseTimeSeries возвращает сумму и количество расходов по интервалам granularity (day, week, month)
+// в диапазоне [from, to). Интервалы без расходов попадают в результат с нулями. categoryID = 0 - все категории
+func (a *AnalyticsRepository) GetExpenseTimeSeries(ctx context.Context, userID uint, categoryID int, from, to time.Time, granularity string) ([]models.ExpenseBucket, error) {
+	query := `
+		WITH buckets AS (
+			SELECT generate_series(
+				date_trunc($2::text, $3::timestamptz),
+				$4::timestamptz - interval '1 microsecond',
+				('1 ' || $2::text)::interval
+			) AS bucket
+		),
+		totals AS (
+			SELECT date_trunc($2::text, e.date) AS bucket, SUM(e.base_amount) AS amount, COUNT(*) AS count
+			FROM expenses e
+			WHERE e.user_id = $1 AND ($5 = 0 OR e.category_id = $5)
+			  AND e.date >= $3 AND e.date < $4
+			GROUP BY 1
+		)
+		SELECT b.bucket, COALESCE(t.amount, 0), COALESCE(t.count, 0)
+		FROM buckets b
+		LEFT JOIN totals t ON t.bucket = b.bucket
+		ORDER BY b.bucket
+	`
+	result, err := a.storage.GetExpenseTimeSeries(ctx, query, userID, categoryID, from, to, granularity)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCategoryPeriodTotals возвращает расходы по каждой категории пользователя за три периода сразу.
+// categoryID = 0 - все категории; категории без расходов возвращаются с нулями
+func (a *AnalyticsRepository) GetCategoryPeriodTotals(ctx context.Context, userID uint, categoryID int, current, previous, lastYear models.TimeRange) ([]models.CategoryPeriodTotals, error) {
+	query := `
+		SELECT c.id, c.name,
+		       COALESCE(SUM(e.base_amount) FILTER (WHERE e.date >= $3 AND e.date < $4), 0) AS current,
+		       COALESCE(SUM(e.base_amount) FILTER (WHERE e.date >= $5 AND e.date < $6), 0) AS previous,
+		       COALESCE(SUM(e.base_amount) FILTER (WHERE e.date >= $7 AND e.date < $8), 0) AS last_year
+		FROM categories c
+		LEFT JOIN expenses e ON e.category_id = c.id AND e.user_id = $1
+		  AND ((e.date >= $3 AND e.date < $4) OR (e.date >= $5 AND e.date < $6) OR (e.date >= $7 AND e.date < $8))
+		WHERE c.user_id = $1 AND ($2 = 0 OR c.id = $2)
+		GROUP BY c.id, c.name
+		ORDER BY current DESC, c.id
+	`
+	result, err := a.storage.GetCategoryPeriodTotals(ctx, query, userID, categoryID, current, previous, lastYear)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCategoryShares возвращает сумму расходов по категориям за [from, to) и долю каждой в общей сумме
+// одним запросом. Категории с долей меньше minShare процентов объединяются в строку "other" с id = 0,
+// она идет последней
+func (a *AnalyticsRepository) GetCategoryShares(ctx context.Context, userID uint, from, to time.Time, minShare float64) ([]models.CategoryShare, error) {
+	query := `
+		WITH totals AS (
+			SELECT c.id, c.name, SUM(e.base_amount) AS amount
+			FROM expenses e
+			JOIN categories c ON c.id = e.category_id
+			WHERE e.user_id = $1 AND e.date >= $2 AND e.date < $3
+			GROUP BY c.id, c.name
+		),
+		shares AS (
+			SELECT id, name, amount,
+			       amount * 100.0 / NULLIF(SUM(amount) OVER (), 0) AS percentage
+			FROM totals
+		),
+		buckets AS (
+			SELECT CASE WHEN percentage >= $4 THEN id ELSE 0 END AS category_id,
+			       CASE WHEN percentage >= $4 THEN name ELSE 'other' END AS category_name,
+			       SUM(amount) AS amount,
+			       COALESCE(SUM(percentage), 0) AS percentage,
+			       COUNT(*) AS categories_count
+			FROM shares
+			GROUP BY 1, 2
+		)
+		SELECT category_id, category_name, amount, percentage, categories_count
+		FROM buckets
+		ORDER BY category_id = 0, amount DESC, category_id
+	`
+	result, err := a.storage.GetCategoryShares(ctx, query, userID, from, to, minShare)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
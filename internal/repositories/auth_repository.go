@@ -4,6 +4,7 @@ import (
 	"context"
 	"finance/internal/models"
 	storage "finance/internal/storages"
+	"time"
 )
 
 type AuthRepository struct {
@@ -31,18 +32,14 @@ func (r *AuthRepository) CreateUser(ctx context.Context, user *models.User) (*mo
 	}, nil
 }
 
-func (r *AuthRepository) CheckUserVerification(ctx context.Context, email string, hashpassword string) (models.User, error) {
-	query := `SELECT id, first_name, last_name, email FROM users WHERE email = $1 AND password = $2`
-	result, err := r.storage.CheckUserVerification(ctx, query, email, hashpassword)
-	if err != nil {
-		return models.User{}, err
-	}
-	return models.User{
-		ID:        result.ID,
-		Email:     result.Email,
-		FirstName: result.FirstName,
-		LastName:  result.LastName,
-	}, nil
+func (r *AuthRepository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	query := `SELECT id, email, first_name, last_name, password FROM users WHERE email = $1`
+	return r.storage.GetUserByEmail(ctx, query, email)
+}
+
+func (r *AuthRepository) UpdatePasswordHash(ctx context.Context, userID int, hashpassword string) error {
+	query := `UPDATE users SET password = $2 WHERE id = $1`
+	return r.storage.UpdatePasswordHash(ctx, query, userID, hashpassword)
 }
 
 func (r *AuthRepository) UserExistsByEmail(ctx context.Context, email string) (bool, error) {
@@ -55,14 +52,6 @@ func (r *AuthRepository) UserExistsByEmail(ctx context.Context, email string) (b
 
 }
 
-func (r *AuthRepository) GetUserIDbyRefreshToken(ctx context.Context, refresh_token string) (int, error) {
-	query := `SELECT user_id FROM refresh_tokens WHERE token = $1 AND expires_at > NOW()`
-	user_id, err := r.storage.GetUserIDbyRefreshToken(ctx, query, refresh_token)
-	if err != nil {
-		return 0, err
-	}
-	return user_id, nil
-}
 func (r *AuthRepository) RemoveOldRefreshToken(ctx context.Context, userID int) error {
 	query := `DELETE FROM refresh_tokens WHERE user_id = $1`
 	err := r.storage.RemoveOldRefreshToken(ctx, query, userID)
@@ -72,10 +61,80 @@ func (r *AuthRepository) RemoveOldRefreshToken(ctx context.Context, userID int)
 	return nil
 }
 func (r *AuthRepository) SaveNewRefreshToken(ctx context.Context, user_id int, token models.RefreshToken) error {
-	query := `INSERT INTO refresh_tokens (user_id, token, expires_at) VALUES ($1, $2, $3)`
+	query := `INSERT INTO refresh_tokens (user_id, token, family_id, expires_at, user_agent, ip_address, session_created_at, last_used_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
 	err := r.storage.SaveNewRefreshToken(ctx, query, user_id, token)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// GetRefreshToken блокирует строку токена до конца транзакции, чтобы один токен нельзя было обменять дважды
+func (r *AuthRepository) GetRefreshToken(ctx context.Context, refresh_token string) (models.RefreshToken, error) {
+	query := `SELECT id, user_id, token, family_id, expires_at, created_at, rotated_at, user_agent, ip_address, session_created_at, last_used_at
+		FROM refresh_tokens WHERE token = $1 FOR UPDATE`
+	return r.storage.GetRefreshToken(ctx, query, refresh_token)
+}
+
+// GetSessions возвращает действующие сеансы пользователя: у каждого семейства ровно один необмененный токен
+func (r *AuthRepository) GetSessions(ctx context.Context, userID int) ([]models.RefreshToken, error) {
+	query := `SELECT family_id, user_agent, ip_address, session_created_at, last_used_at, expires_at
+		FROM refresh_tokens
+		WHERE user_id = $1 AND rotated_at IS NULL AND expires_at > NOW()
+		ORDER BY last_used_at DESC, id DESC`
+	return r.storage.GetSessions(ctx, query, userID)
+}
+
+func (r *AuthRepository) MarkRefreshTokenRotated(ctx context.Context, tokenID int) error {
+	query := `UPDATE refresh_tokens SET rotated_at = NOW() WHERE id = $1`
+	return r.storage.MarkRefreshTokenRotated(ctx, query, tokenID)
+}
+
+func (r *AuthRepository) DeleteRefreshTokenFamily(ctx context.Context, userID int, familyID string) (int64, error) {
+	query := `DELETE FROM refresh_tokens WHERE user_id = $1 AND family_id = $2`
+	return r.storage.DeleteRefreshTokenFamily(ctx, query, userID, familyID)
+}
+
+// TouchSession обновляет время последнего использования сеанса у его действующего refresh-токена
+func (r *AuthRepository) TouchSession(ctx context.Context, userID int, familyID string) error {
+	query := `UPDATE refresh_tokens SET last_used_at = NOW()
+		WHERE user_id = $1 AND family_id = $2 AND rotated_at IS NULL`
+	return r.storage.TouchSession(ctx, query, userID, familyID)
+}
+
+func (r *AuthRepository) DeleteExpiredRefreshTokens(ctx context.Context) (int64, error) {
+	query := `DELETE FROM refresh_tokens WHERE expires_at <= NOW()`
+	return r.storage.DeleteExpiredRefreshTokens(ctx, query)
+}
+
+func (r *AuthRepository) DeleteRefreshToken(ctx context.Context, refresh_token string) error {
+	query := `DELETE FROM refresh_tokens WHERE token = $1`
+	return r.storage.DeleteRefreshToken(ctx, query, refresh_token)
+}
+
+func (r *AuthRepository) RevokeToken(ctx context.Context, token models.RevokedToken) (models.RevokedToken, error) {
+	query := `INSERT INTO revoked_tokens (user_id, jti, family_id, revoked_at, expires_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5)
+		ON CONFLICT (jti) DO UPDATE SET jti = EXCLUDED.jti
+		RETURNING id, user_id, COALESCE(jti, ''), COALESCE(family_id, ''), revoked_at, expires_at`
+	return r.storage.RevokeToken(ctx, query, token)
+}
+
+func (r *AuthRepository) GetRevokedTokens(ctx context.Context) ([]models.RevokedToken, error) {
+	query := `SELECT id, user_id, COALESCE(jti, ''), COALESCE(family_id, ''), revoked_at, expires_at FROM revoked_tokens WHERE expires_at > NOW()`
+	return r.storage.GetRevokedTokens(ctx, query)
+}
+
+// GetUserTokensRevokedAt возвращает время последнего действующего отзыва всех токенов пользователя
+// или нулевое время, если такого отзыва нет
+func (r *AuthRepository) GetUserTokensRevokedAt(ctx context.Context, userID int) (time.Time, error) {
+	query := `SELECT MAX(revoked_at) FROM revoked_tokens
+		WHERE user_id = $1 AND jti IS NULL AND family_id IS NULL AND expires_at > NOW()`
+	return r.storage.GetUserTokensRevokedAt(ctx, query, userID)
+}
+
+func (r *AuthRepository) DeleteExpiredRevokedTokens(ctx context.Context) (int64, error) {
+	query := `DELETE FROM revoked_tokens WHERE expires_at <= NOW()`
+	return r.storage.DeleteExpiredRevokedTokens(ctx, query)
+}
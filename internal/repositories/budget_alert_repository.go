@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"strconv"
+	"time"
+)
+
+// MaxBudgetAlerts - сколько последних уведомлений возвращается в списке
+const MaxBudgetAlerts = 200
+
+// BudgetExceededPercentage - процент расходования лимита, начиная с которого бюджет считается превышенным.
+// Общая граница для уведомлений exceeded и статуса бюджета
+const BudgetExceededPercentage = 100
+
+type BudgetAlertRepository struct {
+	storage storage.BudgetAlertStorageInterface
+}
+
+func NewBudgetAlertRepository(storage storage.BudgetAlertStorageInterface) *BudgetAlertRepository { //конструктор
+	return &BudgetAlertRepository{
+		storage: storage,
+	}
+}
+
+// CreateThresholdAlerts создает уведомления для всех порогов бюджетов, учитывающих категорию и активных на date,
+// которые уже достигнуты. Кроме бюджетов самой категории это общие бюджеты и группы, в которые она входит. Уникальный ключ (budget_id, alert_type, threshold, period_start) гарантирует,
+// что каждый порог срабатывает один раз за период бюджета
+func (a *BudgetAlertRepository) CreateThresholdAlerts(ctx context.Context, userID uint, categoryID int, date time.Time) (int64, error) {
+	query := `
+		INSERT INTO budget_alerts (user_id, budget_id, alert_type, threshold, period_start, percentage)
+		SELECT b.user_id, b.id,
+		       CASE WHEN t.threshold >= ` + strconv.Itoa(BudgetExceededPercentage) + ` THEN 'exceeded' ELSE 'warning' END,
+		       t.threshold,
+		       COALESCE(b.start_date, 'epoch'),
+		       ROUND(b.spent_amount * 100 / GREATEST(b.amount + b.carried_amount, 0.01), 2)
+		FROM budgets b
+		CROSS JOIN LATERAL unnest(b.alert_thresholds) AS t(threshold)
+		WHERE b.user_id = $1
+		  AND (b.category_id = $2 OR b.scope = 'overall'
+		       OR (b.scope = 'group' AND EXISTS (
+		           SELECT 1 FROM budget_categories bc WHERE bc.budget_id = b.id AND bc.category_id = $2)))
+		  AND (($3 >= b.start_date AND $3 < b.end_date) OR (b.start_date IS NULL AND b.end_date IS NULL))
+		  AND b.spent_amount * 100 >= (b.amount + b.carried_amount) * t.threshold
+		ON CONFLICT (budget_id, alert_type, threshold, period_start) DO NOTHING
+	`
+	result, err := a.storage.CreateThresholdAlerts(ctx, query, userID, categoryID, date)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// CreateNearEndAlerts создает уведомления near_end для бюджетов всех пользователей, период которых
+// заканчивается в интервале (now, until]
+func (a *BudgetAlertRepository) CreateNearEndAlerts(ctx context.Context, now, until time.Time) (int64, error) {
+	query := `
+		INSERT INTO budget_alerts (user_id, budget_id, alert_type, threshold, period_start, percentage)
+		SELECT b.user_id, b.id, 'near_end', 0,
+		       COALESCE(b.start_date, 'epoch'),
+		       ROUND(b.spent_amount * 100 / GREATEST(b.amount + b.carried_amount, 0.01), 2)
+		FROM budgets b
+		WHERE b.end_date > $1 AND b.end_date <= $2
+		ON CONFLICT (budget_id, alert_type, threshold, period_start) DO NOTHING
+	`
+	result, err := a.storage.CreateNearEndAlerts(ctx, query, now, until)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+func (a *BudgetAlertRepository) GetUserAlerts(ctx context.Context, userID uint, unacknowledgedOnly bool) ([]models.BudgetAlert, error) {
+	query := `
+		SELECT a.id, a.user_id, a.budget_id, COALESCE(b.category_id, 0), COALESCE(c.name, b.name) AS category_name,
+		       a.alert_type, a.threshold, a.percentage, b.end_date, a.acknowledged_at, a.created_at
+		FROM budget_alerts a
+		JOIN budgets b ON b.id = a.budget_id
+		LEFT JOIN categories c ON c.id = b.category_id
+		WHERE a.user_id = $1 AND (NOT $2 OR a.acknowledged_at IS NULL)
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT ` + strconv.Itoa(MaxBudgetAlerts)
+	result, err := a.storage.GetUserAlerts(ctx, query, userID, unacknowledgedOnly)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// AcknowledgeAlert отмечает уведомление прочитанным. Повторное подтверждение не меняет время первого
+func (a *BudgetAlertRepository) AcknowledgeAlert(ctx context.Context, userID uint, alertID int) error {
+	query := `UPDATE budget_alerts SET acknowledged_at = COALESCE(acknowledged_at, NOW()) WHERE id = $1 AND user_id = $2`
+	err := a.storage.AcknowledgeAlert(ctx, query, userID, alertID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"finance/internal/models"
 	storage "finance/internal/storages"
+	"finance/pkg/money"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -18,7 +21,10 @@ func NewBudgetRepository(storage storage.BudgetStorageInterface) *BudgetReposito
 }
 
 func (b *BudgetRepository) CreateBudget(ctx context.Context, budget models.Budget) (models.Budget, error) {
-	query := `INSERT INTO budgets (user_id, category_id, amount, spent_amount, period, start_date, end_date) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	query := `
+		INSERT INTO budgets (user_id, category_id, amount, spent_amount, period, start_date, end_date, alert_thresholds,
+		                     auto_renew, carry_over, carried_amount, week_start, month_start_day, year_start_month, scope, name, currency)
+		VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id`
 	result, err := b.storage.CreateBudget(ctx, query, budget)
 	if err != nil {
 		return models.Budget{}, err
@@ -26,12 +32,18 @@ func (b *BudgetRepository) CreateBudget(ctx context.Context, budget models.Budge
 	return result, nil
 }
 
+// GetUserBudgets возвращает бюджеты пользователя. category_id = 0 - все бюджеты, включая общие и группы категорий.
+// Для общих бюджетов и групп вместо названия категории возвращается название бюджета
 func (b *BudgetRepository) GetUserBudgets(ctx context.Context, category_id int, userID uint) ([]models.Budget, error) {
 	query := `
-		SELECT id, user_id, category_id, amount, spent_amount, period, start_date, end_date
-		FROM budgets
-		WHERE user_id = $1 AND ($2 = 0 OR category_id = $2)
-		ORDER BY start_date DESC
+		SELECT b.id, b.user_id, COALESCE(b.category_id, 0), COALESCE(c.name, b.name) AS category_name,
+		       b.amount, b.spent_amount, b.period, b.start_date, b.end_date, b.alert_thresholds,
+		       b.auto_renew, b.carry_over, b.carried_amount, b.week_start, b.month_start_day, b.year_start_month,
+		       b.scope, b.name, ARRAY(SELECT bc.category_id FROM budget_categories bc WHERE bc.budget_id = b.id ORDER BY bc.category_id), b.currency
+		FROM budgets b
+		LEFT JOIN categories c ON b.category_id = c.id
+		WHERE b.user_id = $1 AND ($2 = 0 OR b.category_id = $2)
+		ORDER BY b.start_date DESC
 	`
 	result, err := b.storage.GetUserBudgets(ctx, query, category_id, userID)
 	if err != nil {
@@ -42,8 +54,10 @@ func (b *BudgetRepository) GetUserBudgets(ctx context.Context, category_id int,
 
 func (b *BudgetRepository) GetBudgetByID(ctx context.Context, userID uint, category_id int, budget_id int) (models.Budget, error) {
 	query := `
-	SELECT id, user_id, category_id, amount, spent_amount, period, start_date, end_date
-	FROM budgets WHERE id = $1 AND user_id = $2 AND ($3 = 0 OR category_id = $3)`
+	SELECT b.id, b.user_id, COALESCE(b.category_id, 0), b.amount, b.spent_amount, b.period, b.start_date, b.end_date, b.alert_thresholds,
+	       b.auto_renew, b.carry_over, b.carried_amount, b.week_start, b.month_start_day, b.year_start_month,
+	       b.scope, b.name, ARRAY(SELECT bc.category_id FROM budget_categories bc WHERE bc.budget_id = b.id ORDER BY bc.category_id), b.currency
+	FROM budgets b WHERE b.id = $1 AND b.user_id = $2 AND ($3 = 0 OR b.category_id = $3)`
 	result, err := b.storage.GetBudgetByID(ctx, query, userID, category_id, budget_id)
 	if err != nil {
 		return models.Budget{}, err
@@ -51,8 +65,45 @@ func (b *BudgetRepository) GetBudgetByID(ctx context.Context, userID uint, categ
 	return result, nil
 }
 
+func (b *BudgetRepository) UpdateBudget(ctx context.Context, budget models.Budget) error {
+	query := `
+		UPDATE budgets
+		SET amount = $1, spent_amount = $2, period = $3, start_date = $4, end_date = $5, alert_thresholds = $9,
+		    auto_renew = $10, carry_over = $11, carried_amount = $12,
+		    week_start = $13, month_start_day = $14, year_start_month = $15,
+		    scope = $16, name = $17, currency = $18
+		WHERE id = $6 AND user_id = $7 AND ($8 = 0 OR category_id = $8)`
+	err := b.storage.UpdateBudget(ctx, query, budget)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetBudgetCategories заменяет набор категорий группового бюджета. Все категории должны принадлежать пользователю
+func (b *BudgetRepository) SetBudgetCategories(ctx context.Context, userID uint, budgetID uint, categoryIDs []uint) error {
+	deleteQuery := `DELETE FROM budget_categories WHERE budget_id = $1`
+	insertQuery := `
+		INSERT INTO budget_categories (budget_id, category_id)
+		SELECT $1, c.id FROM categories c
+		WHERE c.user_id = $2 AND c.id = ANY($3)
+	`
+	err := b.storage.SetBudgetCategories(ctx, deleteQuery, insertQuery, userID, budgetID, categoryIDs)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteBudgetsInCategory удаляет бюджеты категории и группы, в которых кроме нее категорий нет
 func (b *BudgetRepository) DeleteBudgetsInCategory(ctx context.Context, userID uint, categoryID int) error {
-	query := `DELETE FROM budgets WHERE user_id = $1 AND category_id = $2`
+	query := `
+		DELETE FROM budgets b
+		WHERE b.user_id = $1
+		  AND (b.category_id = $2
+		       OR (b.scope = 'group' AND NOT EXISTS (
+		           SELECT 1 FROM budget_categories bc WHERE bc.budget_id = b.id AND bc.category_id <> $2)))
+	`
 	err := b.storage.DeleteBudgetsInCategory(ctx, query, userID, categoryID)
 	if err != nil {
 		return err
@@ -70,7 +121,7 @@ func (b *BudgetRepository) DeleteBudget(ctx context.Context, userID uint, catego
 }
 
 // UpdateSpentAmount обновляет потраченную сумму для бюджета
-func (b *BudgetRepository) UpdateSpentAmount(ctx context.Context, category_id int, budgetID uint, spentAmount float64) error {
+func (b *BudgetRepository) UpdateSpentAmount(ctx context.Context, category_id int, budgetID uint, spentAmount money.Amount) error {
 	query := `UPDATE budgets SET spent_amount = $1 WHERE id = $2 AND ($3 = 0 OR category_id = $3)`
 	err := b.storage.UpdateSpentAmount(ctx, query, category_id, budgetID, spentAmount)
 	if err != nil {
@@ -79,13 +130,95 @@ func (b *BudgetRepository) UpdateSpentAmount(ctx context.Context, category_id in
 	return nil
 }
 
+// AdjustSpentAmount изменяет потраченную сумму всех бюджетов, учитывающих категорию и активных на дату расхода:
+// бюджетов самой категории, общих бюджетов и групп, в которые она входит.
+// delta задана в валюте currency, baseDelta - в базовой валюте пользователя. В валюту бюджета delta пересчитывается
+// по курсу на дату расхода, а если такого курса нет - через базовую валюту. Если пересчитать сумму хотя бы для
+// одного бюджета нельзя, ни один бюджет не меняется и возвращается ошибка
+func (b *BudgetRepository) AdjustSpentAmount(ctx context.Context, userID uint, categoryID int, date time.Time, currency string, delta, baseDelta money.Amount) error {
+	query := `
+		WITH targets AS (
+			SELECT b.id, b.currency, COALESCE(
+			           $1 * exchange_rate($5, b.currency, ($4::timestamp)::date),
+			           $6 * exchange_rate(u.base_currency, b.currency, ($4::timestamp)::date)) AS delta
+			FROM budgets b
+			JOIN users u ON u.id = b.user_id
+			WHERE b.user_id = $2
+			  AND (b.category_id = $3 OR b.scope = 'overall'
+			       OR (b.scope = 'group' AND EXISTS (
+			           SELECT 1 FROM budget_categories bc WHERE bc.budget_id = b.id AND bc.category_id = $3)))
+			  AND (($4 >= b.start_date AND $4 < b.end_date) OR (b.start_date IS NULL AND b.end_date IS NULL))
+		), adjusted AS (
+			UPDATE budgets b
+			SET spent_amount = GREATEST(b.spent_amount + t.delta, 0)
+			FROM targets t
+			WHERE b.id = t.id AND NOT EXISTS (SELECT 1 FROM targets WHERE delta IS NULL)
+		)
+		SELECT DISTINCT currency FROM targets WHERE delta IS NULL ORDER BY currency
+	`
+	missing, err := b.storage.AdjustSpentAmount(ctx, query, userID, categoryID, date, currency, delta, baseDelta)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("no exchange rate from %s to %s on %s", currency, strings.Join(missing, ", "), date.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// RecalculateSpentAmounts пересобирает spent_amount всех бюджетов пользователя из таблицы expenses
+// с пересчетом расходов в валюту бюджета. userID = 0 - бюджеты всех пользователей.
+// Бюджеты, для расходов которых нет курса к валюте бюджета, не меняются и возвращаются в skipped
+func (b *BudgetRepository) RecalculateSpentAmounts(ctx context.Context, userID uint) (updated int64, skipped int64, err error) {
+	query := `
+		WITH spent AS (
+			SELECT b.id,
+			       COALESCE(SUM(c.amount), 0) AS amount,
+			       COUNT(e.id) FILTER (WHERE c.amount IS NULL) AS missing
+			FROM budgets b
+			JOIN users u ON u.id = b.user_id
+			LEFT JOIN expenses e ON e.user_id = b.user_id
+			  AND (e.category_id = b.category_id OR b.scope = 'overall'
+			       OR (b.scope = 'group' AND e.category_id IN (
+			           SELECT bc.category_id FROM budget_categories bc WHERE bc.budget_id = b.id)))
+			  AND ((e.date >= b.start_date AND e.date < b.end_date) OR (b.start_date IS NULL AND b.end_date IS NULL))
+			LEFT JOIN LATERAL (
+				SELECT COALESCE(
+				           e.amount * exchange_rate(e.currency, b.currency, e.date::date),
+				           e.base_amount * exchange_rate(u.base_currency, b.currency, e.date::date)) AS amount
+			) c ON TRUE
+			WHERE ($1 = 0 OR b.user_id = $1)
+			GROUP BY b.id
+		), updated AS (
+			UPDATE budgets b
+			SET spent_amount = s.amount
+			FROM spent s
+			WHERE b.id = s.id AND s.missing = 0
+			RETURNING b.id
+		)
+		SELECT (SELECT COUNT(*) FROM updated), (SELECT COUNT(*) FROM spent WHERE missing > 0)
+	`
+	updated, skipped, err = b.storage.RecalculateSpentAmounts(ctx, query, userID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return updated, skipped, nil
+}
+
+// GetActiveBudgetsByCategoryAndDate возвращает бюджеты, учитывающие категорию и активные на date,
+// включая общие бюджеты и группы, в которые входит категория
 func (b *BudgetRepository) GetActiveBudgetsByCategoryAndDate(ctx context.Context, userID uint, categoryID int, date time.Time) ([]models.Budget, error) {
 	query := `
-		SELECT id, user_id, category_id, amount, spent_amount, period, start_date, end_date
-		FROM budgets
-		WHERE user_id = $1 AND category_id = $2 
-		  AND ($3 BETWEEN start_date AND end_date OR (start_date IS NULL AND end_date IS NULL))
-		ORDER BY start_date DESC
+		SELECT b.id, b.user_id, COALESCE(b.category_id, 0), b.amount, b.spent_amount, b.period, b.start_date, b.end_date, b.alert_thresholds,
+		       b.auto_renew, b.carry_over, b.carried_amount, b.week_start, b.month_start_day, b.year_start_month,
+		       b.scope, b.name, ARRAY(SELECT bc.category_id FROM budget_categories bc WHERE bc.budget_id = b.id ORDER BY bc.category_id), b.currency
+		FROM budgets b
+		WHERE b.user_id = $1
+		  AND (b.category_id = $2 OR b.scope = 'overall'
+		       OR (b.scope = 'group' AND EXISTS (
+		           SELECT 1 FROM budget_categories bc WHERE bc.budget_id = b.id AND bc.category_id = $2)))
+		  AND (($3 >= b.start_date AND $3 < b.end_date) OR (b.start_date IS NULL AND b.end_date IS NULL))
+		ORDER BY b.start_date DESC
 	`
 	result, err := b.storage.GetActiveBudgetsByCategoryAndDate(ctx, query, userID, categoryID, date)
 	if err != nil {
@@ -93,3 +226,109 @@ func (b *BudgetRepository) GetActiveBudgetsByCategoryAndDate(ctx context.Context
 	}
 	return result, nil
 }
+
+// GetBudgetSpentAmount считает в валюте currency сумму расходов всех категорий, которые учитывает бюджет, за интервал [start, end).
+// Если хотя бы один расход нельзя пересчитать в currency, возвращает ошибку
+func (b *BudgetRepository) GetBudgetSpentAmount(ctx context.Context, budgetID uint, start, end time.Time, currency string) (money.Amount, error) {
+	query := `
+		SELECT COALESCE(SUM(c.amount), 0), COUNT(*) FILTER (WHERE c.amount IS NULL)
+		FROM budgets b
+		JOIN expenses e ON e.user_id = b.user_id
+		JOIN users u ON u.id = b.user_id
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(
+			           e.amount * exchange_rate(e.currency, $4, e.date::date),
+			           e.base_amount * exchange_rate(u.base_currency, $4, e.date::date)) AS amount
+		) c
+		WHERE b.id = $1 AND e.date >= $2 AND e.date < $3
+		  AND (e.category_id = b.category_id OR b.scope = 'overall'
+		       OR (b.scope = 'group' AND e.category_id IN (
+		           SELECT bc.category_id FROM budget_categories bc WHERE bc.budget_id = b.id)))
+	`
+	result, missing, err := b.storage.GetBudgetSpentAmount(ctx, query, budgetID, start, end, currency)
+	if err != nil {
+		return 0, err
+	}
+	if missing > 0 {
+		return 0, fmt.Errorf("no exchange rate to %s for %d expenses", currency, missing)
+	}
+	return result, nil
+}
+
+// GetDueBudgetIDs возвращает автоматически продлеваемые бюджеты всех пользователей, период которых закончился
+func (b *BudgetRepository) GetDueBudgetIDs(ctx context.Context, now time.Time) ([]uint, error) {
+	query := `SELECT id FROM budgets WHERE auto_renew AND end_date <= $1 ORDER BY end_date`
+	result, err := b.storage.GetDueBudgetIDs(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LockBudget читает бюджет с блокировкой строки до конца транзакции,
+// чтобы параллельные запуски планировщика не продлили один период дважды
+func (b *BudgetRepository) LockBudget(ctx context.Context, id uint) (models.Budget, error) {
+	query := `
+		SELECT b.id, b.user_id, COALESCE(b.category_id, 0), b.amount, b.spent_amount, b.period, b.start_date, b.end_date, b.alert_thresholds,
+		       b.auto_renew, b.carry_over, b.carried_amount, b.week_start, b.month_start_day, b.year_start_month,
+		       b.scope, b.name, ARRAY(SELECT bc.category_id FROM budget_categories bc WHERE bc.budget_id = b.id ORDER BY bc.category_id), b.currency
+		FROM budgets b
+		WHERE b.id = $1
+		FOR UPDATE OF b
+	`
+	result, err := b.storage.LockBudget(ctx, query, id)
+	if err != nil {
+		return models.Budget{}, err
+	}
+	return result, nil
+}
+
+// HasOverlappingBudget проверяет, есть ли у пользователя другой бюджет той же категории (или другой общий бюджет)
+// и того же периода, пересекающийся по датам с budget. Группы категорий могут пересекаться
+func (b *BudgetRepository) HasOverlappingBudget(ctx context.Context, budget models.Budget) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM budgets
+			WHERE user_id = $1 AND scope = $7 AND scope <> 'group'
+			  AND category_id IS NOT DISTINCT FROM NULLIF($2, 0) AND period = $3
+			  AND start_date < $5 AND end_date > $4
+			  AND id <> $6
+		)
+	`
+	result, err := b.storage.HasOverlappingBudget(ctx, query, budget)
+	if err != nil {
+		return false, err
+	}
+	return result, nil
+}
+
+// ArchiveBudgetPeriod сохраняет закрытый период бюджета в историю. Повторное сохранение того же периода игнорируется
+func (b *BudgetRepository) ArchiveBudgetPeriod(ctx context.Context, budget models.Budget) error {
+	query := `
+		INSERT INTO budget_history (budget_id, user_id, period, start_date, end_date, amount, carried_amount, spent_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (budget_id, start_date) DO NOTHING
+	`
+	err := b.storage.ArchiveBudgetPeriod(ctx, query, budget)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetBudgetHistory возвращает закрытые периоды бюджета, начиная с последнего
+func (b *BudgetRepository) GetBudgetHistory(ctx context.Context, userID uint, categoryID int, budgetID int) ([]models.BudgetPeriod, error) {
+	query := `
+		SELECT h.id, h.budget_id, h.user_id, h.period, h.start_date, h.end_date,
+		       h.amount, h.carried_amount, h.spent_amount, h.created_at
+		FROM budget_history h
+		JOIN budgets b ON h.budget_id = b.id
+		WHERE h.budget_id = $1 AND h.user_id = $2 AND ($3 = 0 OR b.category_id = $3)
+		ORDER BY h.start_date DESC
+	`
+	result, err := b.storage.GetBudgetHistory(ctx, query, userID, categoryID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
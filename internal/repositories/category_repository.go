@@ -4,6 +4,8 @@ import (
 	"context"
 	"finance/internal/models"
 	storage "finance/internal/storages"
+	"finance/pkg/money"
+	"time"
 )
 
 type CategoryRepository struct {
@@ -26,6 +28,19 @@ func (c *CategoryRepository) CreateCategory(ctx context.Context, category models
 
 }
 
+// GetOrCreateCategory возвращает категорию пользователя с названием name, создавая ее, если такой еще нет
+func (c *CategoryRepository) GetOrCreateCategory(ctx context.Context, userID uint, name string) (models.Category, error) {
+	query := `
+		INSERT INTO categories (user_id, name) VALUES ($1, $2)
+		ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id, name, created_at`
+	result, err := c.storage.CreateCategory(ctx, query, models.Category{UserID: userID, Name: name})
+	if err != nil {
+		return models.Category{}, err
+	}
+	return result, nil
+}
+
 func (c *CategoryRepository) GetCategoryByID(ctx context.Context, userId uint, category_id int) (models.Category, error) {
 	query := `
         SELECT 
@@ -33,7 +48,7 @@ func (c *CategoryRepository) GetCategoryByID(ctx context.Context, userId uint, c
             c.name, 
             c.created_at,
             COUNT(e.id) AS expense_count,
-            COALESCE(SUM(e.amount), 0) AS total_amount
+            COALESCE(SUM(e.base_amount), 0) AS total_amount
         FROM 
             categories c
         LEFT JOIN 
@@ -70,7 +85,7 @@ func (c *CategoryRepository) DeleteCategory(ctx context.Context, userID uint, ca
 
 func (c *CategoryRepository) GetMostUsedCategories(ctx context.Context, userID uint) ([]models.Category, error) {
 	query := `
-        SELECT c.id, c.user_id, c.name, c.created_at, COUNT(e.id) as expense_count, COALESCE(SUM(e.amount), 0) as total_amount
+        SELECT c.id, c.user_id, c.name, c.created_at, COUNT(e.id) as expense_count, COALESCE(SUM(e.base_amount), 0) as total_amount
         FROM categories c LEFT JOIN expenses e ON c.id = e.category_id AND e.user_id = $1
         WHERE c.user_id = $1 GROUP BY c.id ORDER BY expense_count DESC LIMIT 5`
 
@@ -81,39 +96,22 @@ func (c *CategoryRepository) GetMostUsedCategories(ctx context.Context, userID u
 	return result, nil
 }
 
-func (c *CategoryRepository) GetTotalAmountInCategory(ctx context.Context, userID uint, categoryID int, period string) (float64, error) {
-	query := `SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE user_id = $1 AND category_id = $2`
+func (c *CategoryRepository) GetTotalAmountInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (money.Amount, error) {
+	query := `SELECT COALESCE(SUM(base_amount), 0) FROM expenses WHERE user_id = $1 AND category_id = $2 AND date >= $3 AND date < $4`
 
-	switch period {
-	case "weekly":
-		query += " AND date >= date_trunc('week', CURRENT_DATE)"
-	case "monthly":
-		query += " AND date >= date_trunc('month', CURRENT_DATE)"
-	case "yearly":
-		query += " AND date >= date_trunc('year', CURRENT_DATE)"
-	}
-	result, err := c.storage.GetTotalAmountInCategory(ctx, query, userID, categoryID, period)
+	result, err := c.storage.GetTotalAmountInCategory(ctx, query, userID, categoryID, from, to)
 	if err != nil {
 		return 0, err
 	}
 	return result, nil
 }
 
-func (c *CategoryRepository) GetLargestExpenseInCategory(ctx context.Context, userID uint, categoryID int, period string) (models.Expense, error) {
-	query := `SELECT e.id, e.user_id, e.category_id, c.name AS category_name, e.amount, e.description, e.date, e.created_at FROM expenses e JOIN 
-	categories c ON e.category_id = c.id WHERE e.user_id = $1 AND e.category_id = $2 AND e.amount > 0`
-
-	switch period {
-	case "weekly":
-		query += " AND date >= date_trunc('week', CURRENT_DATE)"
-	case "monthly":
-		query += " AND date >= date_trunc('month', CURRENT_DATE)"
-	case "yearly":
-		query += " AND date >= date_trunc('year', CURRENT_DATE)"
-	}
+func (c *CategoryRepository) GetLargestExpenseInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (models.Expense, error) {
+	query := `SELECT e.id, e.user_id, e.category_id, c.name AS category_name, e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at FROM expenses e JOIN 
+	categories c ON e.category_id = c.id WHERE e.user_id = $1 AND e.category_id = $2 AND e.amount > 0
+	AND e.date >= $3 AND e.date < $4 ORDER BY e.base_amount DESC LIMIT 1`
 
-	query += " ORDER BY amount DESC LIMIT 1"
-	result, err := c.storage.GetLargestExpenseInCategory(ctx, query, userID, categoryID, period)
+	result, err := c.storage.GetLargestExpenseInCategory(ctx, query, userID, categoryID, from, to)
 	if err != nil {
 		return models.Expense{}, err
 	}
@@ -121,42 +119,25 @@ func (c *CategoryRepository) GetLargestExpenseInCategory(ctx context.Context, us
 
 }
 
-func (c *CategoryRepository) GetSmallestExpenseInCategory(ctx context.Context, userID uint, categoryID int, period string) (models.Expense, error) {
-	query := `SELECT e.id, e.user_id, e.category_id, c.name AS category_name, e.amount, e.description, e.date, e.created_at FROM expenses e JOIN 
-	categories c ON e.category_id = c.id WHERE e.user_id = $1 AND e.category_id = $2 AND e.amount > 0`
-
-	switch period {
-	case "weekly":
-		query += " AND date >= date_trunc('week', CURRENT_DATE)"
-	case "monthly":
-		query += " AND date >= date_trunc('month', CURRENT_DATE)"
-	case "yearly":
-		query += " AND date >= date_trunc('year', CURRENT_DATE)"
-	}
+func (c *CategoryRepository) GetSmallestExpenseInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (models.Expense, error) {
+	query := `SELECT e.id, e.user_id, e.category_id, c.name AS category_name, e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at FROM expenses e JOIN 
+	categories c ON e.category_id = c.id WHERE e.user_id = $1 AND e.category_id = $2 AND e.amount > 0
+	AND e.date >= $3 AND e.date < $4 ORDER BY e.base_amount ASC LIMIT 1`
 
-	query += " ORDER BY amount ASC LIMIT 1"
-	result, err := c.storage.GetSmallestExpenseInCategory(ctx, query, userID, categoryID, period)
+	result, err := c.storage.GetSmallestExpenseInCategory(ctx, query, userID, categoryID, from, to)
 	if err != nil {
 		return models.Expense{}, err
 	}
 	return result, nil // надо чтобы название категории еще возвращало
 }
 
-func (c *CategoryRepository) GetExpenseCountInCategory(ctx context.Context, userID uint, categoryID int, period string) (int, error) {
+func (c *CategoryRepository) GetExpenseCountInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (int, error) {
 	query := `
 		SELECT COUNT(*)
 		FROM expenses
-		WHERE user_id = $1 AND category_id = $2`
-
-	switch period {
-	case "weekly":
-		query += " AND date >= date_trunc('week', CURRENT_DATE)"
-	case "monthly":
-		query += " AND date >= date_trunc('month', CURRENT_DATE)"
-	case "yearly":
-		query += " AND date >= date_trunc('year', CURRENT_DATE)"
-	}
-	result, err := c.storage.GetExpenseCountInCategory(ctx, query, userID, categoryID, period)
+		WHERE user_id = $1 AND category_id = $2 AND date >= $3 AND date < $4`
+
+	result, err := c.storage.GetExpenseCountInCategory(ctx, query, userID, categoryID, from, to)
 	if err != nil {
 		return 0, err
 	}
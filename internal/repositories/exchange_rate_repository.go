@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"fmt"
+	"time"
+)
+
+type ExchangeRateRepository struct {
+	storage storage.ExchangeRateStorageInterface
+}
+
+func NewExchangeRateRepository(storage storage.ExchangeRateStorageInterface) *ExchangeRateRepository { //конструктор
+	return &ExchangeRateRepository{
+		storage: storage,
+	}
+}
+
+// UpsertRates сохраняет курсы, курс за ту же дату перезаписывается
+func (r *ExchangeRateRepository) UpsertRates(ctx context.Context, rates []models.ExchangeRate) (int64, error) {
+	query := `
+		INSERT INTO exchange_rates (base_currency, quote_currency, rate, date)
+		VALUES ($1, $2, $3, $4::date)
+		ON CONFLICT (base_currency, quote_currency, date) DO UPDATE SET rate = EXCLUDED.rate
+	`
+	result, err := r.storage.UpsertRates(ctx, query, rates)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// GetRateToBase возвращает базовую валюту пользователя и курс currency к ней на дату date.
+// Пустая currency означает базовую валюту, курс тогда равен 1
+func (r *ExchangeRateRepository) GetRateToBase(ctx context.Context, userID uint, currency string, date time.Time) (string, float64, error) {
+	query := `
+		SELECT u.base_currency, exchange_rate(COALESCE(NULLIF($2, ''), u.base_currency), u.base_currency, ($3::timestamp)::date)
+		FROM users u
+		WHERE u.id = $1
+	`
+	base, rate, err := r.storage.GetRateToBase(ctx, query, userID, currency, date)
+	if err != nil {
+		return "", 0, err
+	}
+	if rate == nil {
+		return "", 0, fmt.Errorf("no exchange rate from %s to %s on %s", currency, base, date.Format("2006-01-02"))
+	}
+	return base, *rate, nil
+}
+
+// CountExpensesWithoutRate считает расходы пользователя, которые нельзя пересчитать в currency из-за отсутствия курса
+func (r *ExchangeRateRepository) CountExpensesWithoutRate(ctx context.Context, userID uint, currency string) (int, error) {
+	query := `SELECT COUNT(*) FROM expenses WHERE user_id = $1 AND exchange_rate(currency, $2, date::date) IS NULL`
+	result, err := r.storage.CountExpensesWithoutRate(ctx, query, userID, currency)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// RecalculateBaseAmounts пересчитывает base_amount расходов начиная с даты from по текущей базовой валюте их владельца.
+// userID = 0 - расходы всех пользователей. Расходы, для которых курса нет, не меняются
+func (r *ExchangeRateRepository) RecalculateBaseAmounts(ctx context.Context, userID uint, from time.Time) (int64, error) {
+	query := `
+		UPDATE expenses e
+		SET base_amount = ROUND(e.amount * exchange_rate(e.currency, u.base_currency, e.date::date), 2)
+		FROM users u
+		WHERE u.id = e.user_id AND ($1 = 0 OR e.user_id = $1) AND e.date >= $2
+		  AND exchange_rate(e.currency, u.base_currency, e.date::date) IS NOT NULL
+	`
+	result, err := r.storage.RecalculateBaseAmounts(ctx, query, userID, from)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// CountIncomesWithoutRate считает доходы пользователя, которые нельзя пересчитать в currency из-за отсутствия курса
+func (r *ExchangeRateRepository) CountIncomesWithoutRate(ctx context.Context, userID uint, currency string) (int, error) {
+	query := `SELECT COUNT(*) FROM incomes WHERE user_id = $1 AND exchange_rate(currency, $2, date::date) IS NULL`
+	result, err := r.storage.CountIncomesWithoutRate(ctx, query, userID, currency)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// RecalculateIncomeBaseAmounts пересчитывает base_amount доходов начиная с даты from по текущей базовой валюте их владельца.
+// userID = 0 - доходы всех пользователей. Доходы, для которых курса нет, не меняются
+func (r *ExchangeRateRepository) RecalculateIncomeBaseAmounts(ctx context.Context, userID uint, from time.Time) (int64, error) {
+	query := `
+		UPDATE incomes i
+		SET base_amount = ROUND(i.amount * exchange_rate(i.currency, u.base_currency, i.date::date), 2)
+		FROM users u
+		WHERE u.id = i.user_id AND ($1 = 0 OR i.user_id = $1) AND i.date >= $2
+		  AND exchange_rate(i.currency, u.base_currency, i.date::date) IS NOT NULL
+	`
+	result, err := r.storage.RecalculateIncomeBaseAmounts(ctx, query, userID, from)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
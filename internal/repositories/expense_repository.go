@@ -5,6 +5,7 @@ import (
 	"finance/internal/models"
 	storage "finance/internal/storages"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -19,7 +20,8 @@ func NewExpenseRepository(storage storage.ExpenseStorageInterface) *ExpenseRepos
 }
 
 func (e *ExpenseRepository) CreateExpense(ctx context.Context, expense models.Expense) (models.Expense, error) {
-	query := `INSERT INTO expenses (user_id, category_id, amount, description, date, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, category_id, amount, description, date, created_at`
+	query := `INSERT INTO expenses (user_id, category_id, amount, description, date, created_at, currency, base_amount) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id, user_id, category_id, (SELECT name FROM categories WHERE id = $2) AS category_name, amount, currency, base_amount, description, date, created_at`
 	result, err := e.storage.CreateExpense(ctx, query, expense)
 	if err != nil {
 		return models.Expense{}, err
@@ -28,68 +30,118 @@ func (e *ExpenseRepository) CreateExpense(ctx context.Context, expense models.Ex
 }
 
 func (e *ExpenseRepository) GetExpenseByID(ctx context.Context, userID uint, category_id int, id uint) (models.Expense, error) {
-	query := `SELECT e.id, e.user_id, e.category_id, c.name as category_name, e.amount, e.description, e.date, e.created_at FROM expenses e JOIN categories c ON e.category_id = c.id WHERE e.id = $1 AND e.user_id = $2 AND e.category_id = $3`
-	result, err := e.storage.GetExpenseByID(ctx, query, id, category_id, userID)
+	query := `SELECT e.id, e.user_id, e.category_id, c.name as category_name, e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at,
+	COALESCE((SELECT array_agg(t.name ORDER BY t.name) FROM expense_tags et JOIN tags t ON et.tag_id = t.id WHERE et.expense_id = e.id), '{}') AS tags
+	FROM expenses e JOIN categories c ON e.category_id = c.id WHERE e.id = $1 AND e.user_id = $2 AND e.category_id = $3`
+	result, err := e.storage.GetExpenseByID(ctx, query, userID, category_id, id)
 	if err != nil {
 		return models.Expense{}, err
 	}
 	return result, nil
 }
 
-func (e *ExpenseRepository) GetExpensesByUserID(ctx context.Context, category_id int, userID uint) ([]models.Expense, error) {
-	query := `SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-		       e.amount, e.description, e.date, e.created_at
+// expenseSortColumns - поля, по которым разрешена сортировка списка расходов
+var expenseSortColumns = map[string]string{
+	"date":       "e.date",
+	"amount":     "e.base_amount",
+	"created_at": "e.created_at",
+}
+
+// GetExpensesByUserID возвращает страницу расходов пользователя вместе с тегами по условиям filter.
+// Страницы строятся по ключу (поле сортировки, id), поэтому новые расходы не сдвигают уже выданные страницы
+func (e *ExpenseRepository) GetExpensesByUserID(ctx context.Context, filter models.ExpenseFilter) ([]models.Expense, error) {
+	sort_column, ok := expenseSortColumns[filter.SortBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort field: %s", filter.SortBy)
+	}
+	direction, operator := "ASC", ">"
+	if filter.Desc {
+		direction, operator = "DESC", "<"
+	}
+
+	args := []any{filter.UserID}
+	conditions := []string{"e.user_id = $1"}
+	addCondition := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.CategoryID != 0 {
+		addCondition("e.category_id = $%d", filter.CategoryID)
+	}
+	if filter.From != nil {
+		addCondition("e.date >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("e.date < $%d", *filter.To)
+	}
+	if filter.MinAmount != nil {
+		addCondition("e.base_amount >= $%d", *filter.MinAmount)
+	}
+	if filter.MaxAmount != nil {
+		addCondition("e.base_amount <= $%d", *filter.MaxAmount)
+	}
+	if filter.Search != "" {
+		// Символы шаблона LIKE в поисковой строке ищутся буквально
+		escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.Search)
+		addCondition("e.description ILIKE '%%' || $%d || '%%'", escaped)
+	}
+	if len(filter.Tags) > 0 {
+		addCondition(`EXISTS (
+			SELECT 1 FROM expense_tags et JOIN tags t ON et.tag_id = t.id
+			WHERE et.expense_id = e.id AND t.name = ANY($%d::text[]))`, filter.Tags)
+	}
+	if filter.AfterValue != nil {
+		args = append(args, filter.AfterValue, filter.AfterID)
+		conditions = append(conditions, fmt.Sprintf("(%s, e.id) %s ($%d, $%d)", sort_column, operator, len(args)-1, len(args)))
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
+		       e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at,
+		       COALESCE((SELECT array_agg(t.name ORDER BY t.name) FROM expense_tags et JOIN tags t ON et.tag_id = t.id WHERE et.expense_id = e.id), '{}') AS tags
+		FROM expenses e
+		JOIN categories c ON e.category_id = c.id
+		WHERE %s
+		ORDER BY %s %s, e.id %s
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), sort_column, direction, direction, len(args))
+	result, err := e.storage.GetExpensesByFilter(ctx, query, args...)
+	if err != nil {
+		return []models.Expense{}, err
+	}
+	return result, nil
+}
+
+func (e *ExpenseRepository) GetExpensesByPeriod(ctx context.Context, userID uint, category_id int, from, to time.Time) ([]models.Expense, error) {
+	query := `
+		SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
+		       e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at
 		FROM expenses e
 		JOIN categories c ON e.category_id = c.id
 		WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
+		  AND e.date >= $3 AND e.date < $4
 		ORDER BY e.date DESC
 	`
-	result, err := e.storage.GetExpensesByUserID(ctx, query, category_id, userID)
+	result, err := e.storage.GetExpensesByPeriod(ctx, query, userID, category_id, from, to)
 	if err != nil {
-		return []models.Expense{}, err
+		return nil, err
 	}
 	return result, nil
 }
 
-func (e *ExpenseRepository) GetExpensesByPeriod(ctx context.Context, userID uint, category_id int, period string) ([]models.Expense, error) {
-	var query string
-	switch period {
-	case "weekly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 week'
-			ORDER BY e.date DESC
-		`
-	case "monthly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 month'
-			ORDER BY e.date DESC
-		`
-	case "yearly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 year'
-			ORDER BY e.date DESC
-		`
-	default:
-		return nil, fmt.Errorf("invalid period: %s", period)
-	}
-	result, err := e.storage.GetExpensesByUserID(ctx, query, category_id, userID)
+// UpdateExpense обновляет расход, если новая категория принадлежит тому же пользователю
+func (e *ExpenseRepository) UpdateExpense(ctx context.Context, expense models.Expense) (models.Expense, error) {
+	query := `
+		UPDATE expenses
+		SET category_id = $1, amount = $2, description = $3, date = $4, currency = $7, base_amount = $8
+		WHERE id = $5 AND user_id = $6
+		  AND EXISTS (SELECT 1 FROM categories WHERE id = $1 AND user_id = $6)
+		RETURNING id, user_id, category_id, (SELECT name FROM categories WHERE id = $1) AS category_name,
+		          amount, currency, base_amount, description, date, created_at
+	`
+	result, err := e.storage.UpdateExpense(ctx, query, expense)
 	if err != nil {
-		return nil, err
+		return models.Expense{}, err
 	}
 	return result, nil
 }
@@ -116,7 +168,7 @@ func (e *ExpenseRepository) DeleteExpensesInCategory(ctx context.Context, userID
 func (e *ExpenseRepository) GetExpensesByCategory(ctx context.Context, userID uint, categoryID int) ([]models.Expense, error) {
 	query := `
 		SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-		       e.amount, e.description, e.date, e.created_at
+		       e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at
 		FROM expenses e
 		JOIN categories c ON e.category_id = c.id
 		WHERE e.user_id = $1 AND e.category_id = $2
@@ -130,47 +182,18 @@ func (e *ExpenseRepository) GetExpensesByCategory(ctx context.Context, userID ui
 
 }
 
-func (e *ExpenseRepository) GetLargestExpenseByPeriod(ctx context.Context, userID uint, category_id int, period string) (models.Expense, error) {
-	var query string
-
-	switch period {
-	case "weekly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 week'
-			ORDER BY e.amount DESC
-			LIMIT 1
-		`
-	case "monthly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 month'
-			ORDER BY e.amount DESC
-			LIMIT 1
-		`
-	case "yearly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 year'
-			ORDER BY e.amount DESC
-			LIMIT 1
-		`
-	default:
-		return models.Expense{}, fmt.Errorf("invalid period: %s", period)
-	}
-	result, err := e.storage.GetLargestExpenseByPeriod(ctx, query, userID, category_id, period)
+func (e *ExpenseRepository) GetLargestExpenseByPeriod(ctx context.Context, userID uint, category_id int, from, to time.Time) (models.Expense, error) {
+	query := `
+		SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
+		       e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at
+		FROM expenses e
+		JOIN categories c ON e.category_id = c.id
+		WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
+		  AND e.date >= $3 AND e.date < $4
+		ORDER BY e.base_amount DESC
+		LIMIT 1
+	`
+	result, err := e.storage.GetLargestExpenseByPeriod(ctx, query, userID, category_id, from, to)
 	if err != nil {
 		return models.Expense{}, err
 	}
@@ -178,47 +201,18 @@ func (e *ExpenseRepository) GetLargestExpenseByPeriod(ctx context.Context, userI
 
 }
 
-func (e *ExpenseRepository) GetSmallestExpenseByPeriod(ctx context.Context, userID uint, category_id int, period string) (models.Expense, error) {
-	var query string
-
-	switch period {
-	case "weekly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 week'
-			ORDER BY e.amount ASC
-			LIMIT 1
-		`
-	case "monthly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 month'
-			ORDER BY e.amount ASC
-			LIMIT 1
-		`
-	case "yearly":
-		query = `
-			SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-			       e.amount, e.description, e.date, e.created_at
-			FROM expenses e
-			JOIN categories c ON e.category_id = c.id
-			WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
-			  AND e.date >= NOW() - INTERVAL '1 year'
-			ORDER BY e.amount ASC
-			LIMIT 1
-		`
-	default:
-		return models.Expense{}, fmt.Errorf("invalid period: %s", period)
-	}
-	result, err := e.storage.GetSmallestExpenseByPeriod(ctx, query, userID, category_id, period)
+func (e *ExpenseRepository) GetSmallestExpenseByPeriod(ctx context.Context, userID uint, category_id int, from, to time.Time) (models.Expense, error) {
+	query := `
+		SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
+		       e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at
+		FROM expenses e
+		JOIN categories c ON e.category_id = c.id
+		WHERE e.user_id = $1 AND ($2 = 0 OR e.category_id = $2)
+		  AND e.date >= $3 AND e.date < $4
+		ORDER BY e.base_amount ASC
+		LIMIT 1
+	`
+	result, err := e.storage.GetSmallestExpenseByPeriod(ctx, query, userID, category_id, from, to)
 	if err != nil {
 		return models.Expense{}, err
 	}
@@ -228,11 +222,11 @@ func (e *ExpenseRepository) GetSmallestExpenseByPeriod(ctx context.Context, user
 func (e *ExpenseRepository) GetExpensesByCategoryAndPeriod(ctx context.Context, userID uint, categoryID int, startDate, endDate time.Time) ([]models.Expense, error) {
 	query := `
 		SELECT e.id, e.user_id, e.category_id, c.name as category_name, 
-		       e.amount, e.description, e.date, e.created_at
+		       e.amount, e.currency, e.base_amount, e.description, e.date, e.created_at
 		FROM expenses e
 		JOIN categories c ON e.category_id = c.id
 		WHERE e.user_id = $1 AND e.category_id = $2
-		  AND e.date >= $3 AND e.date <= $4
+		  AND e.date >= $3 AND e.date < $4
 		ORDER BY e.date DESC
 	`
 	result, err := e.storage.GetExpensesByCategoryAndPeriod(ctx, query, userID, categoryID, startDate, endDate)
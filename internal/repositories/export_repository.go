@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"time"
+)
+
+type ExportRepository struct {
+	storage storage.ExportStorageInterface
+}
+
+func NewExportRepository(storage storage.ExportStorageInterface) *ExportRepository { //конструктор
+	return &ExportRepository{
+		storage: storage,
+	}
+}
+
+func (e *ExportRepository) StreamCategories(ctx context.Context, userID uint, fn func(models.Category) error) error {
+	query := `SELECT id, name, created_at FROM categories WHERE user_id = $1 ORDER BY id`
+	return e.storage.StreamCategories(ctx, query, userID, fn)
+}
+
+// StreamExpenses передает расходы за [from, to) в порядке даты. nil - граница не задана
+func (e *ExportRepository) StreamExpenses(ctx context.Context, userID uint, from, to *time.Time, fn func(models.Expense) error) error {
+	query := `
+		SELECT e.id, e.category_id, c.name, e.amount, e.currency, e.base_amount, COALESCE(e.description, ''), e.date, e.created_at,
+		       COALESCE((SELECT array_agg(t.name ORDER BY t.name) FROM expense_tags et JOIN tags t ON et.tag_id = t.id WHERE et.expense_id = e.id), '{}')
+		FROM expenses e
+		JOIN categories c ON e.category_id = c.id
+		WHERE e.user_id = $1
+		  AND ($2::timestamptz IS NULL OR e.date >= $2)
+		  AND ($3::timestamptz IS NULL OR e.date < $3)
+		ORDER BY e.date, e.id
+	`
+	return e.storage.StreamExpenses(ctx, query, userID, from, to, fn)
+}
+
+// StreamBudgets передает бюджеты, период которых пересекается с [from, to)
+func (e *ExportRepository) StreamBudgets(ctx context.Context, userID uint, from, to *time.Time, fn func(models.Budget) error) error {
+	query := `
+		SELECT b.id, COALESCE(b.category_id, 0), COALESCE(c.name, ''), b.amount, b.spent_amount, b.carried_amount, b.currency,
+		       b.period, b.start_date, b.end_date, b.scope, b.name,
+		       ARRAY(SELECT bc.category_id FROM budget_categories bc WHERE bc.budget_id = b.id ORDER BY bc.category_id),
+		       b.auto_renew, b.carry_over, b.alert_thresholds
+		FROM budgets b
+		LEFT JOIN categories c ON b.category_id = c.id
+		WHERE b.user_id = $1
+		  AND ($2::timestamp IS NULL OR b.end_date > $2)
+		  AND ($3::timestamp IS NULL OR b.start_date < $3)
+		ORDER BY b.start_date, b.id
+	`
+	return e.storage.StreamBudgets(ctx, query, userID, from, to, fn)
+}
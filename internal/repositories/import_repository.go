@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"strconv"
+)
+
+// MaxImportBatches - сколько последних загрузок возвращается в списке
+const MaxImportBatches = 100
+
+// importBatchColumns - колонки import_batches в порядке, в котором их читает хранилище
+const importBatchColumns = `id, user_id, file_name, format, status, rows_count, skipped_count, imported_count, created_at, committed_at, undone_at`
+
+type ImportRepository struct {
+	storage storage.ImportStorageInterface
+}
+
+func NewImportRepository(storage storage.ImportStorageInterface) *ImportRepository { //конструктор
+	return &ImportRepository{
+		storage: storage,
+	}
+}
+
+func (r *ImportRepository) CreateBatch(ctx context.Context, batch models.ImportBatch) (models.ImportBatch, error) {
+	query := `INSERT INTO import_batches (user_id, file_name, format, rows_count, skipped_count) VALUES ($1, $2, $3, $4, $5)
+	RETURNING ` + importBatchColumns
+	result, err := r.storage.CreateBatch(ctx, query, batch)
+	if err != nil {
+		return models.ImportBatch{}, err
+	}
+	return result, nil
+}
+
+func (r *ImportRepository) AddRows(ctx context.Context, batchID uint, rows []models.ImportRow) (int64, error) {
+	query := `
+		INSERT INTO import_rows (batch_id, row_number, date, amount, currency, description, category_id, rule_id)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)
+	`
+	result, err := r.storage.AddRows(ctx, query, batchID, rows)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// MarkDuplicates отмечает строки загрузки, для которых у пользователя уже есть расход с той же датой (с точностью до дня),
+// суммой, валютой и описанием (без учета регистра и пробелов по краям)
+func (r *ImportRepository) MarkDuplicates(ctx context.Context, userID uint, batchID uint) (int64, error) {
+	query := `
+		UPDATE import_rows r SET duplicate_expense_id = d.expense_id
+		FROM (
+			SELECT DISTINCT ON (ir.row_number) ir.row_number, e.id AS expense_id
+			FROM import_rows ir
+			JOIN users u ON u.id = $1
+			JOIN expenses e ON e.user_id = $1
+				AND e.date::date = ir.date::date
+				AND e.amount = ir.amount
+				AND e.currency = COALESCE(ir.currency, u.base_currency)
+				AND lower(btrim(COALESCE(e.description, ''))) = lower(btrim(ir.description))
+			WHERE ir.batch_id = $2
+			ORDER BY ir.row_number, e.id
+		) d
+		WHERE r.batch_id = $2 AND r.row_number = d.row_number
+	`
+	result, err := r.storage.MarkDuplicates(ctx, query, userID, batchID)
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// GetBatch возвращает загрузку пользователя. FOR UPDATE не дает одновременно подтвердить и отменить одну загрузку
+func (r *ImportRepository) GetBatch(ctx context.Context, userID uint, batchID int) (models.ImportBatch, error) {
+	query := `SELECT ` + importBatchColumns + ` FROM import_batches WHERE id = $1 AND user_id = $2 FOR UPDATE`
+	result, err := r.storage.GetBatch(ctx, query, userID, batchID)
+	if err != nil {
+		return models.ImportBatch{}, err
+	}
+	return result, nil
+}
+
+func (r *ImportRepository) GetBatches(ctx context.Context, userID uint) ([]models.ImportBatch, error) {
+	query := `SELECT ` + importBatchColumns + ` FROM import_batches WHERE user_id = $1
+	ORDER BY created_at DESC, id DESC LIMIT ` + strconv.Itoa(MaxImportBatches)
+	result, err := r.storage.GetBatches(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *ImportRepository) GetRows(ctx context.Context, batchID uint) ([]models.ImportRow, error) {
+	query := `
+		SELECT r.batch_id, r.row_number, r.date, r.amount, COALESCE(r.currency, ''), r.description,
+		       r.category_id, COALESCE(c.name, '') AS category_name, r.rule_id, r.duplicate_expense_id, r.expense_id
+		FROM import_rows r
+		LEFT JOIN categories c ON c.id = r.category_id
+		WHERE r.batch_id = $1
+		ORDER BY r.row_number
+	`
+	result, err := r.storage.GetRows(ctx, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetRowExpense сохраняет расход, созданный из строки загрузки, и категорию, с которой он создан
+func (r *ImportRepository) SetRowExpense(ctx context.Context, batchID uint, rowNumber int, categoryID uint, expenseID uint) error {
+	query := `UPDATE import_rows SET category_id = $3, expense_id = $4 WHERE batch_id = $1 AND row_number = $2`
+	err := r.storage.SetRowExpense(ctx, query, batchID, rowNumber, categoryID, expenseID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetBatchExpenses возвращает ID и категории расходов, созданных загрузкой и еще не удаленных
+func (r *ImportRepository) GetBatchExpenses(ctx context.Context, batchID uint) ([]models.Expense, error) {
+	query := `
+		SELECT e.id, e.category_id
+		FROM import_rows r
+		JOIN expenses e ON e.id = r.expense_id
+		WHERE r.batch_id = $1
+		ORDER BY r.row_number
+	`
+	result, err := r.storage.GetBatchExpenses(ctx, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// MarkCommitted отмечает загрузку подтвержденной. Количество импортированных строк считается по строкам со ссылкой на расход
+func (r *ImportRepository) MarkCommitted(ctx context.Context, userID uint, batchID uint) error {
+	query := `UPDATE import_batches SET status = 'committed', committed_at = NOW(),
+	imported_count = (SELECT COUNT(*) FROM import_rows WHERE batch_id = $1 AND expense_id IS NOT NULL)
+	WHERE id = $1 AND user_id = $2 AND status = 'preview'`
+	err := r.storage.UpdateBatchStatus(ctx, query, userID, batchID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// MarkUndone отмечает подтвержденную загрузку отмененной. imported_count не меняется,
+// чтобы в истории было видно, сколько расходов она создавала
+func (r *ImportRepository) MarkUndone(ctx context.Context, userID uint, batchID uint) error {
+	query := `UPDATE import_batches SET status = 'undone', undone_at = NOW()
+	WHERE id = $1 AND user_id = $2 AND status = 'committed'`
+	err := r.storage.UpdateBatchStatus(ctx, query, userID, batchID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteBatch удаляет неподтвержденную загрузку вместе со строками предпросмотра
+func (r *ImportRepository) DeleteBatch(ctx context.Context, userID uint, batchID uint) error {
+	query := `DELETE FROM import_batches WHERE id = $1 AND user_id = $2 AND status = 'preview'`
+	err := r.storage.DeleteBatch(ctx, query, userID, batchID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"time"
+)
+
+type IncomeRepository struct {
+	storage storage.IncomeStorageInterface
+}
+
+func NewIncomeRepository(storage storage.IncomeStorageInterface) *IncomeRepository { //конструктор
+	return &IncomeRepository{
+		storage: storage,
+	}
+}
+
+func (i *IncomeRepository) CreateIncome(ctx context.Context, income models.Income) (models.Income, error) {
+	query := `INSERT INTO incomes (user_id, source, amount, description, date, currency, base_amount) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id, user_id, source, amount, description, date, created_at, currency, base_amount`
+	result, err := i.storage.CreateIncome(ctx, query, income)
+	if err != nil {
+		return models.Income{}, err
+	}
+	return result, nil
+}
+
+func (i *IncomeRepository) GetIncomeByID(ctx context.Context, userID uint, id int) (models.Income, error) {
+	query := `SELECT id, user_id, source, amount, description, date, created_at, currency, base_amount FROM incomes WHERE id = $1 AND user_id = $2`
+	result, err := i.storage.GetIncomeByID(ctx, query, userID, id)
+	if err != nil {
+		return models.Income{}, err
+	}
+	return result, nil
+}
+
+func (i *IncomeRepository) GetIncomes(ctx context.Context, userID uint) ([]models.Income, error) {
+	query := `SELECT id, user_id, source, amount, description, date, created_at, currency, base_amount FROM incomes WHERE user_id = $1 ORDER BY date DESC`
+	result, err := i.storage.GetIncomes(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (i *IncomeRepository) UpdateIncome(ctx context.Context, income models.Income) (models.Income, error) {
+	query := `
+		UPDATE incomes
+		SET source = $1, amount = $2, description = $3, date = $4, currency = $5, base_amount = $6
+		WHERE id = $7 AND user_id = $8
+		RETURNING id, user_id, source, amount, description, date, created_at, currency, base_amount
+	`
+	result, err := i.storage.UpdateIncome(ctx, query, income)
+	if err != nil {
+		return models.Income{}, err
+	}
+	return result, nil
+}
+
+func (i *IncomeRepository) DeleteIncome(ctx context.Context, userID uint, id int) error {
+	query := `DELETE FROM incomes WHERE id = $1 AND user_id = $2`
+	err := i.storage.DeleteIncome(ctx, query, userID, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetCashFlow возвращает суммы доходов и расходов по интервалам granularity в диапазоне [from, to).
+// Суммы считаются в базовой валюте пользователя. Интервалы без операций тоже попадают в результат с нулевыми суммами
+func (i *IncomeRepository) GetCashFlow(ctx context.Context, userID uint, from, to time.Time, granularity string) ([]models.CashFlowBucket, error) {
+	query := `
+		WITH buckets AS (
+			SELECT bucket, bucket + $5::interval AS bucket_end
+			FROM generate_series(
+				date_trunc($2::text, $3::timestamptz),
+				$4::timestamptz - interval '1 microsecond',
+				$5::interval
+			) AS bucket
+		)
+		SELECT b.bucket,
+		       COALESCE((SELECT SUM(i.base_amount) FROM incomes i
+		                 WHERE i.user_id = $1 AND i.date >= GREATEST(b.bucket, $3) AND i.date < LEAST(b.bucket_end, $4)), 0) AS income,
+		       COALESCE((SELECT SUM(e.base_amount) FROM expenses e
+		                 WHERE e.user_id = $1 AND e.date >= GREATEST(b.bucket, $3) AND e.date < LEAST(b.bucket_end, $4)), 0) AS expenses
+		FROM buckets b
+		ORDER BY b.bucket
+	`
+	// В PostgreSQL нет интервала "1 quarter", поэтому шаг задается отдельно от единицы date_trunc
+	step := "1 " + granularity
+	if granularity == "quarter" {
+		step = "3 months"
+	}
+	result, err := i.storage.GetCashFlow(ctx, query, userID, from, to, granularity, step)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
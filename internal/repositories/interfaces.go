@@ -3,16 +3,44 @@ package repositories
 import (
 	"context"
 	"finance/internal/models"
+	"finance/pkg/money"
 	"time"
 )
 
+// TransactorInterface выполняет операции нескольких репозиториев в одной транзакции
+type TransactorInterface interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinSnapshot(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// AnalyticsRepository handles aggregated expense analytics
+type AnalyticsRepositoryInterface interface {
+	GetExpenseTimeSeries(ctx context.Context, userID uint, categoryID int, from, to time.Time, granularity string) ([]models.ExpenseBucket, error)
+	GetCategoryPeriodTotals(ctx context.Context, userID uint, categoryID int, current, previous, lastYear models.TimeRange) ([]models.CategoryPeriodTotals, error)
+	GetCategoryShares(ctx context.Context, userID uint, from, to time.Time, minShare float64) ([]models.CategoryShare, error)
+}
+
 type AuthRepositoryInterface interface {
 	// Операции с пользователями
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
-	GetUserIDbyRefreshToken(ctx context.Context, refresh_token string) (int, error)
 	RemoveOldRefreshToken(ctx context.Context, userID int) error
 	SaveNewRefreshToken(ctx context.Context, user_id int, token models.RefreshToken) error
-	CheckUserVerification(ctx context.Context, email string, hash_password string) (models.User, error)
+	// GetRefreshToken возвращает токен с блокировкой строки; ID = 0, если токен не найден
+	GetRefreshToken(ctx context.Context, refresh_token string) (models.RefreshToken, error)
+	GetSessions(ctx context.Context, userID int) ([]models.RefreshToken, error)
+	MarkRefreshTokenRotated(ctx context.Context, tokenID int) error
+	DeleteRefreshToken(ctx context.Context, refresh_token string) error
+	DeleteRefreshTokenFamily(ctx context.Context, userID int, familyID string) (int64, error)
+	TouchSession(ctx context.Context, userID int, familyID string) error
+	DeleteExpiredRefreshTokens(ctx context.Context) (int64, error)
+	// GetUserByEmail возвращает пользователя вместе с хэшем пароля; ID = 0, если пользователь не найден
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	UpdatePasswordHash(ctx context.Context, userID int, hash_password string) error
+	// Отзыв access-токенов
+	RevokeToken(ctx context.Context, token models.RevokedToken) (models.RevokedToken, error)
+	GetRevokedTokens(ctx context.Context) ([]models.RevokedToken, error)
+	GetUserTokensRevokedAt(ctx context.Context, userID int) (time.Time, error)
+	DeleteExpiredRevokedTokens(ctx context.Context) (int64, error)
 	// Проверка существования
 	UserExistsByEmail(ctx context.Context, email string) (bool, error)
 }
@@ -22,21 +50,23 @@ type UserRepositoryInterface interface {
 	DeleteUser(ctx context.Context, userID uint) error
 	GetUserStats(ctx context.Context, userID uint) (models.UserStats, error)
 	GetProfile(ctx context.Context, userID uint) (models.User, error)
+	UpdateBaseCurrency(ctx context.Context, userID uint, currency string) error
 }
 
 // CategoryRepository handles category data persistence
 type CategoryRepositoryInterface interface {
 	// Basic CRUD operations
 	CreateCategory(ctx context.Context, category models.Category) (models.Category, error)
+	GetOrCreateCategory(ctx context.Context, userID uint, name string) (models.Category, error)
 	GetCategoryByID(ctx context.Context, userId uint, category_id int) (models.Category, error)
 	GetCategories(ctx context.Context, userID uint) ([]models.Category, error)
 	DeleteCategory(ctx context.Context, userID uint, category_id int) error
 	// Additional methods
 	GetMostUsedCategories(ctx context.Context, userID uint) ([]models.Category, error)
-	GetTotalAmountInCategory(ctx context.Context, userID uint, categoryID int, period string) (float64, error)
-	GetLargestExpenseInCategory(ctx context.Context, userID uint, categoryID int, period string) (models.Expense, error)
-	GetSmallestExpenseInCategory(ctx context.Context, userID uint, categoryID int, period string) (models.Expense, error)
-	GetExpenseCountInCategory(ctx context.Context, userID uint, categoryID int, period string) (int, error)
+	GetTotalAmountInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (money.Amount, error)
+	GetLargestExpenseInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (models.Expense, error)
+	GetSmallestExpenseInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (models.Expense, error)
+	GetExpenseCountInCategory(ctx context.Context, userID uint, categoryID int, from, to time.Time) (int, error)
 }
 
 // ExpenseRepository handles expense data persistence
@@ -44,16 +74,88 @@ type ExpenseRepositoryInterface interface {
 	// Basic CRUD operations
 	CreateExpense(ctx context.Context, expense models.Expense) (models.Expense, error)
 	GetExpenseByID(ctx context.Context, userID uint, category_id int, expense_id uint) (models.Expense, error)
-	GetExpensesByUserID(ctx context.Context, category_id int, userID uint) ([]models.Expense, error)
-	GetExpensesByPeriod(ctx context.Context, userID uint, category_id int, period string) ([]models.Expense, error)
+	GetExpensesByUserID(ctx context.Context, filter models.ExpenseFilter) ([]models.Expense, error)
+	GetExpensesByPeriod(ctx context.Context, userID uint, category_id int, from, to time.Time) ([]models.Expense, error)
+	UpdateExpense(ctx context.Context, expense models.Expense) (models.Expense, error)
 	DeleteExpense(ctx context.Context, userID uint, category_id int, id uint) error
 	DeleteExpensesInCategory(ctx context.Context, userID uint, categoryID int) error
 	// Analytics and reporting methods
 	GetExpensesByCategory(ctx context.Context, userID uint, categoryID int) ([]models.Expense, error)
 	GetExpensesByCategoryAndPeriod(ctx context.Context, userID uint, categoryID int, startDate, endDate time.Time) ([]models.Expense, error)
 	// Aggregation methods
-	GetLargestExpenseByPeriod(ctx context.Context, userID uint, category_id int, period string) (models.Expense, error)
-	GetSmallestExpenseByPeriod(ctx context.Context, userID uint, category_id int, period string) (models.Expense, error)
+	GetLargestExpenseByPeriod(ctx context.Context, userID uint, category_id int, from, to time.Time) (models.Expense, error)
+	GetSmallestExpenseByPeriod(ctx context.Context, userID uint, category_id int, from, to time.Time) (models.Expense, error)
+}
+
+// ExchangeRateRepository handles exchange rates and conversion to the base currency
+type ExchangeRateRepositoryInterface interface {
+	UpsertRates(ctx context.Context, rates []models.ExchangeRate) (int64, error)
+	GetRateToBase(ctx context.Context, userID uint, currency string, date time.Time) (string, float64, error)
+	CountExpensesWithoutRate(ctx context.Context, userID uint, currency string) (int, error)
+	RecalculateBaseAmounts(ctx context.Context, userID uint, from time.Time) (int64, error)
+	CountIncomesWithoutRate(ctx context.Context, userID uint, currency string) (int, error)
+	RecalculateIncomeBaseAmounts(ctx context.Context, userID uint, from time.Time) (int64, error)
+}
+
+// ExportRepository streams user data for export without loading it into memory
+type ExportRepositoryInterface interface {
+	StreamCategories(ctx context.Context, userID uint, fn func(models.Category) error) error
+	StreamExpenses(ctx context.Context, userID uint, from, to *time.Time, fn func(models.Expense) error) error
+	StreamBudgets(ctx context.Context, userID uint, from, to *time.Time, fn func(models.Budget) error) error
+}
+
+// ImportRepository handles bank statement import batches and their rows
+type ImportRepositoryInterface interface {
+	CreateBatch(ctx context.Context, batch models.ImportBatch) (models.ImportBatch, error)
+	AddRows(ctx context.Context, batchID uint, rows []models.ImportRow) (int64, error)
+	MarkDuplicates(ctx context.Context, userID uint, batchID uint) (int64, error)
+	GetBatch(ctx context.Context, userID uint, batchID int) (models.ImportBatch, error)
+	GetBatches(ctx context.Context, userID uint) ([]models.ImportBatch, error)
+	GetRows(ctx context.Context, batchID uint) ([]models.ImportRow, error)
+	SetRowExpense(ctx context.Context, batchID uint, rowNumber int, categoryID uint, expenseID uint) error
+	GetBatchExpenses(ctx context.Context, batchID uint) ([]models.Expense, error)
+	MarkCommitted(ctx context.Context, userID uint, batchID uint) error
+	MarkUndone(ctx context.Context, userID uint, batchID uint) error
+	DeleteBatch(ctx context.Context, userID uint, batchID uint) error
+}
+
+// IncomeRepository handles income data persistence
+type IncomeRepositoryInterface interface {
+	// Basic CRUD operations
+	CreateIncome(ctx context.Context, income models.Income) (models.Income, error)
+	GetIncomeByID(ctx context.Context, userID uint, id int) (models.Income, error)
+	GetIncomes(ctx context.Context, userID uint) ([]models.Income, error)
+	UpdateIncome(ctx context.Context, income models.Income) (models.Income, error)
+	DeleteIncome(ctx context.Context, userID uint, id int) error
+	// Analytics and reporting methods
+	GetCashFlow(ctx context.Context, userID uint, from, to time.Time, granularity string) ([]models.CashFlowBucket, error)
+}
+
+// RecurringExpenseRepository handles recurring expense data persistence
+type RecurringExpenseRepositoryInterface interface {
+	// Basic CRUD operations
+	CreateRecurringExpense(ctx context.Context, recurring models.RecurringExpense) (models.RecurringExpense, error)
+	GetRecurringExpenseByID(ctx context.Context, userID uint, category_id int, id int) (models.RecurringExpense, error)
+	GetRecurringExpenses(ctx context.Context, userID uint, category_id int) ([]models.RecurringExpense, error)
+	UpdateRecurringExpense(ctx context.Context, recurring models.RecurringExpense) error
+	DeleteRecurringExpense(ctx context.Context, userID uint, category_id int, id int) error
+	// Scheduler methods
+	GetDueRecurringExpenseIDs(ctx context.Context, now time.Time) ([]uint, error)
+	LockRecurringExpense(ctx context.Context, id uint) (models.RecurringExpense, error)
+	UpdateNextRun(ctx context.Context, id uint, nextRunAt time.Time, isActive bool) error
+}
+
+// RuleRepository handles user categorization rules persistence
+type RuleRepositoryInterface interface {
+	CreateRule(ctx context.Context, rule models.CategorizationRule) (models.CategorizationRule, error)
+	GetRules(ctx context.Context, userID uint) ([]models.CategorizationRule, error)
+	DeleteRule(ctx context.Context, userID uint, ruleID int) error
+}
+
+// TagRepository handles expense tags persistence
+type TagRepositoryInterface interface {
+	SetExpenseTags(ctx context.Context, userID uint, expenseID uint, tags []string) error
+	GetTagAnalytics(ctx context.Context, userID uint, from, to *time.Time) ([]models.TagSummary, error)
 }
 
 // BudgetRepository handles budget data persistence
@@ -61,8 +163,26 @@ type BudgetRepositoryInterface interface {
 	CreateBudget(ctx context.Context, budget models.Budget) (models.Budget, error)
 	GetBudgetByID(ctx context.Context, userID uint, category_id int, budget_id int) (models.Budget, error)
 	GetUserBudgets(ctx context.Context, category_id int, userID uint) ([]models.Budget, error)
+	UpdateBudget(ctx context.Context, budget models.Budget) error
 	DeleteBudget(ctx context.Context, userID uint, category_id int, budget_id int) error
 	DeleteBudgetsInCategory(ctx context.Context, userID uint, categoryID int) error
-	UpdateSpentAmount(ctx context.Context, category_id int, budgetID uint, spentAmount float64) error
+	UpdateSpentAmount(ctx context.Context, category_id int, budgetID uint, spentAmount money.Amount) error
+	AdjustSpentAmount(ctx context.Context, userID uint, categoryID int, date time.Time, currency string, delta, baseDelta money.Amount) error
+	RecalculateSpentAmounts(ctx context.Context, userID uint) (updated int64, skipped int64, err error)
 	GetActiveBudgetsByCategoryAndDate(ctx context.Context, userID uint, categoryID int, date time.Time) ([]models.Budget, error)
+	SetBudgetCategories(ctx context.Context, userID uint, budgetID uint, categoryIDs []uint) error
+	GetBudgetSpentAmount(ctx context.Context, budgetID uint, start, end time.Time, currency string) (money.Amount, error)
+	GetDueBudgetIDs(ctx context.Context, now time.Time) ([]uint, error)
+	LockBudget(ctx context.Context, id uint) (models.Budget, error)
+	HasOverlappingBudget(ctx context.Context, budget models.Budget) (bool, error)
+	ArchiveBudgetPeriod(ctx context.Context, budget models.Budget) error
+	GetBudgetHistory(ctx context.Context, userID uint, categoryID int, budgetID int) ([]models.BudgetPeriod, error)
+}
+
+// BudgetAlertRepository handles budget alerts persistence
+type BudgetAlertRepositoryInterface interface {
+	CreateThresholdAlerts(ctx context.Context, userID uint, categoryID int, date time.Time) (int64, error)
+	CreateNearEndAlerts(ctx context.Context, now, until time.Time) (int64, error)
+	GetUserAlerts(ctx context.Context, userID uint, unacknowledgedOnly bool) ([]models.BudgetAlert, error)
+	AcknowledgeAlert(ctx context.Context, userID uint, alertID int) error
 }
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"time"
+)
+
+type RecurringExpenseRepository struct {
+	storage storage.RecurringExpenseStorageInterface
+}
+
+func NewRecurringExpenseRepository(storage storage.RecurringExpenseStorageInterface) *RecurringExpenseRepository { //конструктор
+	return &RecurringExpenseRepository{
+		storage: storage,
+	}
+}
+
+// CreateRecurringExpense создает регулярный расход только в категории, принадлежащей пользователю
+func (r *RecurringExpenseRepository) CreateRecurringExpense(ctx context.Context, recurring models.RecurringExpense) (models.RecurringExpense, error) {
+	query := `
+		INSERT INTO recurring_expenses (user_id, category_id, amount, description, frequency, day_of_month, start_date, end_date, next_run_at, is_active)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		WHERE EXISTS (SELECT 1 FROM categories WHERE id = $2 AND user_id = $1)
+		RETURNING id, user_id, category_id, (SELECT name FROM categories WHERE id = $2) AS category_name,
+		          amount, description, frequency, day_of_month, start_date, end_date, next_run_at, is_active, created_at
+	`
+	result, err := r.storage.CreateRecurringExpense(ctx, query, recurring)
+	if err != nil {
+		return models.RecurringExpense{}, err
+	}
+	return result, nil
+}
+
+func (r *RecurringExpenseRepository) GetRecurringExpenseByID(ctx context.Context, userID uint, category_id int, id int) (models.RecurringExpense, error) {
+	query := `
+		SELECT r.id, r.user_id, r.category_id, c.name AS category_name, r.amount, r.description, r.frequency,
+		       r.day_of_month, r.start_date, r.end_date, r.next_run_at, r.is_active, r.created_at
+		FROM recurring_expenses r
+		JOIN categories c ON r.category_id = c.id
+		WHERE r.id = $1 AND r.user_id = $2 AND r.category_id = $3
+	`
+	result, err := r.storage.GetRecurringExpenseByID(ctx, query, userID, category_id, id)
+	if err != nil {
+		return models.RecurringExpense{}, err
+	}
+	return result, nil
+}
+
+func (r *RecurringExpenseRepository) GetRecurringExpenses(ctx context.Context, userID uint, category_id int) ([]models.RecurringExpense, error) {
+	query := `
+		SELECT r.id, r.user_id, r.category_id, c.name AS category_name, r.amount, r.description, r.frequency,
+		       r.day_of_month, r.start_date, r.end_date, r.next_run_at, r.is_active, r.created_at
+		FROM recurring_expenses r
+		JOIN categories c ON r.category_id = c.id
+		WHERE r.user_id = $1 AND ($2 = 0 OR r.category_id = $2)
+		ORDER BY r.next_run_at
+	`
+	result, err := r.storage.GetRecurringExpenses(ctx, query, userID, category_id)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *RecurringExpenseRepository) UpdateRecurringExpense(ctx context.Context, recurring models.RecurringExpense) error {
+	query := `
+		UPDATE recurring_expenses
+		SET amount = $1, description = $2, frequency = $3, day_of_month = $4, end_date = $5, next_run_at = $6, is_active = $7
+		WHERE id = $8 AND user_id = $9 AND category_id = $10
+	`
+	err := r.storage.UpdateRecurringExpense(ctx, query, recurring)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *RecurringExpenseRepository) DeleteRecurringExpense(ctx context.Context, userID uint, category_id int, id int) error {
+	query := `DELETE FROM recurring_expenses WHERE id = $1 AND user_id = $2 AND category_id = $3`
+	err := r.storage.DeleteRecurringExpense(ctx, query, userID, category_id, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDueRecurringExpenseIDs возвращает активные регулярные расходы всех пользователей, у которых наступил срок платежа
+func (r *RecurringExpenseRepository) GetDueRecurringExpenseIDs(ctx context.Context, now time.Time) ([]uint, error) {
+	query := `SELECT id FROM recurring_expenses WHERE is_active AND next_run_at <= $1 ORDER BY next_run_at`
+	result, err := r.storage.GetDueRecurringExpenseIDs(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// LockRecurringExpense читает регулярный расход с блокировкой строки до конца транзакции,
+// чтобы один и тот же платеж не был создан дважды параллельными запусками планировщика
+func (r *RecurringExpenseRepository) LockRecurringExpense(ctx context.Context, id uint) (models.RecurringExpense, error) {
+	query := `
+		SELECT r.id, r.user_id, r.category_id, c.name AS category_name, r.amount, r.description, r.frequency,
+		       r.day_of_month, r.start_date, r.end_date, r.next_run_at, r.is_active, r.created_at
+		FROM recurring_expenses r
+		JOIN categories c ON r.category_id = c.id
+		WHERE r.id = $1
+		FOR UPDATE OF r
+	`
+	result, err := r.storage.LockRecurringExpense(ctx, query, id)
+	if err != nil {
+		return models.RecurringExpense{}, err
+	}
+	return result, nil
+}
+
+func (r *RecurringExpenseRepository) UpdateNextRun(ctx context.Context, id uint, nextRunAt time.Time, isActive bool) error {
+	query := `UPDATE recurring_expenses SET next_run_at = $1, is_active = $2 WHERE id = $3`
+	err := r.storage.UpdateNextRun(ctx, query, id, nextRunAt, isActive)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -3,19 +3,39 @@ package repositories
 import storage "finance/internal/storages"
 
 type Repositories struct {
+	TransactorInterface
+	AnalyticsRepositoryInterface
 	AuthRepositoryInterface
 	BudgetRepositoryInterface
+	BudgetAlertRepositoryInterface
 	CategoryRepositoryInterface
 	ExpenseRepositoryInterface
+	ExchangeRateRepositoryInterface
+	ExportRepositoryInterface
+	ImportRepositoryInterface
+	IncomeRepositoryInterface
+	RecurringExpenseRepositoryInterface
+	RuleRepositoryInterface
+	TagRepositoryInterface
 	UserRepositoryInterface
 }
 
 func NewRepositories(storage *storage.Storages) *Repositories {
 	return &Repositories{
-		AuthRepositoryInterface:     NewAuthRepository(storage.AuthStorageInterface),
-		BudgetRepositoryInterface:   NewBudgetRepository(storage.BudgetStorageInterface),
-		CategoryRepositoryInterface: NewCategoryRepository(storage.CategoryStorageInterface),
-		ExpenseRepositoryInterface:  NewExpenseRepository(storage.ExpenseStorageInterface),
-		UserRepositoryInterface:     NewUserRepository(storage.UserStorageInterface),
+		TransactorInterface:                 storage.TransactorInterface,
+		AnalyticsRepositoryInterface:        NewAnalyticsRepository(storage.AnalyticsStorageInterface),
+		AuthRepositoryInterface:             NewAuthRepository(storage.AuthStorageInterface),
+		BudgetRepositoryInterface:           NewBudgetRepository(storage.BudgetStorageInterface),
+		BudgetAlertRepositoryInterface:      NewBudgetAlertRepository(storage.BudgetAlertStorageInterface),
+		CategoryRepositoryInterface:         NewCategoryRepository(storage.CategoryStorageInterface),
+		ExpenseRepositoryInterface:          NewExpenseRepository(storage.ExpenseStorageInterface),
+		ExchangeRateRepositoryInterface:     NewExchangeRateRepository(storage.ExchangeRateStorageInterface),
+		ExportRepositoryInterface:           NewExportRepository(storage.ExportStorageInterface),
+		ImportRepositoryInterface:           NewImportRepository(storage.ImportStorageInterface),
+		IncomeRepositoryInterface:           NewIncomeRepository(storage.IncomeStorageInterface),
+		RecurringExpenseRepositoryInterface: NewRecurringExpenseRepository(storage.RecurringExpenseStorageInterface),
+		RuleRepositoryInterface:             NewRuleRepository(storage.RuleStorageInterface),
+		TagRepositoryInterface:              NewTagRepository(storage.TagStorageInterface),
+		UserRepositoryInterface:             NewUserRepository(storage.UserStorageInterface),
 	}
 }
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+)
+
+type RuleRepository struct {
+	storage storage.RuleStorageInterface
+}
+
+func NewRuleRepository(storage storage.RuleStorageInterface) *RuleRepository { //конструктор
+	return &RuleRepository{
+		storage: storage,
+	}
+}
+
+func (r *RuleRepository) CreateRule(ctx context.Context, rule models.CategorizationRule) (models.CategorizationRule, error) {
+	query := `
+		INSERT INTO categorization_rules (user_id, category_id, match_type, pattern, min_amount, max_amount, tags, priority)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, user_id, category_id, (SELECT name FROM categories WHERE id = $2) AS category_name,
+		          match_type, pattern, min_amount, max_amount, tags, priority, created_at`
+	result, err := r.storage.CreateRule(ctx, query, rule)
+	if err != nil {
+		return models.CategorizationRule{}, err
+	}
+	return result, nil
+}
+
+// GetRules возвращает правила пользователя в порядке применения: сначала с большим приоритетом,
+// при равном приоритете - созданные раньше
+func (r *RuleRepository) GetRules(ctx context.Context, userID uint) ([]models.CategorizationRule, error) {
+	query := `
+		SELECT r.id, r.user_id, r.category_id, c.name AS category_name,
+		       r.match_type, r.pattern, r.min_amount, r.max_amount, r.tags, r.priority, r.created_at
+		FROM categorization_rules r
+		JOIN categories c ON c.id = r.category_id
+		WHERE r.user_id = $1
+		ORDER BY r.priority DESC, r.id
+	`
+	result, err := r.storage.GetRules(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *RuleRepository) DeleteRule(ctx context.Context, userID uint, ruleID int) error {
+	query := `DELETE FROM categorization_rules WHERE id = $1 AND user_id = $2`
+	err := r.storage.DeleteRule(ctx, query, userID, ruleID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"finance/internal/models"
+	storage "finance/internal/storages"
+	"time"
+)
+
+type TagRepository struct {
+	storage storage.TagStorageInterface
+}
+
+func NewTagRepository(storage storage.TagStorageInterface) *TagRepository { //конструктор
+	return &TagRepository{
+		storage: storage,
+	}
+}
+
+// SetExpenseTags заменяет набор тегов расхода на tags. Недостающие теги пользователя создаются,
+// связи с тегами, которых нет в tags, удаляются
+func (t *TagRepository) SetExpenseTags(ctx context.Context, userID uint, expenseID uint, tags []string) error {
+	query := `
+		WITH upserted AS (
+			INSERT INTO tags (user_id, name)
+			SELECT $2, unnest($3::text[])
+			ON CONFLICT (user_id, name) DO UPDATE SET name = EXCLUDED.name
+			RETURNING id
+		), removed AS (
+			DELETE FROM expense_tags
+			WHERE expense_id = $1 AND tag_id NOT IN (SELECT id FROM upserted)
+		)
+		INSERT INTO expense_tags (expense_id, tag_id)
+		SELECT $1, id FROM upserted
+		ON CONFLICT DO NOTHING
+	`
+	err := t.storage.SetExpenseTags(ctx, query, userID, expenseID, tags)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetTagAnalytics считает сумму и количество расходов по каждому тегу во всех категориях.
+// Пустые from и to не ограничивают период
+func (t *TagRepository) GetTagAnalytics(ctx context.Context, userID uint, from, to *time.Time) ([]models.TagSummary, error) {
+	query := `
+		SELECT t.name, SUM(e.base_amount) AS total_amount, COUNT(e.id) AS expenses_count,
+		       COUNT(DISTINCT e.category_id) AS categories_count
+		FROM tags t
+		JOIN expense_tags et ON et.tag_id = t.id
+		JOIN expenses e ON e.id = et.expense_id
+		WHERE t.user_id = $1
+		  AND ($2::timestamptz IS NULL OR e.date >= $2)
+		  AND ($3::timestamptz IS NULL OR e.date < $3)
+		GROUP BY t.name
+		ORDER BY total_amount DESC
+	`
+	result, err := t.storage.GetTagAnalytics(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -27,25 +27,20 @@ func (u *UserRepository) DeleteUser(ctx context.Context, userID uint) error {
 }
 
 func (u *UserRepository) GetUserStats(ctx context.Context, userID uint) (models.UserStats, error) {
-	query := `SELECT COUNT(DISTINCT e.id) as total_expenses_count, 
-	COUNT(DISTINCT c.id) as total_categories_count, 
-	COUNT(DISTINCT b.id) as total_budgets_count, 
-	COALESCE(SUM(CASE WHEN e.date >= (CURRENT_TIMESTAMP - INTERVAL '30 days') 
-	THEN e.amount 
-	ELSE 0 
-    END), 0) as monthly_expenses_sum,
-    COALESCE(SUM(CASE 
-        WHEN e.date >= (CURRENT_TIMESTAMP - INTERVAL '7 days')
-        THEN e.amount 
-        ELSE 0 
-    END), 0) as weekly_expenses_sum
-
+	// Каждая метрика считается отдельным подзапросом: JOIN расходов, бюджетов и доходов размножал бы строки и суммы
+	query := `SELECT
+		(SELECT COUNT(*) FROM expenses WHERE user_id = u.id) AS total_expenses_count,
+		(SELECT COUNT(*) FROM categories WHERE user_id = u.id) AS total_categories_count,
+		(SELECT COUNT(*) FROM budgets WHERE user_id = u.id) AS total_budgets_count,
+		(SELECT COALESCE(SUM(base_amount), 0) FROM expenses
+		 WHERE user_id = u.id AND date >= (CURRENT_TIMESTAMP - INTERVAL '30 days')) AS monthly_expenses_sum,
+		(SELECT COALESCE(SUM(base_amount), 0) FROM expenses
+		 WHERE user_id = u.id AND date >= (CURRENT_TIMESTAMP - INTERVAL '7 days')) AS weekly_expenses_sum,
+		(SELECT COALESCE(SUM(base_amount), 0) FROM expenses WHERE user_id = u.id) AS total_expenses_sum,
+		(SELECT COALESCE(SUM(base_amount), 0) FROM incomes WHERE user_id = u.id) AS total_income_sum,
+		u.base_currency
 	FROM users u
-	LEFT JOIN categories c ON u.id = c.user_id
-	LEFT JOIN expenses e ON u.id = e.user_id
-	LEFT JOIN budgets b ON u.id = b.user_id
-	WHERE u.id = $1
-	GROUP BY u.id;`
+	WHERE u.id = $1;`
 	result, err := u.storage.GetUserStats(ctx, query, userID)
 	if err != nil {
 		return models.UserStats{}, err
@@ -54,10 +49,19 @@ func (u *UserRepository) GetUserStats(ctx context.Context, userID uint) (models.
 }
 
 func (u *UserRepository) GetProfile(ctx context.Context, userID uint) (models.User, error) {
-	query := `SELECT id, first_name, last_name, email, time_of_registration FROM users WHERE id = $1`
+	query := `SELECT id, first_name, last_name, email, time_of_registration, base_currency FROM users WHERE id = $1`
 	result, err := u.storage.GetProfile(ctx, query, userID)
 	if err != nil {
 		return models.User{}, err
 	}
 	return result, nil
 }
+
+func (u *UserRepository) UpdateBaseCurrency(ctx context.Context, userID uint, currency string) error {
+	query := `UPDATE users SET base_currency = $2 WHERE id = $1`
+	err := u.storage.UpdateBaseCurrency(ctx, query, userID, currency)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -12,20 +12,59 @@ func SetupAuthRoutes(router *gin.RouterGroup, authHandler handler.AuthHandlerInt
 	{
 		auth.POST("/sign-up", authHandler.SignUp)
 		auth.POST("/sign-in", authHandler.SignIn)
+		auth.POST("/refresh", authHandler.Refresh)
 		auth.POST("/logout", authHandler.Logout)
 	}
 }
 
+// SetupSessionRoutes - маршруты аутентификации и управления сеансами, которым нужен авторизованный пользователь
+func SetupSessionRoutes(router *gin.RouterGroup, authHandler handler.AuthHandlerInterface) {
+	auth := router.Group("/auth")
+	{
+		auth.POST("/logout-all", authHandler.LogoutAll)
+	}
+	sessions := router.Group("/user/sessions")
+	{
+		sessions.GET("", authHandler.GetSessions)
+		sessions.DELETE("/:id", authHandler.DeleteSession)
+	}
+}
+
 func SetupExpenseRoutes(router *gin.RouterGroup, expenseHandler handler.ExpenseHandlerInterface) {
 	expenses := router.Group("/categories/:category_id/expenses")
 	{
 		expenses.POST("", expenseHandler.CreateExpense)
 		expenses.GET("", expenseHandler.GetExpenses)
 		expenses.GET("/:expense_id", expenseHandler.GetExpense)
+		expenses.PATCH("/:expense_id", expenseHandler.UpdateExpense)
 		expenses.DELETE("/:expense_id", expenseHandler.DeleteExpense)
 		expenses.GET("/analytics", expenseHandler.GetAnalytics)
 	}
+	router.GET("/expenses", expenseHandler.GetAllExpenses)
 }
+func SetupIncomeRoutes(router *gin.RouterGroup, incomeHandler handler.IncomeHandlerInterface) {
+	incomes := router.Group("/incomes")
+	{
+		incomes.POST("", incomeHandler.CreateIncome)
+		incomes.GET("", incomeHandler.GetIncomes)
+		incomes.GET("/:income_id", incomeHandler.GetIncome)
+		incomes.PATCH("/:income_id", incomeHandler.UpdateIncome)
+		incomes.DELETE("/:income_id", incomeHandler.DeleteIncome)
+	}
+	router.GET("/cashflow", incomeHandler.GetCashFlow)
+}
+
+func SetupRecurringExpenseRoutes(router *gin.RouterGroup, recurringExpenseHandler handler.RecurringExpenseHandlerInterface) {
+	recurring := router.Group("/categories/:category_id/recurring")
+	{
+		recurring.POST("", recurringExpenseHandler.CreateRecurringExpense)
+		recurring.GET("", recurringExpenseHandler.GetRecurringExpenses)
+		recurring.GET("/:recurring_id", recurringExpenseHandler.GetRecurringExpense)
+		recurring.PATCH("/:recurring_id", recurringExpenseHandler.UpdateRecurringExpense)
+		recurring.DELETE("/:recurring_id", recurringExpenseHandler.DeleteRecurringExpense)
+	}
+}
+
 func SetupCategoryRoutes(router *gin.RouterGroup, categoryHandler handler.CategoryHandlerInterface) {
 	categories := router.Group("/categories")
 	{
@@ -43,15 +82,85 @@ func SetupBudgetRoutes(router *gin.RouterGroup, budgetHandler handler.BudgetHand
 	{
 		budgets.POST("", budgetHandler.CreateBudget)
 		budgets.GET("", budgetHandler.GetBudgets)
+		budgets.PATCH("/:budget_id", budgetHandler.UpdateBudget)
 		budgets.DELETE("/:budget_id", budgetHandler.DeleteBudget)
+		budgets.GET("/:budget_id/history", budgetHandler.GetBudgetHistory)
+	}
+	// Общие бюджеты и бюджеты групп категорий, а также доступ к любому бюджету по ID
+	crossCategory := router.Group("/budgets")
+	{
+		crossCategory.POST("", budgetHandler.CreateCrossCategoryBudget)
+		crossCategory.GET("", budgetHandler.GetAllBudgets)
+		crossCategory.PATCH("/:budget_id", budgetHandler.UpdateBudgetByID)
+		crossCategory.DELETE("/:budget_id", budgetHandler.DeleteBudgetByID)
+		crossCategory.GET("/:budget_id/history", budgetHandler.GetBudgetHistoryByID)
+		crossCategory.GET("/status", budgetHandler.GetBudgetsStatus)
+	}
+}
+
+func SetupTagRoutes(router *gin.RouterGroup, tagHandler handler.TagHandlerInterface) {
+	tags := router.Group("/tags")
+	{
+		tags.GET("/analytics", tagHandler.GetTagAnalytics)
 	}
 }
 
+func SetupBudgetAlertRoutes(router *gin.RouterGroup, budgetAlertHandler handler.BudgetAlertHandlerInterface) {
+	alerts := router.Group("/alerts")
+	{
+		alerts.GET("", budgetAlertHandler.GetAlerts)
+		alerts.POST("/:alert_id/ack", budgetAlertHandler.AcknowledgeAlert)
+	}
+}
+
+func SetupAnalyticsRoutes(router *gin.RouterGroup, analyticsHandler handler.AnalyticsHandlerInterface) {
+	analytics := router.Group("/analytics")
+	{
+		analytics.GET("/timeseries", analyticsHandler.GetTimeSeries)
+		analytics.GET("/trends", analyticsHandler.GetTrends)
+		analytics.GET("/categories", analyticsHandler.GetCategoryShares)
+	}
+}
+
+func SetupExportRoutes(router *gin.RouterGroup, exportHandler handler.ExportHandlerInterface) {
+	router.GET("/export", exportHandler.Export)
+}
+
+func SetupImportRoutes(router *gin.RouterGroup, importHandler handler.ImportHandlerInterface) {
+	imports := router.Group("/imports")
+	{
+		imports.POST("", importHandler.CreateImport)
+		imports.GET("", importHandler.GetImports)
+		imports.GET("/:import_id", importHandler.GetImport)
+		imports.POST("/:import_id/commit", importHandler.CommitImport)
+		imports.DELETE("/:import_id", importHandler.UndoImport)
+	}
+}
+
+func SetupRuleRoutes(router *gin.RouterGroup, ruleHandler handler.RuleHandlerInterface) {
+	rules := router.Group("/rules")
+	{
+		rules.POST("", ruleHandler.CreateRule)
+		rules.GET("", ruleHandler.GetRules)
+		rules.DELETE("/:rule_id", ruleHandler.DeleteRule)
+		rules.POST("/test", ruleHandler.TestRule)
+		rules.POST("/apply", ruleHandler.ApplyRules)
+	}
+	// Расход без категории в маршруте: категорию выбирают правила
+	router.POST("/expenses", ruleHandler.CreateExpense)
+}
+
+func SetupAdminRoutes(router *gin.RouterGroup, exchangeRateHandler handler.ExchangeRateHandlerInterface, budgetHandler handler.BudgetHandlerInterface) {
+	router.POST("/exchange-rates", exchangeRateHandler.ImportExchangeRates)
+	router.POST("/budgets/recalculate", budgetHandler.RecalculateAllBudgets)
+}
+
 func SetupUserRoutes(router *gin.RouterGroup, userHandler handler.UserHandlerInterface) {
 	users := router.Group("/user")
 	{
 		users.GET("/profile", userHandler.GetProfile)
 		users.DELETE("/account", userHandler.DeleteAccount)
 		users.GET("/stats", userHandler.GetStats)
+		users.PUT("/base-currency", userHandler.UpdateBaseCurrency)
 	}
 }
@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"context"
+	"finance/pkg/logger"
+	"sync"
+	"time"
+)
+
+// Job - фоновая задача, которая выполняется с заданным интервалом
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Scheduler struct {
+	jobs []Job
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// AddJob регистрирует задачу. Задачи нужно добавлять до вызова Start
+func (s *Scheduler) AddJob(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.jobs = append(s.jobs, Job{
+		Name:     name,
+		Interval: interval,
+		Run:      run,
+	})
+}
+
+// Start запускает каждую задачу в отдельной горутине. Первый запуск происходит сразу,
+// чтобы обработать все, что накопилось, пока сервер был остановлен. Задачи работают до отмены ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+}
+
+// Wait блокирует до завершения всех задач после отмены контекста
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	log := logger.New("scheduler", true)
+	log.Info("Starting job", map[string]interface{}{
+		"job":      job.Name,
+		"interval": job.Interval.String(),
+	})
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.runOnce(ctx, job)
+		select {
+		case <-ctx.Done():
+			log.Info("Job stopped", map[string]interface{}{
+				"job": job.Name,
+			})
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	log := logger.New("scheduler", true)
+	// Один запуск не должен длиться дольше интервала, иначе запуски начнут накладываться
+	runCtx, cancel := context.WithTimeout(ctx, job.Interval)
+	defer cancel()
+
+	if err := job.Run(runCtx); err != nil {
+		log.Error("job failed", map[string]interface{}{
+			"job":   job.Name,
+			"error": err.Error(),
+		})
+	}
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"finance/pkg/money"
+	"finance/pkg/period"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// TimeSeriesDefaultGranularity - интервал временного ряда, если granularity не передан
+	TimeSeriesDefaultGranularity = "day"
+	// MaxTimeSeriesPoints - максимальное число интервалов в одном временном ряду
+	MaxTimeSeriesPoints = 1000
+	// DefaultTrendThreshold - изменение расходов в процентах, до которого тренд считается стабильным
+	DefaultTrendThreshold = 5.0
+	// DefaultCategoryMinShare - доля категории в процентах, меньше которой она попадает в "other"
+	DefaultCategoryMinShare = 3.0
+)
+
+type AnalyticsService struct {
+	repo repositories.AnalyticsRepositoryInterface
+}
+
+func NewAnalyticsService(repo repositories.AnalyticsRepositoryInterface) *AnalyticsService {
+	return &AnalyticsService{
+		repo: repo,
+	}
+}
+
+// GetExpenseTimeSeries возвращает расходы по дням, неделям или месяцам с нулями для пустых интервалов.
+// to включается в период целиком. По умолчанию - последние 30 дней, 12 недель или 12 месяцев
+func (a *AnalyticsService) GetExpenseTimeSeries(ctx context.Context, userID uint, req dto.TimeSeriesRequest) (dto.TimeSeriesResponse, error) {
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = TimeSeriesDefaultGranularity
+	}
+	if req.CategoryID < 0 {
+		return dto.TimeSeriesResponse{}, errors.New("invalid category id")
+	}
+
+	to := req.To
+	if to.IsZero() {
+		now := time.Now()
+		to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	from := req.From
+	var points int
+	switch granularity {
+	case "day":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -29)
+		}
+		points = int(to.Sub(from).Hours()/24) + 1
+	case "week":
+		if from.IsZero() {
+			from = to.AddDate(0, 0, -7*11)
+		}
+		points = int(to.Sub(from).Hours()/24)/7 + 2
+	case "month":
+		if from.IsZero() {
+			from = time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, to.Location())
+		}
+		points = (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	default:
+		return dto.TimeSeriesResponse{}, fmt.Errorf("unsupported granularity: %s. Available values: day, week, month", granularity)
+	}
+	// to задается датой и включается в период целиком
+	to_exclusive := to.AddDate(0, 0, 1)
+	if !from.Before(to_exclusive) {
+		return dto.TimeSeriesResponse{}, errors.New("from must not be after to")
+	}
+	if points > MaxTimeSeriesPoints {
+		return dto.TimeSeriesResponse{}, fmt.Errorf("too many points in time series: %d, max %d. Narrow the period or use a larger granularity", points, MaxTimeSeriesPoints)
+	}
+
+	buckets, err := a.repo.GetExpenseTimeSeries(ctx, userID, req.CategoryID, from, to_exclusive, granularity)
+	if err != nil {
+		return dto.TimeSeriesResponse{}, err
+	}
+
+	response := dto.TimeSeriesResponse{
+		Granularity: granularity,
+		From:        from,
+		To:          to,
+		CategoryID:  req.CategoryID,
+	}
+	if granularity == "month" {
+		response.Months = make([]dto.MonthExpense, 0, len(buckets))
+		for _, bucket := range buckets {
+			response.Months = append(response.Months, dto.MonthExpense{
+				Year:   bucket.Period.Year(),
+				Month:  int(bucket.Period.Month()),
+				Amount: bucket.Amount,
+				Count:  bucket.Count,
+			})
+		}
+		return response, nil
+	}
+	response.Points = make([]dto.DayExpense, 0, len(buckets))
+	for _, bucket := range buckets {
+		response.Points = append(response.Points, dto.DayExpense{
+			Date:   bucket.Period,
+			Amount: bucket.Amount,
+			Count:  bucket.Count,
+		})
+	}
+	return response, nil
+}
+
+// GetExpenseTrends сравнивает расходы за период с предыдущим периодом и с тем же периодом прошлого года,
+// в целом и по каждой категории. Изменение в пределах threshold процентов считается стабильным трендом
+func (a *AnalyticsService) GetExpenseTrends(ctx context.Context, userID uint, req dto.TrendsRequest) (dto.TrendsResponse, error) {
+	threshold := DefaultTrendThreshold
+	if req.Threshold != nil {
+		threshold = *req.Threshold
+	}
+	if threshold < 0 || threshold > 100 {
+		return dto.TrendsResponse{}, errors.New("threshold must be between 0 and 100")
+	}
+	if req.CategoryID < 0 {
+		return dto.TrendsResponse{}, errors.New("invalid category id")
+	}
+
+	now := time.Now()
+	current, err := period.Resolve(req.Period, req.From, req.To, now)
+	if err != nil {
+		return dto.TrendsResponse{}, err
+	}
+	previous := current.Previous()
+	last_year := current.YearAgo()
+	// Незаконченный период сравнивается с тем же числом первых дней предыдущих периодов,
+	// иначе половина текущего месяца всегда выглядит как падение расходов
+	if elapsed := current.ElapsedDays(now); elapsed > 0 && elapsed < current.Days() {
+		current = current.Truncate(elapsed)
+		previous = previous.Truncate(elapsed)
+		last_year = last_year.Truncate(elapsed)
+	}
+
+	totals, err := a.repo.GetCategoryPeriodTotals(ctx, userID, req.CategoryID,
+		models.TimeRange{From: current.Start, To: current.End},
+		models.TimeRange{From: previous.Start, To: previous.End},
+		models.TimeRange{From: last_year.Start, To: last_year.End},
+	)
+	if err != nil {
+		return dto.TrendsResponse{}, err
+	}
+	if req.CategoryID != 0 && len(totals) == 0 {
+		return dto.TrendsResponse{}, errors.New("category not found")
+	}
+
+	response := dto.TrendsResponse{
+		Current:    toComparedPeriod(current),
+		Previous:   toComparedPeriod(previous),
+		LastYear:   toComparedPeriod(last_year),
+		Threshold:  threshold,
+		Categories: make([]dto.CategoryTrend, 0, len(totals)),
+	}
+	var overall models.CategoryPeriodTotals
+	for _, total := range totals {
+		overall.Current += total.Current
+		overall.Previous += total.Previous
+		overall.LastYear += total.LastYear
+		response.Categories = append(response.Categories, dto.CategoryTrend{
+			CategoryID:    total.CategoryID,
+			CategoryName:  total.CategoryName,
+			ExpenseTrends: buildExpenseTrends(total, threshold),
+		})
+	}
+	response.Overall = buildExpenseTrends(overall, threshold)
+	return response, nil
+}
+
+// GetCategoryShares возвращает долю каждой категории в расходах за период.
+// Мелкие категории с долей меньше min_share объединяются в последнюю строку "other"
+func (a *AnalyticsService) GetCategoryShares(ctx context.Context, userID uint, req dto.CategorySharesRequest) (dto.CategorySharesResponse, error) {
+	min_share := DefaultCategoryMinShare
+	if req.MinShare != nil {
+		min_share = *req.MinShare
+	}
+	if min_share < 0 || min_share > 100 {
+		return dto.CategorySharesResponse{}, errors.New("min_share must be between 0 and 100")
+	}
+	date_range, err := period.Resolve(req.Period, req.From, req.To, time.Now())
+	if err != nil {
+		return dto.CategorySharesResponse{}, err
+	}
+
+	shares, err := a.repo.GetCategoryShares(ctx, userID, date_range.Start, date_range.End, min_share)
+	if err != nil {
+		return dto.CategorySharesResponse{}, err
+	}
+
+	response := dto.CategorySharesResponse{
+		Period:     date_range.Label,
+		From:       date_range.Start,
+		To:         date_range.End.AddDate(0, 0, -1),
+		MinShare:   min_share,
+		Categories: make([]dto.CategoryExpense, 0, len(shares)),
+	}
+	for _, share := range shares {
+		response.TotalAmount += share.Amount
+		if share.CategoryID == 0 {
+			response.OtherCategoriesCount = share.CategoriesCount
+		}
+		response.Categories = append(response.Categories, dto.CategoryExpense{
+			CategoryID:   share.CategoryID,
+			CategoryName: share.CategoryName,
+			Amount:       share.Amount,
+			Percentage:   roundAmount(share.Percentage),
+		})
+	}
+	return response, nil
+}
+
+func toComparedPeriod(r period.DateRange) dto.ComparedPeriod {
+	return dto.ComparedPeriod{
+		Period: r.Label,
+		From:   r.Start,
+		To:     r.End.AddDate(0, 0, -1),
+	}
+}
+
+func buildExpenseTrends(total models.CategoryPeriodTotals, threshold float64) dto.ExpenseTrends {
+	growth_rate, trend := classifyTrend(total.Current, total.Previous, threshold)
+	yoy_rate, yoy_trend := classifyTrend(total.Current, total.LastYear, threshold)
+	return dto.ExpenseTrends{
+		CurrentAmount:     total.Current,
+		PreviousAmount:    total.Previous,
+		GrowthRate:        growth_rate,
+		Trend:             trend,
+		ComparedToPrev:    total.Current.Sub(total.Previous),
+		LastYearAmount:    total.LastYear,
+		YearOverYearRate:  yoy_rate,
+		YearOverYearTrend: yoy_trend,
+	}
+}
+
+// classifyTrend возвращает изменение current относительно base в процентах и направление тренда.
+// Если base равен нулю, процент не определен и возвращается nil
+func classifyTrend(current, base money.Amount, threshold float64) (*float64, string) {
+	if base.IsZero() {
+		if current.IsPositive() {
+			return nil, "increasing"
+		}
+		return nil, "stable"
+	}
+	rate := roundAmount(current.Sub(base).Ratio(base) * 100)
+	switch {
+	case rate > threshold:
+		return &rate, "increasing"
+	case rate < -threshold:
+		return &rate, "decreasing"
+	default:
+		return &rate, "stable"
+	}
+}
+
+// roundAmount округляет проценты до сотых, чтобы не отдавать клиенту хвосты вроде 17.999999999
+func roundAmount(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -3,16 +3,18 @@ package services
 import (
 	"context"
 	"crypto/rand"
-	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"finance/internal/dto"
 	"finance/internal/models"
 	"finance/internal/repositories"
+	"finance/pkg/password"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -24,13 +26,51 @@ const (
 	RefreshTokenTTL = 30 * 24 * time.Hour
 )
 
+// ErrInvalidCredentials возвращается при неверном email или пароле. Причина не уточняется,
+// чтобы по ответу нельзя было узнать, зарегистрирован ли email
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// Ошибки обновления токенов
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	// ErrRefreshTokenReused - предъявлен уже обмененный токен. Все токены этого сеанса отозваны
+	ErrRefreshTokenReused = errors.New("refresh token has already been used, session revoked")
+	ErrSessionNotFound    = errors.New("session not found")
+)
+
+// MaxUserAgentLength - сколько символов User-Agent сохраняется в сеансе
+const MaxUserAgentLength = 512
+
+// SessionTouchInterval - как часто запросы с access-токеном обновляют время последнего использования сеанса
+const SessionTouchInterval = time.Minute
+
+// accessTokenClaims - содержимое access-токена. sid связывает токен с сеансом, чтобы его можно было отозвать
+// вместе с сеансом
+type accessTokenClaims struct {
+	jwt.RegisteredClaims
+	SessionID string `json:"sid,omitempty"`
+}
+
 type AuthService struct {
-	repo repositories.AuthRepositoryInterface
+	repo    repositories.AuthRepositoryInterface
+	hasher  *password.Hasher
+	revoked *revocationCache
+	tx      repositories.TransactorInterface
+
+	touchMu sync.Mutex
+	// touched - когда каждый сеанс последний раз отмечен в базе этим экземпляром
+	touched map[string]time.Time
 }
 
-func NewAuthService(repo repositories.AuthRepositoryInterface) *AuthService {
+func NewAuthService(repo repositories.AuthRepositoryInterface, tx repositories.TransactorInterface) *AuthService {
 	return &AuthService{
 		repo: repo,
+		tx:   tx,
+		// SECRET_HASH нужен только для проверки хэшей SHA-1, созданных до перехода на argon2id
+		hasher:  password.New(password.DefaultParams, os.Getenv("SECRET_HASH")),
+		revoked: newRevocationCache(),
+		touched: make(map[string]time.Time),
 	}
 }
 
@@ -45,7 +85,7 @@ func (a *AuthService) SignUp(ctx context.Context, req dto.RegisterRequest) (*dto
 	if exists {
 		return nil, errors.New("user with this email already exists")
 	}
-	hashedPassword, err := HashPassword(req.Password)
+	hashedPassword, err := a.hasher.Hash(req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
@@ -69,27 +109,46 @@ func (a *AuthService) SignUp(ctx context.Context, req dto.RegisterRequest) (*dto
 
 }
 
-func (a *AuthService) SignIn(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
-	hashPassword, err := HashPassword(req.Password)
+func (a *AuthService) SignIn(ctx context.Context, req dto.LoginRequest, client dto.ClientInfo) (*dto.AuthResponse, error) {
+	user, err := a.repo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, fmt.Errorf("failed to hash password: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
-	user, err := a.repo.CheckUserVerification(ctx, req.Email, hashPassword)
+	if user.ID == 0 {
+		// Хэшируем пароль впустую, чтобы время ответа для незарегистрированного email не отличалось
+		a.hasher.Hash(req.Password)
+		return nil, ErrInvalidCredentials
+	}
+	ok, needsRehash, err := a.hasher.Verify(req.Password, user.Password)
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, fmt.Errorf("failed to verify password: %w", err)
+	}
+	if !ok {
+		return nil, ErrInvalidCredentials
 	}
-	accesstoken, err := a.GenerateAccessToken(user.ID)
+	if needsRehash {
+		// Пароль только что проверен, поэтому устаревший хэш можно заменить хэшем с текущими параметрами
+		hashedPassword, err := a.hasher.Hash(req.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash password: %w", err)
+		}
+		if err := a.repo.UpdatePasswordHash(ctx, user.ID, hashedPassword); err != nil {
+			return nil, fmt.Errorf("failed to upgrade password hash: %w", err)
+		}
+	}
+	// Каждый вход начинает новое семейство refresh-токенов, поэтому сеансы на разных устройствах не мешают друг другу
+	familyID, err := newTokenID()
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+		return nil, fmt.Errorf("failed to generate token family: %w", err)
 	}
-	refreshToken, err := a.GenerateRefreshToken()
+	tokens, err := a.issueTokens(ctx, user.ID, familyID, time.Now(), client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, err
 	}
 
 	return &dto.AuthResponse{
-		AccessToken:  accesstoken.AccessToken,
-		RefreshToken: refreshToken.RefreshToken,
+		AccessToken:  tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
 		User: dto.UserInfo{
 			ID:        uint(user.ID),
 			Email:     user.Email,
@@ -99,6 +158,177 @@ func (a *AuthService) SignIn(ctx context.Context, req dto.LoginRequest) (*dto.Au
 	}, nil
 }
 
+// Refresh обменивает refresh-токен на новую пару токенов того же сеанса. Обмененный токен больше не принимается:
+// его повторное предъявление означает, что токен мог быть украден, и отзывает весь сеанс
+func (a *AuthService) Refresh(ctx context.Context, refresh_token string, client dto.ClientInfo) (*dto.TokenResponse, error) {
+	var response *dto.TokenResponse
+	var revoked *models.RevokedToken
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		token, err := a.repo.GetRefreshToken(ctx, refresh_token)
+		if err != nil {
+			return err
+		}
+		if token.ID == 0 {
+			return ErrInvalidRefreshToken
+		}
+		if token.RotatedAt != nil {
+			// Ошибка откатила бы отзыв вместе с транзакцией, поэтому о повторе сообщаем после фиксации
+			session, err := a.endSession(ctx, token.UserID, token.FamilyID)
+			if err != nil {
+				return err
+			}
+			revoked = &session
+			return nil
+		}
+		if !token.ExpiresAt.After(time.Now()) {
+			return ErrRefreshTokenExpired
+		}
+		if err := a.repo.MarkRefreshTokenRotated(ctx, token.ID); err != nil {
+			return err
+		}
+		response, err = a.issueTokens(ctx, token.UserID, token.FamilyID, token.SessionCreatedAt, client)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if revoked != nil {
+		a.revoked.Add(*revoked)
+		return nil, ErrRefreshTokenReused
+	}
+	return response, nil
+}
+
+// GetSessions возвращает действующие сеансы пользователя. currentSessionID - сеанс текущего запроса
+func (a *AuthService) GetSessions(ctx context.Context, userID int, currentSessionID string) (dto.SessionsListResponse, error) {
+	sessions, err := a.repo.GetSessions(ctx, userID)
+	if err != nil {
+		return dto.SessionsListResponse{}, err
+	}
+	response := dto.SessionsListResponse{
+		Sessions: make([]dto.SessionResponse, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, dto.SessionResponse{
+			ID:         session.FamilyID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.SessionCreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.FamilyID == currentSessionID,
+		})
+	}
+	return response, nil
+}
+
+// DeleteSession завершает сеанс пользователя: удаляет его refresh-токены и отзывает выданные в нем access-токены
+func (a *AuthService) DeleteSession(ctx context.Context, userID int, sessionID string) error {
+	var revoked models.RevokedToken
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		revoked, err = a.endSession(ctx, userID, sessionID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	a.revoked.Add(revoked)
+	return nil
+}
+
+// endSession удаляет refresh-токены сеанса и отзывает его access-токены. Вызывается внутри транзакции,
+// отзыв добавляется в кэш вызывающим после ее фиксации
+func (a *AuthService) endSession(ctx context.Context, userID int, sessionID string) (models.RevokedToken, error) {
+	deleted, err := a.repo.DeleteRefreshTokenFamily(ctx, userID, sessionID)
+	if err != nil {
+		return models.RevokedToken{}, err
+	}
+	if deleted == 0 {
+		return models.RevokedToken{}, ErrSessionNotFound
+	}
+	now := time.Now()
+	return a.repo.RevokeToken(ctx, models.RevokedToken{
+		UserID:    userID,
+		FamilyID:  sessionID,
+		RevokedAt: now,
+		// Позже все токены сеанса истекут сами
+		ExpiresAt: now.Add(JWTokenTTL),
+	})
+}
+
+// TouchSession отмечает использование сеанса запросом с его access-токеном. В базу время пишется
+// не чаще раза в SessionTouchInterval на сеанс, чтобы не обновлять строку при каждом запросе
+func (a *AuthService) TouchSession(ctx context.Context, userID int, sessionID string) error {
+	if sessionID == "" {
+		return nil
+	}
+	now := time.Now()
+	a.touchMu.Lock()
+	if last, ok := a.touched[sessionID]; ok && now.Sub(last) < SessionTouchInterval {
+		a.touchMu.Unlock()
+		return nil
+	}
+	// Устаревшие отметки больше ничего не ограничивают, а завершенные сеансы иначе оставались бы в карте навсегда
+	for id, last := range a.touched {
+		if now.Sub(last) >= SessionTouchInterval {
+			delete(a.touched, id)
+		}
+	}
+	a.touched[sessionID] = now
+	a.touchMu.Unlock()
+
+	return a.repo.TouchSession(ctx, userID, sessionID)
+}
+
+// DeleteExpiredRefreshTokens удаляет истекшие refresh-токены. Обмененные токены хранятся до истечения,
+// чтобы распознать их повторное использование
+func (a *AuthService) DeleteExpiredRefreshTokens(ctx context.Context) (int64, error) {
+	return a.repo.DeleteExpiredRefreshTokens(ctx)
+}
+
+// issueTokens выдает access-токен и сохраняет новый refresh-токен в семействе familyID вместе с данными клиента
+func (a *AuthService) issueTokens(ctx context.Context, userID int, familyID string, sessionCreatedAt time.Time, client dto.ClientInfo) (*dto.TokenResponse, error) {
+	// Отзыв всех токенов пользователя сравнивается с iat с точностью до секунды. Токен, выданный после отзыва
+	// в ту же секунду, получает iat следующей секунды, иначе он сразу считался бы отозванным
+	issued_at := time.Now()
+	revoked_at, err := a.repo.GetUserTokensRevokedAt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if cutoff := revoked_at.Truncate(time.Second); !revoked_at.IsZero() && !issued_at.Truncate(time.Second).After(cutoff) {
+		issued_at = cutoff.Add(time.Second)
+	}
+	accesstoken, err := a.GenerateAccessToken(userID, familyID, issued_at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+	refreshToken, err := a.GenerateRefreshToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	user_agent := client.UserAgent
+	if runes := []rune(user_agent); len(runes) > MaxUserAgentLength {
+		user_agent = string(runes[:MaxUserAgentLength])
+	}
+	err = a.repo.SaveNewRefreshToken(ctx, userID, models.RefreshToken{
+		Token:            refreshToken.RefreshToken,
+		FamilyID:         familyID,
+		ExpiresAt:        refreshToken.ExpiresAt,
+		UserAgent:        user_agent,
+		IPAddress:        client.IPAddress,
+		SessionCreatedAt: sessionCreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return &dto.TokenResponse{
+		AccessToken:  accesstoken.AccessToken,
+		RefreshToken: refreshToken.RefreshToken,
+		ExpiresAt:    accesstoken.ExpiresAt,
+	}, nil
+}
+
 func (a *AuthService) GenerateRefreshToken() (dto.RefreshTokenRequest, error) {
 	refresh_token := make([]byte, 32)
 	if _, err := rand.Read(refresh_token); err != nil {
@@ -112,13 +342,23 @@ func (a *AuthService) GenerateRefreshToken() (dto.RefreshTokenRequest, error) {
 	}, nil
 }
 
-func (a *AuthService) GenerateAccessToken(userID int) (dto.AccessTokenRequest, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Subject:   strconv.Itoa(userID),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(JWTokenTTL)),
+// GenerateAccessToken выдает access-токен сеансу sessionID со временем выдачи issuedAt
+func (a *AuthService) GenerateAccessToken(userID int, sessionID string, issuedAt time.Time) (dto.AccessTokenRequest, error) {
+	// jti позволяет отозвать конкретный токен до истечения его срока
+	jti, err := newTokenID()
+	if err != nil {
+		return dto.AccessTokenRequest{}, fmt.Errorf("failed to generate token id: %w", err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   strconv.Itoa(userID),
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
+			ExpiresAt: jwt.NewNumericDate(issuedAt.Add(JWTokenTTL)),
+		},
+		SessionID: sessionID,
 	})
-	err := godotenv.Load(".env")
+	err = godotenv.Load(".env")
 	if err != nil {
 		log.Fatal(err)
 		return dto.AccessTokenRequest{}, fmt.Errorf("failed to load environment file: %w", err)
@@ -133,82 +373,166 @@ func (a *AuthService) GenerateAccessToken(userID int) (dto.AccessTokenRequest, e
 	}
 	return dto.AccessTokenRequest{
 		AccessToken: tokenString,
-		ExpiresAt:   time.Now().Add(JWTokenTTL),
+		ExpiresAt:   issuedAt.Add(JWTokenTTL),
 	}, nil
 }
 
 func (a *AuthService) ValidateToken(ctx context.Context, req dto.AccessTokenRequest) (*dto.UserID, error) {
-
-	err := godotenv.Load(".env")
+	claims, err := a.parseAccessToken(req.AccessToken)
 	if err != nil {
-		return &dto.UserID{}, fmt.Errorf("failed to load environment file: %w", err)
+		return &dto.UserID{}, fmt.Errorf("invalid token: %w", err)
 	}
-
-	secretSignInKey := os.Getenv("SECRET_SIGNINKEY")
-	if secretSignInKey == "" {
-		return &dto.UserID{}, fmt.Errorf("SECRET_SIGNINKEY environment variable is not set")
+	// Токены без jti выданы до появления отзыва, отозвать их невозможно
+	if claims.ID == "" {
+		return &dto.UserID{}, fmt.Errorf("token has no id, sign in again")
 	}
 
-	token, err := jwt.ParseWithClaims(req.AccessToken, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Проверяем метод подписи
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secretSignInKey), nil
-	})
-
+	userID, err := strconv.Atoi(claims.Subject)
 	if err != nil {
-		return &dto.UserID{}, fmt.Errorf("invalid token: %w", err)
+		return &dto.UserID{}, fmt.Errorf("invalid user ID in access token: %w", err)
 	}
 
-	// Проверяем валидность claims
-	if claims, ok := token.Claims.(*jwt.RegisteredClaims); ok && token.Valid {
-		if claims.ExpiresAt != nil && claims.ExpiresAt.Time.Before(time.Now()) {
+	if !a.revoked.Synced() {
+		if err := a.syncRevokedTokens(ctx); err != nil {
 			return &dto.UserID{}, err
 		}
+	}
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	if a.revoked.IsRevoked(claims.ID, claims.SessionID, userID, issuedAt) {
+		return &dto.UserID{}, fmt.Errorf("token has been revoked")
+	}
 
-		userID, err := strconv.Atoi(claims.Subject)
-		if err != nil {
-			return &dto.UserID{}, fmt.Errorf("invalid user ID in access token: %w", err)
-		}
+	return &dto.UserID{
+		UserID:    userID,
+		SessionID: claims.SessionID,
+	}, nil
+}
 
-		return &dto.UserID{
-			UserID: userID,
-		}, nil
+// Logout отзывает текущий access-токен и удаляет refresh-токен. Любой из токенов может быть пустым
+func (a *AuthService) Logout(ctx context.Context, access_token string, refresh_token string) error {
+	if access_token != "" {
+		claims, err := a.parseAccessToken(access_token)
+		switch {
+		case errors.Is(err, jwt.ErrTokenExpired):
+			// Истекший токен уже недействителен, отзывать его не нужно
+		case err != nil:
+			return fmt.Errorf("invalid token: %w", err)
+		case claims.ID != "":
+			userID, err := strconv.Atoi(claims.Subject)
+			if err != nil {
+				return fmt.Errorf("invalid user ID in access token: %w", err)
+			}
+			revoked, err := a.repo.RevokeToken(ctx, models.RevokedToken{
+				UserID:    userID,
+				JTI:       claims.ID,
+				RevokedAt: time.Now(),
+				ExpiresAt: claims.ExpiresAt.Time,
+			})
+			if err != nil {
+				return err
+			}
+			a.revoked.Add(revoked)
+		}
 	}
-
-	return nil, fmt.Errorf("invalid token claims")
+	if refresh_token != "" {
+		if err := a.repo.DeleteRefreshToken(ctx, refresh_token); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func HashPassword(Password string) (string, error) {
-	hash := sha1.New()
-	err := godotenv.Load(".env")
+// LogoutAll завершает все сеансы пользователя: удаляет его refresh-токены и отзывает все выданные access-токены
+func (a *AuthService) LogoutAll(ctx context.Context, userID int) error {
+	var revoked models.RevokedToken
+	err := a.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		revoked, err = a.RevokeAllSessions(ctx, userID)
+		return err
+	})
 	if err != nil {
-		log.Fatal(err)
-		return "", err
+		return err
 	}
-	secretString := os.Getenv("SECRET_HASH") // получаем значение из файла конфигурации
-	_, err1 := hash.Write([]byte(Password))
-	if err1 != nil {
-		log.Println("Ошибка при шифровании пароля", err)
-		return "", err
+	a.CacheRevocation(revoked)
+	return nil
+}
+
+// RevokeAllSessions удаляет refresh-токены пользователя и отзывает все выданные ему access-токены в базе.
+// Внутри чужой транзакции возвращенный отзыв передается в CacheRevocation после ее фиксации
+func (a *AuthService) RevokeAllSessions(ctx context.Context, userID int) (models.RevokedToken, error) {
+	if err := a.repo.RemoveOldRefreshToken(ctx, userID); err != nil {
+		return models.RevokedToken{}, err
 	}
-	return fmt.Sprintf("%x", hash.Sum([]byte(secretString))), nil
+	now := time.Now()
+	return a.repo.RevokeToken(ctx, models.RevokedToken{
+		UserID:    userID,
+		RevokedAt: now,
+		// Позже все токены, выданные до now, истекут сами
+		ExpiresAt: now.Add(JWTokenTTL),
+	})
+}
+
+// CacheRevocation сразу применяет зафиксированный в базе отзыв к проверке токенов этого экземпляра
+func (a *AuthService) CacheRevocation(token models.RevokedToken) {
+	a.revoked.Add(token)
+}
 
+// SyncRevokedTokens удаляет из базы отзывы истекших токенов и загружает остальные в кэш,
+// в том числе отзывы, сделанные другими экземплярами сервера. Возвращает количество удаленных записей
+func (a *AuthService) SyncRevokedTokens(ctx context.Context) (int64, error) {
+	deleted, err := a.repo.DeleteExpiredRevokedTokens(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, a.syncRevokedTokens(ctx)
 }
 
-func (a *AuthService) GetUserIDbyRefreshToken(ctx context.Context, refresh_token string) (int, error) {
-	return a.repo.GetUserIDbyRefreshToken(ctx, refresh_token)
+func (a *AuthService) syncRevokedTokens(ctx context.Context) error {
+	tokens, err := a.repo.GetRevokedTokens(ctx)
+	if err != nil {
+		return err
+	}
+	a.revoked.Replace(tokens, time.Now())
+	return nil
 }
 
-func (a *AuthService) RemoveOldRefreshToken(ctx context.Context, userID int) error {
-	return a.repo.RemoveOldRefreshToken(ctx, userID)
+// parseAccessToken проверяет подпись и срок действия access-токена
+func (a *AuthService) parseAccessToken(access_token string) (*accessTokenClaims, error) {
+	err := godotenv.Load(".env")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environment file: %w", err)
+	}
+
+	secretSignInKey := os.Getenv("SECRET_SIGNINKEY")
+	if secretSignInKey == "" {
+		return nil, fmt.Errorf("SECRET_SIGNINKEY environment variable is not set")
+	}
+
+	claims := &accessTokenClaims{}
+	token, err := jwt.ParseWithClaims(access_token, claims, func(token *jwt.Token) (interface{}, error) {
+		// Проверяем метод подписи
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secretSignInKey), nil
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token claims")
+	}
+	return claims, nil
 }
 
-func (a *AuthService) SaveNewRefreshToken(ctx context.Context, user_id int, token dto.RefreshTokenRequest) error {
-	refresh_token := models.RefreshToken{
-		Token:     token.RefreshToken,
-		ExpiresAt: token.ExpiresAt,
+// newTokenID возвращает случайный идентификатор для jti и семейств refresh-токенов
+func newTokenID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
 	}
-	return a.repo.SaveNewRefreshToken(ctx, user_id, refresh_token)
+	return hex.EncodeToString(id), nil
 }
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	BudgetAlertNearEnd = "near_end"
+
+	// BudgetNearEndDays - за сколько дней до конца периода бюджета создается уведомление near_end
+	BudgetNearEndDays = 3
+	// MaxBudgetAlertThreshold - максимальный порог уведомления в процентах расходования
+	MaxBudgetAlertThreshold = 1000
+	// MaxBudgetAlertThresholds - максимальное количество порогов у одного бюджета
+	MaxBudgetAlertThresholds = 10
+)
+
+// DefaultBudgetAlertThresholds - пороги уведомлений нового бюджета, если они не переданы
+var DefaultBudgetAlertThresholds = []int{int(BudgetWarningPercentage), repositories.BudgetExceededPercentage}
+
+type BudgetAlertService struct {
+	repo repositories.BudgetAlertRepositoryInterface
+}
+
+func NewBudgetAlertService(repo repositories.BudgetAlertRepositoryInterface) *BudgetAlertService {
+	return &BudgetAlertService{
+		repo: repo,
+	}
+}
+
+func (a *BudgetAlertService) GetAlerts(ctx context.Context, userID uint, query dto.BudgetAlertsQuery) (dto.BudgetAlertsListResponse, error) {
+	alerts, err := a.repo.GetUserAlerts(ctx, userID, query.Unacknowledged)
+	if err != nil {
+		return dto.BudgetAlertsListResponse{}, err
+	}
+	response := dto.BudgetAlertsListResponse{
+		Alerts: make([]dto.BudgetAlert, 0, len(alerts)),
+	}
+	for _, alert := range alerts {
+		response.Alerts = append(response.Alerts, dto.BudgetAlert{
+			ID:             alert.ID,
+			BudgetID:       alert.BudgetID,
+			CategoryID:     alert.CategoryID,
+			CategoryName:   alert.CategoryName,
+			AlertType:      alert.AlertType,
+			Threshold:      alert.Threshold,
+			Message:        budgetAlertMessage(alert),
+			Percentage:     alert.Percentage,
+			Acknowledged:   alert.AcknowledgedAt != nil,
+			AcknowledgedAt: alert.AcknowledgedAt,
+			CreatedAt:      alert.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+func (a *BudgetAlertService) AcknowledgeAlert(ctx context.Context, userID uint, alertID int) error {
+	return a.repo.AcknowledgeAlert(ctx, userID, alertID)
+}
+
+// CreateNearEndAlerts создает уведомления для бюджетов, период которых заканчивается в ближайшие BudgetNearEndDays дней.
+// Вызывается планировщиком, повторный запуск не создает дубликатов
+func (a *BudgetAlertService) CreateNearEndAlerts(ctx context.Context, now time.Time) (int64, error) {
+	return a.repo.CreateNearEndAlerts(ctx, now, now.AddDate(0, 0, BudgetNearEndDays))
+}
+
+// NormalizeAlertThresholds проверяет пороги уведомлений, убирает дубликаты и сортирует по возрастанию.
+// nil означает пороги по умолчанию, пустой список отключает уведомления о расходовании
+func NormalizeAlertThresholds(thresholds []int) ([]int, error) {
+	if thresholds == nil {
+		return append([]int(nil), DefaultBudgetAlertThresholds...), nil
+	}
+	normalized := make([]int, 0, len(thresholds))
+	seen := make(map[int]bool, len(thresholds))
+	for _, threshold := range thresholds {
+		if threshold < 1 || threshold > MaxBudgetAlertThreshold {
+			return nil, fmt.Errorf("alert threshold must be between 1 and %d", MaxBudgetAlertThreshold)
+		}
+		if seen[threshold] {
+			continue
+		}
+		seen[threshold] = true
+		normalized = append(normalized, threshold)
+	}
+	if len(normalized) > MaxBudgetAlertThresholds {
+		return nil, fmt.Errorf("too many alert thresholds: maximum is %d", MaxBudgetAlertThresholds)
+	}
+	sort.Ints(normalized)
+	return normalized, nil
+}
+
+func budgetAlertMessage(alert models.BudgetAlert) string {
+	switch alert.AlertType {
+	case BudgetAlertNearEnd:
+		if alert.BudgetEndDate != nil {
+			return fmt.Sprintf("Budget %q ends on %s, %.2f%% spent", alert.CategoryName, inclusiveEndDate(*alert.BudgetEndDate).Format("2006-01-02"), alert.Percentage)
+		}
+		return fmt.Sprintf("Budget %q ends soon, %.2f%% spent", alert.CategoryName, alert.Percentage)
+	case BudgetStatusExceeded:
+		return fmt.Sprintf("Budget %q reached %d%% of the limit: %.2f%% spent", alert.CategoryName, alert.Threshold, alert.Percentage)
+	default:
+		return fmt.Sprintf("Budget %q passed %d%%: %.2f%% spent", alert.CategoryName, alert.Threshold, alert.Percentage)
+	}
+}
@@ -2,63 +2,132 @@ package services
 
 import (
 	"context"
-	//"errors"
+	"errors"
 	"finance/internal/dto"
 	"finance/internal/models"
 	"finance/internal/repositories"
-	"finance/pkg"
+	"finance/pkg/money"
+	"finance/pkg/period"
+	"fmt"
+	"math"
 	"time"
 )
 
+const (
+	BudgetStatusOK       = "ok"
+	BudgetStatusWarning  = "warning"
+	BudgetStatusExceeded = "exceeded"
+
+	// BudgetWarningPercentage - доля потраченного бюджета (в процентах), начиная с которой статус становится "warning"
+	BudgetWarningPercentage = 80.0
+	// BudgetExceededPercentage - доля потраченного бюджета (в процентах), начиная с которой статус становится "exceeded"
+	BudgetExceededPercentage = float64(repositories.BudgetExceededPercentage)
+
+	// Что учитывает бюджет
+	BudgetScopeCategory = "category"
+	BudgetScopeGroup    = "group"
+	BudgetScopeOverall  = "overall"
+)
+
 type BudgetService struct {
-	repo         repositories.BudgetRepositoryInterface
-	expense_repo repositories.ExpenseRepositoryInterface
+	repo       repositories.BudgetRepositoryInterface
+	alert_repo repositories.BudgetAlertRepositoryInterface
+	rate_repo  repositories.ExchangeRateRepositoryInterface
+	tx         repositories.TransactorInterface
 }
 
-func NewBudgetService(repo repositories.BudgetRepositoryInterface, expense_repo repositories.ExpenseRepositoryInterface) *BudgetService {
+func NewBudgetService(repo repositories.BudgetRepositoryInterface, alert_repo repositories.BudgetAlertRepositoryInterface, rate_repo repositories.ExchangeRateRepositoryInterface, tx repositories.TransactorInterface) *BudgetService {
 	return &BudgetService{
-		repo:         repo,
-		expense_repo: expense_repo,
+		repo:       repo,
+		alert_repo: alert_repo,
+		rate_repo:  rate_repo,
+		tx:         tx,
 	}
 }
 
 func (b *BudgetService) CreateBudget(ctx context.Context, userID uint, category_id int, req dto.CreateBudgetRequest) (dto.BudgetResponse, error) {
-	start_date := time.Now()
-	endDate, err := pkg.AddPeriodToDate(start_date, req.Period)
+	spec := period.DefaultSpec(req.Period)
+	if req.WeekStart != nil {
+		spec.WeekStart = time.Weekday(*req.WeekStart)
+	}
+	if req.MonthStartDay != nil {
+		spec.MonthStartDay = *req.MonthStartDay
+	}
+	if req.YearStartMonth != nil {
+		spec.YearStartMonth = time.Month(*req.YearStartMonth)
+	}
+	date_range, err := resolveBudgetRange(spec, req.StartDate, req.EndDate)
 	if err != nil {
 		return dto.BudgetResponse{}, err
 	}
-	req_budget := models.Budget{
-		UserID:      req.UserID,
-		CategoryID:  uint(category_id),
-		Amount:      req.Amount,
-		SpentAmount: 0,
-		Period:      req.Period,
-		StartDate:   start_date,
-		EndDate:     endDate,
+	thresholds, err := NormalizeAlertThresholds(req.AlertThresholds)
+	if err != nil {
+		return dto.BudgetResponse{}, err
 	}
-
-	res_budget, err := b.repo.CreateBudget(ctx, req_budget)
+	scope, category_ids, err := resolveBudgetScope(category_id, req.CategoryIDs)
 	if err != nil {
 		return dto.BudgetResponse{}, err
 	}
+	currency, err := b.resolveBudgetCurrency(ctx, userID, req.Currency, date_range.Start)
+	if err != nil {
+		return dto.BudgetResponse{}, err
+	}
+	req_budget := models.Budget{
+		UserID:          userID,
+		CategoryID:      uint(category_id),
+		Scope:           scope,
+		Name:            req.Name,
+		CategoryIDs:     category_ids,
+		Amount:          req.Amount,
+		SpentAmount:     0,
+		StartDate:       date_range.Start,
+		EndDate:         date_range.End,
+		AlertThresholds: thresholds,
+		// Разовый custom-бюджет по умолчанию не продлевается
+		AutoRenew: spec.Period != period.Custom,
+		CarryOver: req.CarryOver,
+		Currency:  currency,
+	}
+	if req.AutoRenew != nil {
+		req_budget.AutoRenew = *req.AutoRenew
+	}
+	setBudgetSpec(&req_budget, spec)
 
-	// Пересчитываем потраченную сумму для нового бюджета
-	err = b.recalculateBudgetSpentAmount(ctx, &res_budget)
+	var res_budget models.Budget
+	err = b.tx.WithinTx(ctx, func(ctx context.Context) error {
+		err := b.checkBudgetOverlap(ctx, req_budget)
+		if err != nil {
+			return err
+		}
+		res_budget, err = b.repo.CreateBudget(ctx, req_budget)
+		if err != nil {
+			return err
+		}
+		if res_budget.Scope == BudgetScopeGroup {
+			err = b.repo.SetBudgetCategories(ctx, userID, res_budget.ID, res_budget.CategoryIDs)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Пересчитываем потраченную сумму для нового бюджета с учетом уже существующих расходов
+		err = b.recalculateBudgetSpentAmount(ctx, &res_budget)
+		if err != nil {
+			return err
+		}
+		err = b.repo.UpdateSpentAmount(ctx, category_id, res_budget.ID, res_budget.SpentAmount)
+		if err != nil {
+			return err
+		}
+		// Уже существующие расходы могли сразу превысить пороги нового бюджета
+		_, err = b.alert_repo.CreateThresholdAlerts(ctx, userID, alertCategoryID(res_budget), res_budget.StartDate)
+		return err
+	})
 	if err != nil {
 		return dto.BudgetResponse{}, err
 	}
 
-	return dto.BudgetResponse{
-		ID:              res_budget.ID,
-		CategoryID:      res_budget.CategoryID,
-		Amount:          res_budget.Amount,
-		SpentAmount:     res_budget.SpentAmount,
-		RemainingAmount: res_budget.Amount - res_budget.SpentAmount,
-		Period:          res_budget.Period,
-		StartDate:       res_budget.StartDate,
-		EndDate:         res_budget.EndDate,
-	}, nil
+	return toBudgetResponse(res_budget), nil
 
 }
 
@@ -71,34 +140,491 @@ func (b *BudgetService) GetUserBudgets(ctx context.Context, userID uint, categor
 
 	// Преобразуем каждый элемент из models.Budget в dto.BudgetResponse
 	for i, budget := range budgets {
-		budgetResponses[i] = dto.BudgetResponse{
-			ID:          budget.ID,
-			CategoryID:  uint(category_id),
-			Amount:      budget.Amount,
-			SpentAmount: budget.SpentAmount,
-			Period:      budget.Period,
-			StartDate:   budget.StartDate,
-			EndDate:     budget.EndDate,
-		}
+		budgetResponses[i] = toBudgetResponse(budget)
 	}
 	return budgetResponses, nil
 }
 
+func (b *BudgetService) UpdateBudget(ctx context.Context, userID uint, category_id int, budgetID int, req dto.UpdateBudgetRequest) (dto.BudgetResponse, error) {
+	budget, err := b.repo.GetBudgetByID(ctx, userID, category_id, budgetID)
+	if err != nil {
+		return dto.BudgetResponse{}, err
+	}
+
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			return dto.BudgetResponse{}, errors.New("amount must be greater than zero")
+		}
+		budget.Amount = *req.Amount
+	}
+
+	spec := budgetSpec(budget)
+	rangeChanged := req.StartDate != nil || req.EndDate != nil
+	if req.Period != nil {
+		spec.Period = *req.Period
+		rangeChanged = true
+	}
+	if req.WeekStart != nil {
+		spec.WeekStart = time.Weekday(*req.WeekStart)
+		rangeChanged = true
+	}
+	if req.MonthStartDay != nil {
+		spec.MonthStartDay = *req.MonthStartDay
+		rangeChanged = true
+	}
+	if req.YearStartMonth != nil {
+		spec.YearStartMonth = time.Month(*req.YearStartMonth)
+		rangeChanged = true
+	}
+	if rangeChanged {
+		// Границы пересчитываются от начала текущего периода, если не передано новое начало
+		start := budget.StartDate
+		if req.StartDate != nil {
+			start = *req.StartDate
+		}
+		if start.IsZero() {
+			start = time.Now()
+		}
+		end := req.EndDate
+		if end == nil && spec.Period == period.Custom && budget.Period == period.Custom {
+			last_day := inclusiveEndDate(budget.EndDate)
+			end = &last_day
+		}
+		date_range, err := resolveBudgetRange(spec, &start, end)
+		if err != nil {
+			return dto.BudgetResponse{}, err
+		}
+		setBudgetSpec(&budget, spec)
+		budget.StartDate = date_range.Start
+		budget.EndDate = date_range.End
+	}
+	categoriesChanged := req.CategoryIDs != nil
+	if categoriesChanged {
+		if budget.Scope == BudgetScopeCategory {
+			return dto.BudgetResponse{}, errors.New("category_ids can only be changed for overall and group budgets")
+		}
+		budget.Scope, budget.CategoryIDs, err = resolveBudgetScope(0, req.CategoryIDs)
+		if err != nil {
+			return dto.BudgetResponse{}, err
+		}
+	}
+	if req.Name != nil {
+		budget.Name = *req.Name
+	}
+	if req.AlertThresholds != nil {
+		thresholds, err := NormalizeAlertThresholds(req.AlertThresholds)
+		if err != nil {
+			return dto.BudgetResponse{}, err
+		}
+		budget.AlertThresholds = thresholds
+	}
+	if req.AutoRenew != nil {
+		budget.AutoRenew = *req.AutoRenew
+	}
+	if req.CarryOver != nil {
+		budget.CarryOver = *req.CarryOver
+	}
+	currencyChanged := false
+	if req.Currency != nil {
+		currency, err := b.resolveBudgetCurrency(ctx, userID, *req.Currency, budget.StartDate)
+		if err != nil {
+			return dto.BudgetResponse{}, err
+		}
+		currencyChanged = currency != budget.Currency
+		budget.Currency = currency
+	}
+
+	err = b.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if rangeChanged {
+			err := b.checkBudgetOverlap(ctx, budget)
+			if err != nil {
+				return err
+			}
+		}
+		if categoriesChanged {
+			err := b.repo.SetBudgetCategories(ctx, userID, budget.ID, budget.CategoryIDs)
+			if err != nil {
+				return err
+			}
+		}
+		err := b.repo.UpdateBudget(ctx, budget)
+		if err != nil {
+			return err
+		}
+		if rangeChanged || categoriesChanged || currencyChanged {
+			// Границы периода, набор категорий или валюта изменились, поэтому потраченная сумма считается заново
+			err = b.recalculateBudgetSpentAmount(ctx, &budget)
+			if err != nil {
+				return err
+			}
+			err = b.repo.UpdateSpentAmount(ctx, 0, budget.ID, budget.SpentAmount)
+			if err != nil {
+				return err
+			}
+		}
+		// Уменьшенный лимит или новые пороги могут быть уже достигнуты
+		_, err = b.alert_repo.CreateThresholdAlerts(ctx, userID, alertCategoryID(budget), budget.StartDate)
+		return err
+	})
+	if err != nil {
+		return dto.BudgetResponse{}, err
+	}
+
+	return toBudgetResponse(budget), nil
+}
+
+// CheckBudgetStatus возвращает статус каждого бюджета пользователя по всем категориям
+func (b *BudgetService) CheckBudgetStatus(ctx context.Context, userID uint) ([]*dto.BudgetStatus, error) {
+	budgets, err := b.repo.GetUserBudgets(ctx, 0, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	statuses := make([]*dto.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		statuses = append(statuses, buildBudgetStatus(budget, now))
+	}
+	return statuses, nil
+}
+
+// RecalculateBudgets заново считает потраченную сумму всех бюджетов пользователя по таблице расходов.
+// userID = 0 - бюджеты всех пользователей. Бюджеты, для расходов которых нет курса к валюте бюджета, не меняются
+func (b *BudgetService) RecalculateBudgets(ctx context.Context, userID uint) (dto.RecalculateBudgetsResponse, error) {
+	updated, skipped, err := b.repo.RecalculateSpentAmounts(ctx, userID)
+	if err != nil {
+		return dto.RecalculateBudgetsResponse{}, err
+	}
+	return dto.RecalculateBudgetsResponse{
+		UpdatedBudgets: updated,
+		SkippedBudgets: skipped,
+	}, nil
+}
+
+// errBudgetsWithoutRate - ошибка пересчета бюджетов, для расходов которых нет курса к валюте бюджета
+func errBudgetsWithoutRate(skipped int64) error {
+	return fmt.Errorf("no exchange rate to the currency of %d budgets", skipped)
+}
+
+// GetBudgetHistory возвращает закрытые периоды бюджета с итоговой потраченной суммой
+func (b *BudgetService) GetBudgetHistory(ctx context.Context, userID uint, category_id int, budgetID int) (dto.BudgetHistoryResponse, error) {
+	// Проверяем, что бюджет принадлежит пользователю, иначе пустая история неотличима от чужого бюджета
+	_, err := b.repo.GetBudgetByID(ctx, userID, category_id, budgetID)
+	if err != nil {
+		return dto.BudgetHistoryResponse{}, err
+	}
+	history, err := b.repo.GetBudgetHistory(ctx, userID, category_id, budgetID)
+	if err != nil {
+		return dto.BudgetHistoryResponse{}, err
+	}
+
+	periods := make([]dto.BudgetPeriodResponse, len(history))
+	for i, closed := range history {
+		limit := closed.Amount.Add(closed.CarriedAmount)
+		spentPercentage, status := classifyBudgetSpending(closed.SpentAmount, limit)
+		periods[i] = dto.BudgetPeriodResponse{
+			Period:          closed.Period,
+			StartDate:       closed.StartDate,
+			EndDate:         inclusiveEndDate(closed.EndDate),
+			Amount:          closed.Amount,
+			CarriedAmount:   closed.CarriedAmount,
+			SpentAmount:     closed.SpentAmount,
+			RemainingAmount: limit.Sub(closed.SpentAmount),
+			SpentPercentage: roundAmount(spentPercentage),
+			Status:          status,
+		}
+	}
+	return dto.BudgetHistoryResponse{
+		BudgetID: uint(budgetID),
+		Periods:  periods,
+	}, nil
+}
+
+// RolloverDueBudgets переводит автоматически продлеваемые бюджеты с закончившимся периодом на следующий период,
+// включая периоды, пропущенные за время простоя сервера. Закрытые периоды сохраняются в историю.
+// Каждый бюджет обрабатывается в своей транзакции. Возвращает количество продленных периодов
+func (b *BudgetService) RolloverDueBudgets(ctx context.Context, now time.Time) (int, error) {
+	ids, err := b.repo.GetDueBudgetIDs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	rolled := 0
+	var errs []error
+	for _, id := range ids {
+		count := 0
+		err := b.tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			count, err = b.rolloverBudget(ctx, id, now)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("budget %d: %w", id, err))
+			continue
+		}
+		rolled += count
+	}
+	return rolled, errors.Join(errs...)
+}
+
+// rolloverBudget закрывает все закончившиеся периоды одного бюджета. Вызывается внутри транзакции
+func (b *BudgetService) rolloverBudget(ctx context.Context, id uint, now time.Time) (int, error) {
+	budget, err := b.repo.LockBudget(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	rolled := 0
+	// После блокировки end_date перечитан: если период уже продлен другим запуском, цикл не выполнится
+	for budget.AutoRenew && !budget.EndDate.After(now) {
+		err := b.repo.ArchiveBudgetPeriod(ctx, budget)
+		if err != nil {
+			return 0, err
+		}
+
+		next_range, err := budgetSpec(budget).Next(period.DateRange{Start: budget.StartDate, End: budget.EndDate})
+		if err != nil {
+			return 0, err
+		}
+		next := budget
+		next.StartDate = next_range.Start
+		next.EndDate = next_range.End
+		next.CarriedAmount = 0
+		if budget.CarryOver {
+			// Остаток увеличивает лимит следующего периода, перерасход - уменьшает
+			next.CarriedAmount = budgetLimit(budget).Sub(budget.SpentAmount)
+		}
+
+		// Пользователь мог заранее создать бюджет на следующий период, тогда этот бюджет больше не продлевается
+		overlaps, err := b.repo.HasOverlappingBudget(ctx, next)
+		if err != nil {
+			return 0, err
+		}
+		if overlaps {
+			budget.AutoRenew = false
+			break
+		}
+
+		err = b.recalculateBudgetSpentAmount(ctx, &next)
+		if err != nil {
+			return 0, err
+		}
+		budget = next
+		rolled++
+	}
+
+	err = b.repo.UpdateBudget(ctx, budget)
+	if err != nil {
+		return 0, err
+	}
+	if rolled > 0 {
+		// Расходы, уже внесенные в новый период, могли сразу достичь порогов
+		_, err = b.alert_repo.CreateThresholdAlerts(ctx, budget.UserID, alertCategoryID(budget), budget.StartDate)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return rolled, nil
+}
+
+// checkBudgetOverlap не дает создать второй бюджет той же категории и периода на пересекающиеся даты
+func (b *BudgetService) checkBudgetOverlap(ctx context.Context, budget models.Budget) error {
+	overlaps, err := b.repo.HasOverlappingBudget(ctx, budget)
+	if err != nil {
+		return err
+	}
+	if overlaps {
+		return errors.New("budget for this category and period already exists for these dates")
+	}
+	return nil
+}
+
 func (b *BudgetService) DeleteBudget(ctx context.Context, userID uint, category_id int, budgetID int) error {
 	return b.repo.DeleteBudget(ctx, userID, category_id, budgetID)
 }
 
-// recalculateBudgetSpentAmount пересчитывает потраченную сумму для бюджета
+// resolveBudgetCurrency проверяет валюту бюджета и наличие курса к базовой валюте пользователя на дату начала бюджета.
+// Пустая валюта означает базовую валюту пользователя
+func (b *BudgetService) resolveBudgetCurrency(ctx context.Context, userID uint, currency string, start time.Time) (string, error) {
+	if currency != "" {
+		var err error
+		currency, err = NormalizeCurrency(currency)
+		if err != nil {
+			return "", err
+		}
+	}
+	base, _, err := b.rate_repo.GetRateToBase(ctx, userID, currency, start)
+	if err != nil {
+		return "", err
+	}
+	if currency == "" {
+		return base, nil
+	}
+	return currency, nil
+}
+
+// recalculateBudgetSpentAmount пересчитывает потраченную сумму бюджета по всем категориям, которые он учитывает.
+// Бюджет и его набор категорий уже должны быть сохранены
 func (b *BudgetService) recalculateBudgetSpentAmount(ctx context.Context, budget *models.Budget) error {
-	expenses, err := b.expense_repo.GetExpensesByCategoryAndPeriod(ctx, budget.UserID, int(budget.CategoryID), budget.StartDate, budget.EndDate)
+	spent, err := b.repo.GetBudgetSpentAmount(ctx, budget.ID, budget.StartDate, budget.EndDate, budget.Currency)
 	if err != nil {
 		return err
 	}
+	budget.SpentAmount = spent
+	return nil
+}
+
+// buildBudgetStatus рассчитывает процент расходования, статус и количество оставшихся дней бюджета
+func buildBudgetStatus(budget models.Budget, now time.Time) *dto.BudgetStatus {
+	limit := budgetLimit(budget)
+	spentPercentage, status := classifyBudgetSpending(budget.SpentAmount, limit)
+
+	var daysRemaining int
+	if !budget.EndDate.IsZero() && budget.EndDate.After(now) {
+		daysRemaining = int(math.Ceil(budget.EndDate.Sub(now).Hours() / 24))
+	}
 
-	var totalSpent float64
-	for _, expense := range expenses {
-		totalSpent += expense.Amount
+	return &dto.BudgetStatus{
+		BudgetID:        budget.ID,
+		CategoryName:    budget.CategoryName,
+		BudgetAmount:    limit,
+		SpentAmount:     budget.SpentAmount,
+		RemainingAmount: limit.Sub(budget.SpentAmount),
+		SpentPercentage: spentPercentage,
+		Status:          status,
+		DaysRemaining:   daysRemaining,
+		Currency:        budget.Currency,
 	}
-	budget.SpentAmount = totalSpent
-	return nil
+}
+
+// budgetLimit возвращает лимит текущего периода с учетом суммы, перенесенной из предыдущего
+func budgetLimit(budget models.Budget) money.Amount {
+	return budget.Amount.Add(budget.CarriedAmount)
+}
+
+// classifyBudgetSpending возвращает процент расходования лимита и статус бюджета.
+// Лимит может быть нулевым или отрицательным после переноса перерасхода, тогда любой расход - превышение
+func classifyBudgetSpending(spent, limit money.Amount) (float64, string) {
+	var spentPercentage float64
+	if limit.IsPositive() {
+		spentPercentage = spent.Ratio(limit) * 100
+	} else if spent.IsPositive() {
+		return 0, BudgetStatusExceeded
+	}
+
+	status := BudgetStatusOK
+	if spentPercentage >= BudgetExceededPercentage {
+		status = BudgetStatusExceeded
+	} else if spentPercentage >= BudgetWarningPercentage {
+		status = BudgetStatusWarning
+	}
+	return spentPercentage, status
+}
+
+// resolveBudgetRange возвращает границы периода бюджета: для custom - явные даты начала и конца,
+// для остальных периодов - календарный период, в который попадает start (по умолчанию текущий)
+func resolveBudgetRange(spec period.Spec, start, end *time.Time) (period.DateRange, error) {
+	if err := spec.Validate(); err != nil {
+		return period.DateRange{}, err
+	}
+	if spec.Period == period.Custom {
+		if start == nil || end == nil {
+			return period.DateRange{}, errors.New("start_date and end_date are required for custom budgets")
+		}
+		return period.Explicit(*start, *end)
+	}
+	if end != nil {
+		return period.DateRange{}, errors.New("end_date is only allowed for custom budgets")
+	}
+	date := time.Now()
+	if start != nil {
+		date = *start
+	}
+	return spec.Containing(date)
+}
+
+// inclusiveEndDate переводит конец периода из базы, где он не входит в период, в последний день периода,
+// как end_date в запросах и to в аналитике
+func inclusiveEndDate(end time.Time) time.Time {
+	if end.IsZero() {
+		return end
+	}
+	return end.AddDate(0, 0, -1)
+}
+
+// budgetSpec собирает правило нарезки периодов из полей бюджета
+func budgetSpec(budget models.Budget) period.Spec {
+	return period.Spec{
+		Period:         budget.Period,
+		WeekStart:      time.Weekday(budget.WeekStart),
+		MonthStartDay:  budget.MonthStartDay,
+		YearStartMonth: time.Month(budget.YearStartMonth),
+	}
+}
+
+// setBudgetSpec сохраняет правило нарезки периодов в поля бюджета
+func setBudgetSpec(budget *models.Budget, spec period.Spec) {
+	budget.Period = spec.Period
+	budget.WeekStart = int(spec.WeekStart)
+	budget.MonthStartDay = spec.MonthStartDay
+	budget.YearStartMonth = int(spec.YearStartMonth)
+}
+
+func toBudgetResponse(budget models.Budget) dto.BudgetResponse {
+	return dto.BudgetResponse{
+		ID:              budget.ID,
+		CategoryID:      budget.CategoryID,
+		Amount:          budget.Amount,
+		SpentAmount:     budget.SpentAmount,
+		RemainingAmount: budgetLimit(budget).Sub(budget.SpentAmount),
+		Period:          budget.Period,
+		StartDate:       budget.StartDate,
+		EndDate:         inclusiveEndDate(budget.EndDate),
+		AlertThresholds: budget.AlertThresholds,
+		AutoRenew:       budget.AutoRenew,
+		CarryOver:       budget.CarryOver,
+		CarriedAmount:   budget.CarriedAmount,
+		WeekStart:       budget.WeekStart,
+		MonthStartDay:   budget.MonthStartDay,
+		YearStartMonth:  budget.YearStartMonth,
+		Scope:           budget.Scope,
+		Name:            budget.Name,
+		CategoryIDs:     budget.CategoryIDs,
+		Currency:        budget.Currency,
+	}
+}
+
+// resolveBudgetScope определяет, что учитывает бюджет: категорию из маршрута, группу категорий
+// или, если категории не заданы, все расходы пользователя
+func resolveBudgetScope(category_id int, categoryIDs []uint) (string, []uint, error) {
+	if category_id != 0 {
+		if len(categoryIDs) > 0 {
+			return "", nil, errors.New("category_ids are only allowed for budgets created via /budgets")
+		}
+		return BudgetScopeCategory, nil, nil
+	}
+
+	seen := make(map[uint]bool, len(categoryIDs))
+	ids := make([]uint, 0, len(categoryIDs))
+	for _, id := range categoryIDs {
+		if id == 0 {
+			return "", nil, errors.New("category_ids must contain positive ids")
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return BudgetScopeOverall, ids, nil
+	}
+	return BudgetScopeGroup, ids, nil
+}
+
+// alertCategoryID возвращает категорию, по которой CreateThresholdAlerts найдет бюджет:
+// для группы подходит любая ее категория, общий бюджет находится по любой категории, в том числе 0
+func alertCategoryID(budget models.Budget) int {
+	if budget.Scope == BudgetScopeGroup && len(budget.CategoryIDs) > 0 {
+		return int(budget.CategoryIDs[0])
+	}
+	return int(budget.CategoryID)
 }
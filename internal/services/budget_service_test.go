@@ -0,0 +1,35 @@
+package services
+
+import (
+	"finance/pkg/money"
+	"testing"
+)
+
+func TestClassifyBudgetSpending(t *testing.T) {
+	tests := []struct {
+		name       string
+		spent      int64
+		limit      int64
+		percentage float64
+		status     string
+	}{
+		{"nothing spent", 0, 10000, 0, BudgetStatusOK},
+		{"below warning", 7999, 10000, 79.99, BudgetStatusOK},
+		{"warning boundary", 8000, 10000, 80, BudgetStatusWarning},
+		{"almost exceeded", 9999, 10000, 99.99, BudgetStatusWarning},
+		// Ровно 100% - превышение, как и у уведомления с порогом 100
+		{"exceeded boundary", 10000, 10000, 100, BudgetStatusExceeded},
+		{"over limit", 15000, 10000, 150, BudgetStatusExceeded},
+		{"zero limit without spending", 0, 0, 0, BudgetStatusOK},
+		{"zero limit with spending", 1, 0, 0, BudgetStatusExceeded},
+		{"negative limit after carry over", 1, -500, 0, BudgetStatusExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percentage, status := classifyBudgetSpending(money.FromMinor(tt.spent), money.FromMinor(tt.limit))
+			if roundAmount(percentage) != tt.percentage || status != tt.status {
+				t.Errorf("classifyBudgetSpending = %v, %q; want %v, %q", percentage, status, tt.percentage, tt.status)
+			}
+		})
+	}
+}
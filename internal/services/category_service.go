@@ -2,10 +2,10 @@ package services
 
 import (
 	"context"
-	"errors"
 	"finance/internal/dto"
 	"finance/internal/models"
 	"finance/internal/repositories"
+	"finance/pkg/period"
 	"time"
 )
 
@@ -13,13 +13,15 @@ type CategoryService struct {
 	repo         repositories.CategoryRepositoryInterface
 	budget_repo  repositories.BudgetRepositoryInterface
 	expense_repo repositories.ExpenseRepositoryInterface
+	tx           repositories.TransactorInterface
 }
 
-func NewCategoryService(repo repositories.CategoryRepositoryInterface, budget_repo repositories.BudgetRepositoryInterface, expense_repo repositories.ExpenseRepositoryInterface) *CategoryService {
+func NewCategoryService(repo repositories.CategoryRepositoryInterface, budget_repo repositories.BudgetRepositoryInterface, expense_repo repositories.ExpenseRepositoryInterface, tx repositories.TransactorInterface) *CategoryService {
 	return &CategoryService{
 		repo:         repo,
 		budget_repo:  budget_repo,
 		expense_repo: expense_repo,
+		tx:           tx,
 	}
 }
 
@@ -95,63 +97,76 @@ func (c *CategoryService) GetMostUsedCategories(ctx context.Context, userID uint
 }
 
 func (c *CategoryService) DeleteCategory(ctx context.Context, userID uint, categoryID int) error {
-	err := c.budget_repo.DeleteBudgetsInCategory(ctx, userID, categoryID)
-	if err != nil {
-		return err
-	}
-	err = c.expense_repo.DeleteExpensesInCategory(ctx, userID, categoryID)
-	if err != nil {
-		return nil
-	}
-	return c.repo.DeleteCategory(ctx, userID, categoryID)
+	// Бюджеты, расходы и сама категория удаляются в одной транзакции
+	return c.tx.WithinTx(ctx, func(ctx context.Context) error {
+		err := c.budget_repo.DeleteBudgetsInCategory(ctx, userID, categoryID)
+		if err != nil {
+			return err
+		}
+		err = c.expense_repo.DeleteExpensesInCategory(ctx, userID, categoryID)
+		if err != nil {
+			return err
+		}
+		// Общие бюджеты и группы учитывали удаленные расходы
+		_, skipped, err := c.budget_repo.RecalculateSpentAmounts(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if skipped > 0 {
+			return errBudgetsWithoutRate(skipped)
+		}
+		return c.repo.DeleteCategory(ctx, userID, categoryID)
+	})
 }
 
-func (c *CategoryService) GetAnalyticsByCategory(ctx context.Context, userID uint, categoryID int, period dto.CategoryPeriod) (dto.CategoryAnalytics, error) {
-	category_name, err := c.repo.GetCategoryByID(ctx, userID, categoryID)
+func (c *CategoryService) GetAnalyticsByCategory(ctx context.Context, userID uint, categoryID int, filter dto.CategoryPeriod) (dto.CategoryAnalytics, error) {
+	now := time.Now()
+	date_range, err := period.Resolve(filter.Period, filter.From, filter.To, now)
 	if err != nil {
 		return dto.CategoryAnalytics{}, err
 	}
-	total_amount, err := c.repo.GetTotalAmountInCategory(ctx, userID, categoryID, period.Period)
+	category_name, err := c.repo.GetCategoryByID(ctx, userID, categoryID)
 	if err != nil {
 		return dto.CategoryAnalytics{}, err
 	}
-	expense_count, err := c.repo.GetExpenseCountInCategory(ctx, userID, categoryID, period.Period)
+	total_amount, err := c.repo.GetTotalAmountInCategory(ctx, userID, categoryID, date_range.Start, date_range.End)
 	if err != nil {
 		return dto.CategoryAnalytics{}, err
 	}
-	largest_expense, err := c.repo.GetLargestExpenseInCategory(ctx, userID, categoryID, period.Period)
+	expense_count, err := c.repo.GetExpenseCountInCategory(ctx, userID, categoryID, date_range.Start, date_range.End)
 	if err != nil {
 		return dto.CategoryAnalytics{}, err
 	}
-	smallest_expense, err := c.repo.GetSmallestExpenseInCategory(ctx, userID, categoryID, period.Period)
+	largest_expense, err := c.repo.GetLargestExpenseInCategory(ctx, userID, categoryID, date_range.Start, date_range.End)
 	if err != nil {
 		return dto.CategoryAnalytics{}, err
 	}
-	var timedist float64
-	if period.Period == "weekly" {
-		timedist = 7
-	} else if period.Period == "monthly" {
-		timedist = 30
-	} else if period.Period == "yearly" {
-		timedist = 365
-	} else {
-		return dto.CategoryAnalytics{}, errors.New("invalid period: No data of period")
+	smallest_expense, err := c.repo.GetSmallestExpenseInCategory(ctx, userID, categoryID, date_range.Start, date_range.End)
+	if err != nil {
+		return dto.CategoryAnalytics{}, err
 	}
-	averagePerDay := total_amount / timedist
+	average_expense := total_amount.Div(expense_count)
+	days := date_range.ElapsedDays(now)
 
 	return dto.CategoryAnalytics{
 		CategoryID:    uint(categoryID),
 		CategoryName:  category_name.Name,
-		Period:        period.Period,
+		Period:        date_range.Label,
+		From:          date_range.Start,
+		To:            date_range.End.AddDate(0, 0, -1),
+		Days:          days,
 		TotalAmount:   total_amount,
 		ExpensesCount: expense_count,
-		AveragePerDay: averagePerDay,
+		AveragePerDay: averagePerDay(total_amount, days),
 		LargestExpense: dto.ExpenseResponse{
 			ID:           largest_expense.ID,
 			CategoryID:   largest_expense.CategoryID,
 			CategoryName: largest_expense.CategoryName,
 			Amount:       largest_expense.Amount,
+			Currency:     largest_expense.Currency,
+			BaseAmount:   largest_expense.BaseAmount,
 			Description:  &largest_expense.Description,
+			Date:         largest_expense.Date,
 			CreatedAt:    largest_expense.CreatedAt,
 		},
 		SmallestExpense: dto.ExpenseResponse{
@@ -159,9 +174,12 @@ func (c *CategoryService) GetAnalyticsByCategory(ctx context.Context, userID uin
 			CategoryID:   smallest_expense.CategoryID,
 			CategoryName: smallest_expense.CategoryName,
 			Amount:       smallest_expense.Amount,
+			Currency:     smallest_expense.Currency,
+			BaseAmount:   smallest_expense.BaseAmount,
 			Description:  &smallest_expense.Description,
+			Date:         smallest_expense.Date,
 			CreatedAt:    smallest_expense.CreatedAt,
 		},
-		AverageExpenseAmount: total_amount / float64(expense_count),
+		AverageExpenseAmount: average_expense,
 	}, nil
 }
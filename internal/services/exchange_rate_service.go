@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxExchangeRatesPerImport - максимальное количество курсов в одной загрузке
+const MaxExchangeRatesPerImport = 10000
+
+// exchangeRatesCSVColumns - обязательные колонки CSV с курсами, порядок колонок в файле может быть любым
+var exchangeRatesCSVColumns = []string{"date", "base_currency", "quote_currency", "rate"}
+
+type ExchangeRateService struct {
+	repo        repositories.ExchangeRateRepositoryInterface
+	budget_repo repositories.BudgetRepositoryInterface
+	tx          repositories.TransactorInterface
+}
+
+func NewExchangeRateService(repo repositories.ExchangeRateRepositoryInterface, budget_repo repositories.BudgetRepositoryInterface, tx repositories.TransactorInterface) *ExchangeRateService {
+	return &ExchangeRateService{
+		repo:        repo,
+		budget_repo: budget_repo,
+		tx:          tx,
+	}
+}
+
+// ImportRates сохраняет курсы и пересчитывает по ним суммы расходов и доходов в базовой валюте и потраченные суммы бюджетов.
+// Курс применяется к расходам начиная со своей даты, поэтому пересчет начинается с самой ранней даты загрузки
+func (s *ExchangeRateService) ImportRates(ctx context.Context, req dto.ImportExchangeRatesRequest) (dto.ImportExchangeRatesResponse, error) {
+	if len(req.Rates) == 0 {
+		return dto.ImportExchangeRatesResponse{}, errors.New("rates must not be empty")
+	}
+	if len(req.Rates) > MaxExchangeRatesPerImport {
+		return dto.ImportExchangeRatesResponse{}, fmt.Errorf("too many rates: %d, maximum is %d", len(req.Rates), MaxExchangeRatesPerImport)
+	}
+	rates := make([]models.ExchangeRate, 0, len(req.Rates))
+	var from time.Time
+	for i, input := range req.Rates {
+		rate, err := toExchangeRate(input)
+		if err != nil {
+			return dto.ImportExchangeRatesResponse{}, fmt.Errorf("rate %d: %w", i+1, err)
+		}
+		if from.IsZero() || rate.Date.Before(from) {
+			from = rate.Date
+		}
+		rates = append(rates, rate)
+	}
+
+	var res dto.ImportExchangeRatesResponse
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		res.Imported, err = s.repo.UpsertRates(ctx, rates)
+		if err != nil {
+			return err
+		}
+		res.RecalculatedExpenses, err = s.repo.RecalculateBaseAmounts(ctx, 0, from)
+		if err != nil {
+			return err
+		}
+		res.RecalculatedIncomes, err = s.repo.RecalculateIncomeBaseAmounts(ctx, 0, from)
+		if err != nil {
+			return err
+		}
+		res.RecalculatedBudgets, res.SkippedBudgets, err = s.budget_repo.RecalculateSpentAmounts(ctx, 0)
+		return err
+	})
+	if err != nil {
+		return dto.ImportExchangeRatesResponse{}, err
+	}
+	return res, nil
+}
+
+// ImportRatesCSV загружает курсы из CSV с заголовком date,base_currency,quote_currency,rate
+func (s *ExchangeRateService) ImportRatesCSV(ctx context.Context, r io.Reader) (dto.ImportExchangeRatesResponse, error) {
+	rates, err := parseExchangeRatesCSV(r)
+	if err != nil {
+		return dto.ImportExchangeRatesResponse{}, err
+	}
+	return s.ImportRates(ctx, dto.ImportExchangeRatesRequest{Rates: rates})
+}
+
+func parseExchangeRatesCSV(r io.Reader) ([]dto.ExchangeRateInput, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range exchangeRatesCSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header must contain columns: %s", strings.Join(exchangeRatesCSVColumns, ","))
+		}
+	}
+
+	var rates []dto.ExchangeRateInput
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv line %d: %w", line, err)
+		}
+		if len(rates) == MaxExchangeRatesPerImport {
+			return nil, fmt.Errorf("too many rates, maximum is %d", MaxExchangeRatesPerImport)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[columns["rate"]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", line, record[columns["rate"]])
+		}
+		rates = append(rates, dto.ExchangeRateInput{
+			BaseCurrency:  record[columns["base_currency"]],
+			QuoteCurrency: record[columns["quote_currency"]],
+			Rate:          rate,
+			Date:          strings.TrimSpace(record[columns["date"]]),
+		})
+	}
+	return rates, nil
+}
+
+func toExchangeRate(input dto.ExchangeRateInput) (models.ExchangeRate, error) {
+	base, err := NormalizeCurrency(input.BaseCurrency)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+	quote, err := NormalizeCurrency(input.QuoteCurrency)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+	if base == quote {
+		return models.ExchangeRate{}, errors.New("base_currency and quote_currency must differ")
+	}
+	if input.Rate <= 0 {
+		return models.ExchangeRate{}, errors.New("rate must be greater than zero")
+	}
+	date, err := time.Parse("2006-01-02", input.Date)
+	if err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", input.Date)
+	}
+	return models.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          input.Rate,
+		Date:          date,
+	}, nil
+}
+
+// NormalizeCurrency приводит код валюты к верхнему регистру и проверяет, что это три латинские буквы (ISO 4217)
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q: expected 3 letters, e.g. USD", code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q: expected 3 letters, e.g. USD", code)
+		}
+	}
+	return code, nil
+}
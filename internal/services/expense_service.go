@@ -2,25 +2,65 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"finance/internal/dto"
 	"finance/internal/models"
 	repositories "finance/internal/repositories"
+	"finance/pkg/money"
+	"finance/pkg/period"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultExpensesPageSize - размер страницы списка расходов, если limit не передан
+	DefaultExpensesPageSize = 50
+	// MaxExpensesPageSize - максимальный размер страницы списка расходов
+	MaxExpensesPageSize = 200
+
+	// MaxTagLength - максимальная длина тега в символах
+	MaxTagLength = 50
+	// MaxTagsPerExpense - максимальное количество тегов у одного расхода
+	MaxTagsPerExpense = 20
 )
 
 type ExpenseService struct {
 	repo        repositories.ExpenseRepositoryInterface
 	budget_repo repositories.BudgetRepositoryInterface
+	tag_repo    repositories.TagRepositoryInterface
+	alert_repo  repositories.BudgetAlertRepositoryInterface
+	rate_repo   repositories.ExchangeRateRepositoryInterface
+	tx          repositories.TransactorInterface
 }
 
-func NewExpenseService(repo repositories.ExpenseRepositoryInterface, budget_repo repositories.BudgetRepositoryInterface) *ExpenseService {
+func NewExpenseService(repo repositories.ExpenseRepositoryInterface, budget_repo repositories.BudgetRepositoryInterface, tag_repo repositories.TagRepositoryInterface, alert_repo repositories.BudgetAlertRepositoryInterface, rate_repo repositories.ExchangeRateRepositoryInterface, tx repositories.TransactorInterface) *ExpenseService {
 	return &ExpenseService{
 		repo:        repo,
 		budget_repo: budget_repo,
+		tag_repo:    tag_repo,
+		alert_repo:  alert_repo,
+		rate_repo:   rate_repo,
+		tx:          tx,
 	}
 }
 
 func (s *ExpenseService) CreateExpense(ctx context.Context, userID uint, category_id int, req dto.CreateExpenseRequest) (dto.ExpenseResponse, error) {
+	tags, err := NormalizeTags(req.Tags)
+	if err != nil {
+		return dto.ExpenseResponse{}, err
+	}
+	currency := req.Currency
+	if currency != "" {
+		currency, err = NormalizeCurrency(currency)
+		if err != nil {
+			return dto.ExpenseResponse{}, err
+		}
+	}
 	req_expense := models.Expense{
 		UserID:      userID,
 		CategoryID:  uint(category_id),
@@ -28,8 +68,27 @@ func (s *ExpenseService) CreateExpense(ctx context.Context, userID uint, categor
 		Description: req.Description,
 		Date:        req.Date,
 		CreatedAt:   time.Now(),
+		Currency:    currency,
 	}
-	res_expense, err := s.repo.CreateExpense(ctx, req_expense)
+	var res_expense models.Expense
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		err := s.convertToBase(ctx, &req_expense)
+		if err != nil {
+			return err
+		}
+		res_expense, err = s.repo.CreateExpense(ctx, req_expense)
+		if err != nil {
+			return err
+		}
+		if len(tags) > 0 {
+			err = s.tag_repo.SetExpenseTags(ctx, userID, res_expense.ID, tags)
+			if err != nil {
+				return err
+			}
+			res_expense.Tags = tags
+		}
+		return s.updateBudgetsAfterExpense(ctx, res_expense)
+	})
 	if err != nil {
 		return dto.ExpenseResponse{}, err
 	}
@@ -39,16 +98,19 @@ func (s *ExpenseService) CreateExpense(ctx context.Context, userID uint, categor
 		CategoryID:   res_expense.CategoryID,
 		CategoryName: res_expense.CategoryName,
 		Amount:       res_expense.Amount,
+		Currency:     res_expense.Currency,
+		BaseAmount:   res_expense.BaseAmount,
 		Description:  &res_expense.Description,
 		Date:         res_expense.Date,
-		CreatedAt:    req_expense.CreatedAt,
+		CreatedAt:    res_expense.CreatedAt,
+		Tags:         res_expense.Tags,
 	}, nil
 }
 
 func (s *ExpenseService) GetUserExpense(ctx context.Context, userID uint, category_id int, expenseID int) (dto.ExpenseResponse, error) {
 	res_expense, err := s.repo.GetExpenseByID(ctx, userID, category_id, uint(expenseID))
 	if err != nil {
-		return dto.ExpenseResponse{}, nil
+		return dto.ExpenseResponse{}, err
 	}
 
 	return dto.ExpenseResponse{
@@ -56,17 +118,39 @@ func (s *ExpenseService) GetUserExpense(ctx context.Context, userID uint, catego
 		CategoryID:   res_expense.CategoryID,
 		CategoryName: res_expense.CategoryName,
 		Amount:       res_expense.Amount,
+		Currency:     res_expense.Currency,
+		BaseAmount:   res_expense.BaseAmount,
 		Description:  &res_expense.Description,
 		Date:         res_expense.Date,
 		CreatedAt:    res_expense.CreatedAt,
+		Tags:         res_expense.Tags,
 	}, nil
 }
 
-func (s *ExpenseService) GetUserExpenses(ctx context.Context, category_id int, userID uint) ([]dto.ExpenseResponse, error) {
-	req_expenses, err := s.repo.GetExpensesByUserID(ctx, category_id, userID)
+// GetUserExpenses возвращает страницу расходов пользователя в категории (0 - во всех категориях)
+// с учетом фильтров и сортировки из query. Если расходов больше, чем limit, в ответе есть next_cursor
+func (s *ExpenseService) GetUserExpenses(ctx context.Context, category_id int, userID uint, query dto.ExpenseListQuery) (dto.ExpensesListResponse, error) {
+	filter, err := buildExpenseFilter(category_id, userID, query)
+	if err != nil {
+		return dto.ExpensesListResponse{}, err
+	}
+	// Запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	page_size := filter.Limit
+	filter.Limit++
+	req_expenses, err := s.repo.GetExpensesByUserID(ctx, filter)
 	if err != nil {
-		return []dto.ExpenseResponse{}, err
+		return dto.ExpensesListResponse{}, err
+	}
+
+	var next_cursor string
+	if len(req_expenses) > page_size {
+		req_expenses = req_expenses[:page_size]
+		next_cursor, err = encodeExpenseCursor(filter, req_expenses[len(req_expenses)-1])
+		if err != nil {
+			return dto.ExpensesListResponse{}, err
+		}
 	}
+
 	res_expenses := make([]dto.ExpenseResponse, 0, len(req_expenses))
 	for _, expense := range req_expenses {
 		res_expenses = append(res_expenses, dto.ExpenseResponse{
@@ -74,78 +158,173 @@ func (s *ExpenseService) GetUserExpenses(ctx context.Context, category_id int, u
 			CategoryID:   expense.CategoryID,
 			CategoryName: expense.CategoryName,
 			Amount:       expense.Amount,
+			Currency:     expense.Currency,
+			BaseAmount:   expense.BaseAmount,
 			Description:  &expense.Description,
 			Date:         expense.Date,
 			CreatedAt:    expense.CreatedAt,
+			Tags:         expense.Tags,
 		})
 	}
-	return res_expenses, nil
+	return dto.ExpensesListResponse{
+		Expenses:   res_expenses,
+		NextCursor: next_cursor,
+	}, nil
 }
 
-func (s *ExpenseService) DeleteExpense(ctx context.Context, userID uint, category_id int, expenseID int) error {
-	// Получаем информацию о расходе перед удалением для возврата бюджета
-	expense, err := s.repo.GetExpenseByID(ctx, userID, category_id, uint(expenseID))
-	if err != nil {
+func (s *ExpenseService) UpdateExpense(ctx context.Context, userID uint, category_id int, expenseID int, req dto.UpdateExpenseRequest) (dto.ExpenseResponse, error) {
+	var res_expense models.Expense
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		res_expense, err = s.updateExpense(ctx, userID, category_id, expenseID, req)
 		return err
+	})
+	if err != nil {
+		return dto.ExpenseResponse{}, err
+	}
+
+	return dto.ExpenseResponse{
+		ID:           res_expense.ID,
+		CategoryID:   res_expense.CategoryID,
+		CategoryName: res_expense.CategoryName,
+		Amount:       res_expense.Amount,
+		Currency:     res_expense.Currency,
+		BaseAmount:   res_expense.BaseAmount,
+		Description:  &res_expense.Description,
+		Date:         res_expense.Date,
+		CreatedAt:    res_expense.CreatedAt,
+		Tags:         res_expense.Tags,
+	}, nil
+}
+
+// updateExpense применяет изменения к расходу и переносит его сумму между бюджетами. Вызывается внутри транзакции
+func (s *ExpenseService) updateExpense(ctx context.Context, userID uint, category_id int, expenseID int, req dto.UpdateExpenseRequest) (models.Expense, error) {
+	old_expense, err := s.repo.GetExpenseByID(ctx, userID, category_id, uint(expenseID))
+	if err != nil {
+		return models.Expense{}, err
 	}
 
-	err = s.repo.DeleteExpense(ctx, userID, category_id, uint(expenseID))
+	// Применяем только переданные поля, остальные остаются без изменений
+	new_expense := old_expense
+	if req.CategoryID != nil {
+		new_expense.CategoryID = *req.CategoryID
+	}
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			return models.Expense{}, errors.New("amount must be greater than zero")
+		}
+		new_expense.Amount = *req.Amount
+	}
+	if req.Description != nil {
+		new_expense.Description = *req.Description
+	}
+	if req.Date != nil {
+		new_expense.Date = *req.Date
+	}
+	if req.Currency != nil {
+		new_expense.Currency, err = NormalizeCurrency(*req.Currency)
+		if err != nil {
+			return models.Expense{}, err
+		}
+	}
+	// Сумма в базовой валюте пересчитывается по курсу на (возможно новую) дату расхода
+	err = s.convertToBase(ctx, &new_expense)
 	if err != nil {
-		return err
+		return models.Expense{}, err
 	}
 
-	// Возвращаем средства в бюджеты после удаления расхода
-	err = s.restoreBudgetsAfterExpenseDeletion(ctx, userID, int(expense.CategoryID), expense.Amount, expense.Date)
+	res_expense, err := s.repo.UpdateExpense(ctx, new_expense)
 	if err != nil {
-		// Логируем ошибку, но не прерываем процесс удаления расхода
+		return models.Expense{}, err
 	}
 
-	return nil
+	// Теги заменяются целиком, только если переданы в запросе
+	res_expense.Tags = old_expense.Tags
+	if req.Tags != nil {
+		tags, err := NormalizeTags(*req.Tags)
+		if err != nil {
+			return models.Expense{}, err
+		}
+		err = s.tag_repo.SetExpenseTags(ctx, userID, res_expense.ID, tags)
+		if err != nil {
+			return models.Expense{}, err
+		}
+		res_expense.Tags = tags
+	}
+
+	// Списываем старую сумму с бюджетов старой категории и даты, затем добавляем новую
+	err = s.restoreBudgetsAfterExpenseDeletion(ctx, old_expense)
+	if err != nil {
+		return models.Expense{}, err
+	}
+	err = s.updateBudgetsAfterExpense(ctx, res_expense)
+	if err != nil {
+		return models.Expense{}, err
+	}
+	return res_expense, nil
+}
+
+func (s *ExpenseService) DeleteExpense(ctx context.Context, userID uint, category_id int, expenseID int) error {
+	return s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		// Получаем информацию о расходе перед удалением для возврата бюджета
+		expense, err := s.repo.GetExpenseByID(ctx, userID, category_id, uint(expenseID))
+		if err != nil {
+			return err
+		}
+
+		err = s.repo.DeleteExpense(ctx, userID, category_id, uint(expenseID))
+		if err != nil {
+			return err
+		}
 
+		// Возвращаем средства в бюджеты после удаления расхода
+		return s.restoreBudgetsAfterExpenseDeletion(ctx, expense)
+	})
 }
 
-func (s *ExpenseService) GetExpenseAnalytics(ctx context.Context, userID uint, category_id int, period dto.ExpensePeriod) (dto.ExpenseAnalytics, error) {
-	req, err := s.repo.GetExpensesByPeriod(ctx, userID, category_id, period.Period)
+func (s *ExpenseService) GetExpenseAnalytics(ctx context.Context, userID uint, category_id int, filter dto.ExpensePeriod) (dto.ExpenseAnalytics, error) {
+	now := time.Now()
+	date_range, err := period.Resolve(filter.Period, filter.From, filter.To, now)
+	if err != nil {
+		return dto.ExpenseAnalytics{}, err
+	}
+	req, err := s.repo.GetExpensesByPeriod(ctx, userID, category_id, date_range.Start, date_range.End)
 	if err != nil {
 		return dto.ExpenseAnalytics{}, err
 	}
-	var total_amount float64
+	var total_amount money.Amount
 	total_count := len(req)
 	for _, value := range req {
-		total_amount += value.Amount
+		total_amount = total_amount.Add(value.BaseAmount)
 	}
-	total_average_expense := total_amount / float64(total_count)
+	total_average_expense := total_amount.Div(total_count)
 
-	largest_expense, err := s.repo.GetLargestExpenseByPeriod(ctx, userID, category_id, period.Period)
+	largest_expense, err := s.repo.GetLargestExpenseByPeriod(ctx, userID, category_id, date_range.Start, date_range.End)
 	if err != nil {
 		return dto.ExpenseAnalytics{}, err
 	}
-	smallest_expense, err := s.repo.GetSmallestExpenseByPeriod(ctx, userID, category_id, period.Period)
+	smallest_expense, err := s.repo.GetSmallestExpenseByPeriod(ctx, userID, category_id, date_range.Start, date_range.End)
 	if err != nil {
 		return dto.ExpenseAnalytics{}, err
 	}
-	var timedist float64
-	if period.Period == "weekly" {
-		timedist = 7
-	} else if period.Period == "monthly" {
-		timedist = 30
-	} else if period.Period == "yearly" {
-		timedist = 365
-	} else {
-		timedist = 0
-	}
-	averagePerDay := total_amount / timedist
+	// среднее за день считается по дням периода, которые уже прошли
+	days := date_range.ElapsedDays(now)
 
 	return dto.ExpenseAnalytics{
-		Period:        period.Period,
+		Period:        date_range.Label,
+		From:          date_range.Start,
+		To:            date_range.End.AddDate(0, 0, -1),
+		Days:          days,
 		TotalAmount:   total_amount,
 		ExpensesCount: total_count,
-		AveragePerDay: averagePerDay,
+		AveragePerDay: averagePerDay(total_amount, days),
 		LargestExpense: dto.ExpenseResponse{
 			ID:           largest_expense.ID,
 			CategoryID:   largest_expense.CategoryID,
 			CategoryName: largest_expense.CategoryName,
 			Amount:       largest_expense.Amount,
+			Currency:     largest_expense.Currency,
+			BaseAmount:   largest_expense.BaseAmount,
 			Description:  &largest_expense.Description,
 			Date:         largest_expense.Date,
 			CreatedAt:    largest_expense.CreatedAt,
@@ -155,6 +334,8 @@ func (s *ExpenseService) GetExpenseAnalytics(ctx context.Context, userID uint, c
 			CategoryID:   smallest_expense.CategoryID,
 			CategoryName: smallest_expense.CategoryName,
 			Amount:       smallest_expense.Amount,
+			Currency:     smallest_expense.Currency,
+			BaseAmount:   smallest_expense.BaseAmount,
 			Description:  &smallest_expense.Description,
 			Date:         smallest_expense.Date,
 			CreatedAt:    smallest_expense.CreatedAt,
@@ -163,44 +344,177 @@ func (s *ExpenseService) GetExpenseAnalytics(ctx context.Context, userID uint, c
 	}, nil
 }
 
-// updateBudgetsAfterExpense обновляет все подходящие бюджеты после добавления расхода
-func (s *ExpenseService) updateBudgetsAfterExpense(ctx context.Context, userID uint, categoryID int, amount float64, expenseDate time.Time) error {
-	// Получаем все активные бюджеты пользователя для данной категории
-	budgets, err := s.budget_repo.GetActiveBudgetsByCategoryAndDate(ctx, userID, categoryID, expenseDate)
+// averagePerDay делит сумму на число дней, для пустого периода возвращает 0
+func averagePerDay(total money.Amount, days int) money.Amount {
+	return total.Div(days)
+}
+
+// convertToBase заполняет сумму расхода в базовой валюте пользователя по курсу на дату расхода.
+// Если валюта расхода не задана, расход вносится в базовой валюте
+func (s *ExpenseService) convertToBase(ctx context.Context, expense *models.Expense) error {
+	base, rate, err := s.rate_repo.GetRateToBase(ctx, expense.UserID, expense.Currency, expense.Date)
 	if err != nil {
 		return err
 	}
-
-	// Обновляем каждый подходящий бюджет
-	for _, budget := range budgets {
-		err = s.budget_repo.UpdateSpentAmount(ctx, categoryID, budget.ID, budget.SpentAmount+amount)
-		if err != nil {
-			return err
-		}
+	if expense.Currency == "" {
+		expense.Currency = base
 	}
-
+	expense.BaseAmount = expense.Amount.Mul(rate)
 	return nil
 }
 
-// restoreBudgetsAfterExpenseDeletion возвращает средства в бюджеты после удаления расхода
-func (s *ExpenseService) restoreBudgetsAfterExpenseDeletion(ctx context.Context, userID uint, categoryID int, amount float64, expenseDate time.Time) error {
-	// Получаем все активные бюджеты пользователя для данной категории
-	budgets, err := s.budget_repo.GetActiveBudgetsByCategoryAndDate(ctx, userID, categoryID, expenseDate)
+// updateBudgetsAfterExpense добавляет сумму расхода во все бюджеты категории, активные на дату расхода,
+// и создает уведомления о достигнутых порогах
+func (s *ExpenseService) updateBudgetsAfterExpense(ctx context.Context, expense models.Expense) error {
+	err := s.budget_repo.AdjustSpentAmount(ctx, expense.UserID, int(expense.CategoryID), expense.Date, expense.Currency, expense.Amount, expense.BaseAmount)
 	if err != nil {
 		return err
 	}
+	_, err = s.alert_repo.CreateThresholdAlerts(ctx, expense.UserID, int(expense.CategoryID), expense.Date)
+	return err
+}
+
+// restoreBudgetsAfterExpenseDeletion возвращает сумму расхода в бюджеты категории, активные на дату расхода
+func (s *ExpenseService) restoreBudgetsAfterExpenseDeletion(ctx context.Context, expense models.Expense) error {
+	return s.budget_repo.AdjustSpentAmount(ctx, expense.UserID, int(expense.CategoryID), expense.Date, expense.Currency, expense.Amount.Neg(), expense.BaseAmount.Neg())
+}
 
-	// Возвращаем средства в каждый подходящий бюджет
-	for _, budget := range budgets {
-		newSpentAmount := budget.SpentAmount - amount
-		if newSpentAmount < 0 {
-			newSpentAmount = 0
+// NormalizeTags приводит теги к нижнему регистру, убирает пробелы по краям, пустые значения и дубликаты
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
 		}
-		err = s.budget_repo.UpdateSpentAmount(ctx, categoryID, budget.ID, newSpentAmount)
-		if err != nil {
-			return err
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", tag, MaxTagLength)
 		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxTagsPerExpense {
+		return nil, fmt.Errorf("too many tags: maximum is %d", MaxTagsPerExpense)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// expenseCursor - позиция последнего расхода страницы. Вместе с ней сохраняются параметры сортировки,
+// чтобы курсор нельзя было применить к выборке с другим порядком
+type expenseCursor struct {
+	Sort  string       `json:"s"`
+	Desc  bool         `json:"d"`
+	ID    uint         `json:"id"`
+	Time  time.Time    `json:"t,omitempty"`
+	Value money.Amount `json:"v,omitempty"`
+}
+
+func encodeExpenseCursor(filter models.ExpenseFilter, last models.Expense) (string, error) {
+	cursor := expenseCursor{
+		Sort: filter.SortBy,
+		Desc: filter.Desc,
+		ID:   last.ID,
+	}
+	switch filter.SortBy {
+	case "amount":
+		cursor.Value = last.BaseAmount
+	case "created_at":
+		cursor.Time = last.CreatedAt
+	default:
+		cursor.Time = last.Date
+	}
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
 	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
 
+func decodeExpenseCursor(value string, filter *models.ExpenseFilter) error {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return errors.New("invalid cursor")
+	}
+	var cursor expenseCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return errors.New("invalid cursor")
+	}
+	if cursor.Sort != filter.SortBy || cursor.Desc != filter.Desc {
+		return errors.New("cursor does not match sort and order parameters")
+	}
+	if cursor.Sort == "amount" {
+		filter.AfterValue = cursor.Value
+	} else {
+		filter.AfterValue = cursor.Time
+	}
+	filter.AfterID = cursor.ID
 	return nil
 }
+
+// buildExpenseFilter проверяет параметры списка расходов и переводит их в условия выборки
+func buildExpenseFilter(category_id int, userID uint, query dto.ExpenseListQuery) (models.ExpenseFilter, error) {
+	filter := models.ExpenseFilter{
+		UserID:     userID,
+		CategoryID: category_id,
+		MinAmount:  query.MinAmount,
+		MaxAmount:  query.MaxAmount,
+		Search:     strings.TrimSpace(query.Search),
+		SortBy:     query.Sort,
+		Desc:       true,
+		Limit:      query.Limit,
+	}
+
+	if !query.From.IsZero() {
+		filter.From = &query.From
+	}
+	if !query.To.IsZero() {
+		// to задается датой и включается в период целиком
+		to_exclusive := query.To.AddDate(0, 0, 1)
+		filter.To = &to_exclusive
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return models.ExpenseFilter{}, errors.New("from must not be after to")
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return models.ExpenseFilter{}, errors.New("min_amount must not be greater than max_amount")
+	}
+
+	if query.Tags != "" {
+		tags, err := NormalizeTags(strings.Split(query.Tags, ","))
+		if err != nil {
+			return models.ExpenseFilter{}, err
+		}
+		filter.Tags = tags
+	}
+
+	switch filter.SortBy {
+	case "":
+		filter.SortBy = "date"
+	case "date", "amount", "created_at":
+	default:
+		return models.ExpenseFilter{}, fmt.Errorf("unsupported sort field: %s. Available values: date, amount, created_at", filter.SortBy)
+	}
+	switch strings.ToLower(query.Order) {
+	case "", "desc":
+	case "asc":
+		filter.Desc = false
+	default:
+		return models.ExpenseFilter{}, fmt.Errorf("unsupported order: %s. Available values: asc, desc", query.Order)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultExpensesPageSize
+	}
+	if filter.Limit > MaxExpensesPageSize {
+		filter.Limit = MaxExpensesPageSize
+	}
+
+	if query.Cursor != "" {
+		if err := decodeExpenseCursor(query.Cursor, &filter); err != nil {
+			return models.ExpenseFilter{}, err
+		}
+	}
+	return filter, nil
+}
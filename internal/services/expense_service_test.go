@@ -0,0 +1,91 @@
+package services
+
+import (
+	"encoding/base64"
+	"finance/internal/models"
+	"finance/pkg/money"
+	"testing"
+	"time"
+)
+
+func TestExpenseCursorRoundTrip(t *testing.T) {
+	last := models.Expense{
+		ID:         42,
+		BaseAmount: money.FromMinor(-123456),
+		Date:       time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC),
+		CreatedAt:  time.Date(2026, 3, 11, 14, 5, 7, 123456789, time.FixedZone("MSK", 3*60*60)),
+	}
+	tests := []struct {
+		name   string
+		sortBy string
+		desc   bool
+		want   any
+	}{
+		{"date desc", "date", true, last.Date},
+		{"date asc", "date", false, last.Date},
+		{"default sort", "", true, last.Date},
+		{"created_at keeps nanoseconds", "created_at", true, last.CreatedAt},
+		{"amount", "amount", false, last.BaseAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := encodeExpenseCursor(models.ExpenseFilter{SortBy: tt.sortBy, Desc: tt.desc}, last)
+			if err != nil {
+				t.Fatalf("encode error: %v", err)
+			}
+			filter := models.ExpenseFilter{SortBy: tt.sortBy, Desc: tt.desc}
+			if err := decodeExpenseCursor(cursor, &filter); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if filter.AfterID != last.ID {
+				t.Errorf("AfterID = %d, want %d", filter.AfterID, last.ID)
+			}
+			switch want := tt.want.(type) {
+			case time.Time:
+				got, ok := filter.AfterValue.(time.Time)
+				if !ok || !got.Equal(want) {
+					t.Errorf("AfterValue = %v, want %v", filter.AfterValue, want)
+				}
+			case money.Amount:
+				got, ok := filter.AfterValue.(money.Amount)
+				if !ok || got != want {
+					t.Errorf("AfterValue = %v, want %v", filter.AfterValue, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeExpenseCursorInvalid(t *testing.T) {
+	last := models.Expense{ID: 7, Date: time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)}
+	byDateDesc, err := encodeExpenseCursor(models.ExpenseFilter{SortBy: "date", Desc: true}, last)
+	if err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		sortBy string
+		desc   bool
+	}{
+		{"not base64", "!!!", "date", true},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"date","d":true,"id":1}`)), "date", true},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("cursor")), "date", true},
+		{"wrong field type", base64.RawURLEncoding.EncodeToString([]byte(`{"s":"date","d":true,"id":"x"}`)), "date", true},
+		// Курсор одной сортировки нельзя применить к выборке с другим порядком
+		{"other order", byDateDesc, "date", false},
+		{"other sort field", byDateDesc, "amount", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := models.ExpenseFilter{SortBy: tt.sortBy, Desc: tt.desc}
+			if err := decodeExpenseCursor(tt.cursor, &filter); err == nil {
+				t.Error("expected error")
+			}
+			if filter.AfterValue != nil || filter.AfterID != 0 {
+				t.Errorf("filter modified on error: %v, %d", filter.AfterValue, filter.AfterID)
+			}
+		})
+	}
+}
@@ -0,0 +1,475 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"finance/pkg/money"
+	"finance/pkg/xlsx"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exportMemoryBuffer - сколько байт выгрузки держится в памяти, остальное пишется во временный файл
+const exportMemoryBuffer = 4 << 20
+
+// Форматы выгрузки
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+	ExportFormatXLSX = "xlsx"
+)
+
+// Разделы выгрузки: ключи JSON, заголовки разделов CSV и названия листов XLSX
+const (
+	exportSectionCategories = "categories"
+	exportSectionExpenses   = "expenses"
+	exportSectionBudgets    = "budgets"
+)
+
+var exportContentTypes = map[string]string{
+	ExportFormatCSV:  "text/csv; charset=utf-8",
+	ExportFormatJSON: "application/json; charset=utf-8",
+	ExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+var (
+	exportCategoryColumns = []string{"id", "name", "created_at"}
+	exportExpenseColumns  = []string{"id", "date", "category_id", "category_name", "amount", "currency", "base_amount", "description", "tags", "created_at"}
+	exportBudgetColumns   = []string{"id", "scope", "name", "category_id", "category_name", "category_ids", "period", "start_date", "end_date",
+		"amount", "spent_amount", "carried_amount", "currency", "auto_renew", "carry_over", "alert_thresholds"}
+)
+
+// ExportFile - подготовленная выгрузка. Данные читаются из базы только при вызове Write,
+// поэтому заголовки ответа можно выставить до начала записи
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	write       func(w io.Writer) error
+}
+
+// Write читает выгрузку из базы в буфер и затем записывает ее в w. Ошибка при чтении из базы возвращается
+// до записи первого байта, ошибка в середине записи оставляет файл неполным
+func (f ExportFile) Write(w io.Writer) error {
+	return f.write(w)
+}
+
+type ExportService struct {
+	repo      repositories.ExportRepositoryInterface
+	user_repo repositories.UserRepositoryInterface
+	tx        repositories.TransactorInterface
+}
+
+func NewExportService(repo repositories.ExportRepositoryInterface, user_repo repositories.UserRepositoryInterface, tx repositories.TransactorInterface) *ExportService {
+	return &ExportService{
+		repo:      repo,
+		user_repo: user_repo,
+		tx:        tx,
+	}
+}
+
+// Export проверяет параметры и готовит выгрузку категорий, расходов и бюджетов пользователя.
+// Все разделы читаются из одного снимка базы в буфер, а в ответ пишутся уже после завершения транзакции,
+// чтобы медленный клиент не держал ее открытой
+func (e *ExportService) Export(ctx context.Context, userID uint, req dto.ExportRequest) (ExportFile, error) {
+	format := strings.ToLower(strings.TrimSpace(req.Format))
+	if format == "" {
+		format = ExportFormatJSON
+	}
+	content_type, ok := exportContentTypes[format]
+	if !ok {
+		return ExportFile{}, fmt.Errorf("invalid export format: %s, must be csv, json or xlsx", req.Format)
+	}
+
+	var from, to *time.Time
+	if !req.From.IsZero() {
+		from = &req.From
+	}
+	if !req.To.IsZero() {
+		// to задается датой и включается в период целиком
+		to_exclusive := req.To.AddDate(0, 0, 1)
+		to = &to_exclusive
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return ExportFile{}, errors.New("from must not be after to")
+	}
+
+	meta := dto.ExportResponse{
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	if from != nil {
+		meta.From = req.From.Format("2006-01-02")
+	}
+	if to != nil {
+		meta.To = req.To.Format("2006-01-02")
+	}
+
+	return ExportFile{
+		FileName:    exportFileName(meta, format),
+		ContentType: content_type,
+		write: func(w io.Writer) error {
+			var spool exportSpool
+			defer spool.Close()
+			err := e.tx.WithinSnapshot(ctx, func(ctx context.Context) error {
+				user, err := e.user_repo.GetProfile(ctx, userID)
+				if err != nil {
+					return err
+				}
+				meta.BaseCurrency = user.BaseCurrency
+				return e.writeExport(ctx, newExportWriter(format, &spool), userID, from, to, meta)
+			})
+			if err != nil {
+				return err
+			}
+			_, err = spool.WriteTo(w)
+			return err
+		},
+	}, nil
+}
+
+// exportSpool накапливает выгрузку: первые exportMemoryBuffer байт в памяти, остальное во временном файле
+type exportSpool struct {
+	buf  bytes.Buffer
+	file *os.File
+}
+
+func (s *exportSpool) Write(p []byte) (int, error) {
+	if s.file == nil && s.buf.Len()+len(p) <= exportMemoryBuffer {
+		return s.buf.Write(p)
+	}
+	if s.file == nil {
+		file, err := os.CreateTemp("", "export-*")
+		if err != nil {
+			return 0, fmt.Errorf("failed to create export buffer file: %w", err)
+		}
+		s.file = file
+	}
+	return s.file.Write(p)
+}
+
+// WriteTo записывает в w сначала часть из памяти, затем часть из временного файла
+func (s *exportSpool) WriteTo(w io.Writer) (int64, error) {
+	n, err := s.buf.WriteTo(w)
+	if err != nil || s.file == nil {
+		return n, err
+	}
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return n, err
+	}
+	written, err := io.Copy(w, s.file)
+	return n + written, err
+}
+
+// Close удаляет временный файл, если он создавался
+func (s *exportSpool) Close() error {
+	if s.file == nil {
+		return nil
+	}
+	s.file.Close()
+	return os.Remove(s.file.Name())
+}
+
+func (e *ExportService) writeExport(ctx context.Context, out exportWriter, userID uint, from, to *time.Time, meta dto.ExportResponse) error {
+	if err := out.Begin(meta); err != nil {
+		return err
+	}
+
+	if err := out.Section(exportSectionCategories, exportCategoryColumns); err != nil {
+		return err
+	}
+	err := e.repo.StreamCategories(ctx, userID, func(category models.Category) error {
+		record := dto.ExportCategory{
+			ID:        category.ID,
+			Name:      category.Name,
+			CreatedAt: category.CreatedAt.UTC(),
+		}
+		return out.Record(record, []any{record.ID, record.Name, record.CreatedAt})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := out.Section(exportSectionExpenses, exportExpenseColumns); err != nil {
+		return err
+	}
+	err = e.repo.StreamExpenses(ctx, userID, from, to, func(expense models.Expense) error {
+		record := dto.ExportExpense{
+			ID:           expense.ID,
+			Date:         expense.Date.UTC(),
+			CategoryID:   expense.CategoryID,
+			CategoryName: expense.CategoryName,
+			Amount:       expense.Amount,
+			Currency:     expense.Currency,
+			BaseAmount:   expense.BaseAmount,
+			Description:  expense.Description,
+			Tags:         expense.Tags,
+			CreatedAt:    expense.CreatedAt.UTC(),
+		}
+		if record.Tags == nil {
+			record.Tags = []string{}
+		}
+		return out.Record(record, []any{
+			record.ID, record.Date, record.CategoryID, record.CategoryName, record.Amount, record.Currency,
+			record.BaseAmount, record.Description, record.Tags, record.CreatedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := out.Section(exportSectionBudgets, exportBudgetColumns); err != nil {
+		return err
+	}
+	err = e.repo.StreamBudgets(ctx, userID, from, to, func(budget models.Budget) error {
+		record := dto.ExportBudget{
+			ID:              budget.ID,
+			Scope:           budget.Scope,
+			Name:            budget.Name,
+			CategoryID:      budget.CategoryID,
+			CategoryName:    budget.CategoryName,
+			CategoryIDs:     budget.CategoryIDs,
+			Period:          budget.Period,
+			StartDate:       budget.StartDate.UTC(),
+			EndDate:         inclusiveEndDate(budget.EndDate).UTC(),
+			Amount:          budget.Amount,
+			SpentAmount:     budget.SpentAmount,
+			CarriedAmount:   budget.CarriedAmount,
+			Currency:        budget.Currency,
+			AutoRenew:       budget.AutoRenew,
+			CarryOver:       budget.CarryOver,
+			AlertThresholds: budget.AlertThresholds,
+		}
+		if record.CategoryIDs == nil {
+			record.CategoryIDs = []uint{}
+		}
+		if record.AlertThresholds == nil {
+			record.AlertThresholds = []int{}
+		}
+		var category_id any
+		if record.CategoryID != 0 {
+			category_id = record.CategoryID
+		}
+		return out.Record(record, []any{
+			record.ID, record.Scope, record.Name, category_id, record.CategoryName, record.CategoryIDs, record.Period,
+			record.StartDate, record.EndDate, record.Amount, record.SpentAmount, record.CarriedAmount, record.Currency,
+			record.AutoRenew, record.CarryOver, record.AlertThresholds,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return out.Close()
+}
+
+// exportFileName возвращает имя файла выгрузки, например finance-export-2024-01-31.xlsx
+func exportFileName(meta dto.ExportResponse, format string) string {
+	return "finance-export-" + meta.ExportedAt.Format("2006-01-02") + "." + format
+}
+
+// exportWriter записывает разделы выгрузки в одном из форматов. Record получает запись целиком для JSON
+// и ее значения в порядке колонок раздела для табличных форматов
+type exportWriter interface {
+	Begin(meta dto.ExportResponse) error
+	Section(name string, columns []string) error
+	Record(value any, cells []any) error
+	Close() error
+}
+
+func newExportWriter(format string, w io.Writer) exportWriter {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportWriter{w: csv.NewWriter(w)}
+	case ExportFormatXLSX:
+		return &xlsxExportWriter{w: xlsx.NewWriter(w)}
+	default:
+		return &jsonExportWriter{w: bufio.NewWriterSize(w, 32<<10)}
+	}
+}
+
+// jsonExportWriter пишет объект dto.ExportResponse по частям: поля выгрузки, затем массивы разделов
+type jsonExportWriter struct {
+	w       *bufio.Writer
+	section bool
+	first   bool
+}
+
+func (j *jsonExportWriter) Begin(meta dto.ExportResponse) error {
+	j.w.WriteString("{")
+	fields := []struct {
+		name  string
+		value any
+		skip  bool
+	}{
+		{"exported_at", meta.ExportedAt, false},
+		{"from", meta.From, meta.From == ""},
+		{"to", meta.To, meta.To == ""},
+		{"base_currency", meta.BaseCurrency, false},
+	}
+	separator := ""
+	for _, field := range fields {
+		if field.skip {
+			continue
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(j.w, "%s%q:%s", separator, field.name, value)
+		separator = ","
+	}
+	return nil
+}
+
+func (j *jsonExportWriter) Section(name string, columns []string) error {
+	if j.section {
+		j.w.WriteString("]")
+	}
+	j.section = true
+	j.first = true
+	_, err := fmt.Fprintf(j.w, ",%q:[", name)
+	return err
+}
+
+func (j *jsonExportWriter) Record(value any, cells []any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if !j.first {
+		j.w.WriteString(",")
+	}
+	j.first = false
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonExportWriter) Close() error {
+	if j.section {
+		j.w.WriteString("]")
+	}
+	j.w.WriteString("}\n")
+	return j.w.Flush()
+}
+
+// csvExportWriter пишет разделы друг за другом: строка с названием раздела, заголовок колонок, записи
+// и пустая строка перед следующим разделом
+type csvExportWriter struct {
+	w       *csv.Writer
+	section bool
+}
+
+func (c *csvExportWriter) Begin(meta dto.ExportResponse) error {
+	return nil
+}
+
+func (c *csvExportWriter) Section(name string, columns []string) error {
+	if c.section {
+		c.w.Write([]string{})
+	}
+	c.section = true
+	c.w.Write([]string{name})
+	return c.w.Write(columns)
+}
+
+func (c *csvExportWriter) Record(value any, cells []any) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = formatExportCell(cell)
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	// Ошибка записи в ответ (например, клиент закрыл соединение) прекращает чтение из базы
+	return c.w.Error()
+}
+
+func (c *csvExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// xlsxExportWriter пишет каждый раздел на отдельный лист, суммы - числами, даты - датами
+type xlsxExportWriter struct {
+	w *xlsx.Writer
+}
+
+func (x *xlsxExportWriter) Begin(meta dto.ExportResponse) error {
+	return nil
+}
+
+func (x *xlsxExportWriter) Section(name string, columns []string) error {
+	if err := x.w.AddSheet(name); err != nil {
+		return err
+	}
+	return x.w.WriteHeader(columns...)
+}
+
+func (x *xlsxExportWriter) Record(value any, cells []any) error {
+	row := make([]any, len(cells))
+	for i, cell := range cells {
+		switch v := cell.(type) {
+		case money.Amount:
+			row[i] = xlsx.Number(v.String())
+		case []string, []uint, []int:
+			row[i] = formatExportCell(v)
+		default:
+			row[i] = v
+		}
+	}
+	return x.w.WriteRow(row...)
+}
+
+func (x *xlsxExportWriter) Close() error {
+	return x.w.Close()
+}
+
+// formatExportCell переводит значение колонки в текст CSV. Время без часов и минут записывается датой
+func formatExportCell(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case money.Amount:
+		return v.String()
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	case []string:
+		return strings.Join(v, ",")
+	case []uint:
+		values := make([]string, len(v))
+		for i, id := range v {
+			values[i] = strconv.FormatUint(uint64(id), 10)
+		}
+		return strings.Join(values, ",")
+	case []int:
+		values := make([]string, len(v))
+		for i, n := range v {
+			values[i] = strconv.Itoa(n)
+		}
+		return strings.Join(values, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestExportSpool(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    []int
+		wantFile bool
+	}{
+		{"empty", nil, false},
+		{"fits in memory", []int{100, exportMemoryBuffer - 100}, false},
+		// Все, что не помещается в память, включая текущую запись, уходит во временный файл
+		{"spills to file", []int{exportMemoryBuffer - 10, 20, 30}, true},
+		{"single large write", []int{exportMemoryBuffer + 1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var spool exportSpool
+			var want bytes.Buffer
+			for i, size := range tt.sizes {
+				chunk := bytes.Repeat([]byte{byte('a' + i)}, size)
+				n, err := spool.Write(chunk)
+				if err != nil || n != size {
+					t.Fatalf("Write = %d, %v, want %d", n, err, size)
+				}
+				want.Write(chunk)
+			}
+			if (spool.file != nil) != tt.wantFile {
+				t.Errorf("temporary file created = %v, want %v", spool.file != nil, tt.wantFile)
+			}
+
+			var got bytes.Buffer
+			n, err := spool.WriteTo(&got)
+			if err != nil {
+				t.Fatalf("WriteTo error: %v", err)
+			}
+			if n != int64(want.Len()) || !bytes.Equal(got.Bytes(), want.Bytes()) {
+				t.Errorf("WriteTo wrote %d bytes, want %d bytes in the same order", n, want.Len())
+			}
+
+			name := ""
+			if spool.file != nil {
+				name = spool.file.Name()
+			}
+			if err := spool.Close(); err != nil {
+				t.Fatalf("Close error: %v", err)
+			}
+			if name != "" {
+				if _, err := os.Stat(name); !os.IsNotExist(err) {
+					t.Errorf("temporary file %s was not removed", name)
+				}
+			}
+		})
+	}
+}
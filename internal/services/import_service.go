@@ -0,0 +1,419 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"finance/pkg/statement"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ImportStatusPreview   = "preview"
+	ImportStatusCommitted = "committed"
+	ImportStatusUndone    = "undone"
+
+	// MaxImportFileSize - максимальный размер файла выписки в байтах
+	MaxImportFileSize = 5 << 20
+	// MaxImportRows - максимальное количество операций в одной выписке
+	MaxImportRows = 5000
+)
+
+type ImportService struct {
+	repo            repositories.ImportRepositoryInterface
+	rule_repo       repositories.RuleRepositoryInterface
+	category_repo   repositories.CategoryRepositoryInterface
+	expense_service ExpenseServiceInterface
+	tx              repositories.TransactorInterface
+}
+
+func NewImportService(repo repositories.ImportRepositoryInterface, rule_repo repositories.RuleRepositoryInterface, category_repo repositories.CategoryRepositoryInterface, expense_service ExpenseServiceInterface, tx repositories.TransactorInterface) *ImportService {
+	return &ImportService{
+		repo:            repo,
+		rule_repo:       rule_repo,
+		category_repo:   category_repo,
+		expense_service: expense_service,
+		tx:              tx,
+	}
+}
+
+// PreviewImport разбирает выписку, предлагает категорию для каждого расхода по правилам пользователя,
+// отмечает дубликаты уже внесенных расходов и сохраняет результат как неподтвержденную загрузку.
+// Поступления в выписке пропускаются: доходы учитываются отдельно
+func (s *ImportService) PreviewImport(ctx context.Context, userID uint, fileName string, r io.Reader, req dto.ImportRequest) (dto.ImportPreviewResponse, error) {
+	format, err := importFormat(req.Format, fileName)
+	if err != nil {
+		return dto.ImportPreviewResponse{}, err
+	}
+	negative := true
+	switch strings.ToLower(req.ExpenseSign) {
+	case "", "negative":
+	case "positive":
+		negative = false
+	default:
+		return dto.ImportPreviewResponse{}, errors.New("expense_sign must be negative or positive")
+	}
+	var transactions []statement.Transaction
+	if format == statement.CSV {
+		mapping, err := csvMapping(req)
+		if err != nil {
+			return dto.ImportPreviewResponse{}, err
+		}
+		transactions, err = statement.ParseCSV(r, mapping, MaxImportRows)
+		if err != nil {
+			return dto.ImportPreviewResponse{}, err
+		}
+	} else {
+		transactions, err = statement.ParseOFX(r, MaxImportRows)
+		if err != nil {
+			return dto.ImportPreviewResponse{}, err
+		}
+	}
+	if req.DefaultCategoryID != nil {
+		if _, err := s.category_repo.GetCategoryByID(ctx, userID, int(*req.DefaultCategoryID)); err != nil {
+			return dto.ImportPreviewResponse{}, err
+		}
+	}
+	rules, err := s.rule_repo.GetRules(ctx, userID)
+	if err != nil {
+		return dto.ImportPreviewResponse{}, err
+	}
+	matcher := newRuleMatcher(rules)
+
+	rows := make([]models.ImportRow, 0, len(transactions))
+	skipped := 0
+	for _, transaction := range transactions {
+		amount := transaction.Amount
+		if negative {
+			amount = amount.Neg()
+		}
+		if !amount.IsPositive() {
+			skipped++
+			continue
+		}
+		currency := ""
+		if transaction.Currency != "" {
+			currency, err = NormalizeCurrency(transaction.Currency)
+			if err != nil {
+				return dto.ImportPreviewResponse{}, fmt.Errorf("row %d: %w", transaction.Line, err)
+			}
+		}
+		row := models.ImportRow{
+			RowNumber:   transaction.Line,
+			Date:        transaction.Date,
+			Amount:      amount,
+			Currency:    currency,
+			Description: strings.TrimSpace(transaction.Description),
+			CategoryID:  req.DefaultCategoryID,
+		}
+		if rule := matcher.Match(row.Description, &amount); rule != nil {
+			row.CategoryID = &rule.CategoryID
+			row.RuleID = &rule.ID
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return dto.ImportPreviewResponse{}, errors.New("statement contains no expenses to import")
+	}
+
+	var batch models.ImportBatch
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		batch, err = s.repo.CreateBatch(ctx, models.ImportBatch{
+			UserID:       userID,
+			FileName:     truncateRunes(filepath.Base(fileName), 255),
+			Format:       format,
+			RowsCount:    len(rows),
+			SkippedCount: skipped,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = s.repo.AddRows(ctx, batch.ID, rows)
+		if err != nil {
+			return err
+		}
+		_, err = s.repo.MarkDuplicates(ctx, userID, batch.ID)
+		return err
+	})
+	if err != nil {
+		return dto.ImportPreviewResponse{}, err
+	}
+	return s.buildPreview(ctx, batch)
+}
+
+func (s *ImportService) GetImport(ctx context.Context, userID uint, importID int) (dto.ImportPreviewResponse, error) {
+	batch, err := s.repo.GetBatch(ctx, userID, importID)
+	if err != nil {
+		return dto.ImportPreviewResponse{}, err
+	}
+	return s.buildPreview(ctx, batch)
+}
+
+func (s *ImportService) GetImports(ctx context.Context, userID uint) (dto.ImportsListResponse, error) {
+	batches, err := s.repo.GetBatches(ctx, userID)
+	if err != nil {
+		return dto.ImportsListResponse{}, err
+	}
+	response := dto.ImportsListResponse{
+		Imports: make([]dto.ImportBatchResponse, 0, len(batches)),
+	}
+	for _, batch := range batches {
+		response.Imports = append(response.Imports, toImportBatchResponse(batch))
+	}
+	return response, nil
+}
+
+// CommitImport создает расходы из строк загрузки в одной транзакции: либо импортируются все принятые строки, либо ни одна.
+// Строка принимается, если она не дубликат, решение из req может принять или пропустить любую строку и заменить категорию.
+// Строки без категории попадают в Uncategorized, строки с категорией правила получают его теги
+func (s *ImportService) CommitImport(ctx context.Context, userID uint, importID int, req dto.CommitImportRequest) (dto.ImportPreviewResponse, error) {
+	decisions := make(map[int]dto.ImportRowDecision, len(req.Rows))
+	for _, decision := range req.Rows {
+		decisions[decision.Row] = decision
+	}
+
+	var batch models.ImportBatch
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		batch, err = s.repo.GetBatch(ctx, userID, importID)
+		if err != nil {
+			return err
+		}
+		if batch.Status != ImportStatusPreview {
+			return fmt.Errorf("import is already %s", batch.Status)
+		}
+		rows, err := s.repo.GetRows(ctx, batch.ID)
+		if err != nil {
+			return err
+		}
+		rules, err := s.rule_repo.GetRules(ctx, userID)
+		if err != nil {
+			return err
+		}
+		rules_by_id := make(map[uint]models.CategorizationRule, len(rules))
+		for _, rule := range rules {
+			rules_by_id[rule.ID] = rule
+		}
+		row_numbers := make(map[int]bool, len(rows))
+		for _, row := range rows {
+			row_numbers[row.RowNumber] = true
+		}
+		for row_number := range decisions {
+			if !row_numbers[row_number] {
+				return fmt.Errorf("row %d is not in the import", row_number)
+			}
+		}
+
+		checked_categories := make(map[uint]bool)
+		for _, row := range rows {
+			decision, ok := decisions[row.RowNumber]
+			accept := row.DuplicateExpenseID == nil
+			if ok && decision.Accept != nil {
+				accept = *decision.Accept
+			}
+			if !accept {
+				continue
+			}
+			category_id := row.CategoryID
+			if ok && decision.CategoryID != nil {
+				category_id = decision.CategoryID
+			}
+			if category_id == nil {
+				uncategorized, err := s.category_repo.GetOrCreateCategory(ctx, userID, UncategorizedCategoryName)
+				if err != nil {
+					return err
+				}
+				category_id = &uncategorized.ID
+				checked_categories[uncategorized.ID] = true
+			}
+			if !checked_categories[*category_id] {
+				if _, err := s.category_repo.GetCategoryByID(ctx, userID, int(*category_id)); err != nil {
+					return fmt.Errorf("row %d: %w", row.RowNumber, err)
+				}
+				checked_categories[*category_id] = true
+			}
+			var tags []string
+			if row.RuleID != nil {
+				if rule, ok := rules_by_id[*row.RuleID]; ok && rule.CategoryID == *category_id {
+					tags = rule.Tags
+				}
+			}
+			expense, err := s.expense_service.CreateExpense(ctx, userID, int(*category_id), dto.CreateExpenseRequest{
+				Amount:      row.Amount,
+				Description: row.Description,
+				Date:        row.Date,
+				Tags:        tags,
+				Currency:    row.Currency,
+			})
+			if err != nil {
+				return fmt.Errorf("row %d: %w", row.RowNumber, err)
+			}
+			err = s.repo.SetRowExpense(ctx, batch.ID, row.RowNumber, *category_id, expense.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return s.repo.MarkCommitted(ctx, userID, batch.ID)
+	})
+	if err != nil {
+		return dto.ImportPreviewResponse{}, err
+	}
+	return s.GetImport(ctx, userID, importID)
+}
+
+// UndoImport отменяет загрузку. У подтвержденной загрузки удаляются созданные ею расходы (с возвратом сумм в бюджеты),
+// неподтвержденная загрузка удаляется целиком
+func (s *ImportService) UndoImport(ctx context.Context, userID uint, importID int) (dto.UndoImportResponse, error) {
+	response := dto.UndoImportResponse{}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		batch, err := s.repo.GetBatch(ctx, userID, importID)
+		if err != nil {
+			return err
+		}
+		response.ImportID = batch.ID
+		switch batch.Status {
+		case ImportStatusPreview:
+			return s.repo.DeleteBatch(ctx, userID, batch.ID)
+		case ImportStatusCommitted:
+			expenses, err := s.repo.GetBatchExpenses(ctx, batch.ID)
+			if err != nil {
+				return err
+			}
+			for _, expense := range expenses {
+				err = s.expense_service.DeleteExpense(ctx, userID, int(expense.CategoryID), int(expense.ID))
+				if err != nil {
+					return err
+				}
+			}
+			response.RemovedExpenses = len(expenses)
+			return s.repo.MarkUndone(ctx, userID, batch.ID)
+		default:
+			return fmt.Errorf("import is already %s", batch.Status)
+		}
+	})
+	if err != nil {
+		return dto.UndoImportResponse{}, err
+	}
+	return response, nil
+}
+
+func (s *ImportService) buildPreview(ctx context.Context, batch models.ImportBatch) (dto.ImportPreviewResponse, error) {
+	rows, err := s.repo.GetRows(ctx, batch.ID)
+	if err != nil {
+		return dto.ImportPreviewResponse{}, err
+	}
+	response := dto.ImportPreviewResponse{
+		Import: toImportBatchResponse(batch),
+		Rows:   make([]dto.ImportRowResponse, 0, len(rows)),
+	}
+	for _, row := range rows {
+		if row.DuplicateExpenseID != nil {
+			response.Duplicates++
+		}
+		if row.CategoryID == nil {
+			response.Uncategorized++
+		}
+		response.Rows = append(response.Rows, dto.ImportRowResponse{
+			Row:                row.RowNumber,
+			Date:               row.Date,
+			Amount:             row.Amount,
+			Currency:           row.Currency,
+			Description:        row.Description,
+			CategoryID:         row.CategoryID,
+			CategoryName:       row.CategoryName,
+			RuleID:             row.RuleID,
+			Duplicate:          row.DuplicateExpenseID != nil,
+			DuplicateExpenseID: row.DuplicateExpenseID,
+			ExpenseID:          row.ExpenseID,
+		})
+	}
+	return response, nil
+}
+
+// importFormat возвращает формат выписки из параметра format, а если он не передан - по расширению файла
+func importFormat(format, fileName string) (string, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileName)) {
+		case ".csv":
+			format = statement.CSV
+		case ".ofx", ".qfx":
+			format = statement.OFX
+		}
+	}
+	switch format {
+	case statement.CSV, statement.OFX:
+		return format, nil
+	case "qfx":
+		return statement.OFX, nil
+	case "":
+		return "", errors.New("cannot detect statement format from file name, pass format=csv or format=ofx")
+	default:
+		return "", fmt.Errorf("unsupported statement format: %s. Supported formats: csv, ofx", format)
+	}
+}
+
+// csvMapping переводит параметры загрузки в разметку CSV. Незаданные параметры берутся из statement.DefaultCSVMapping
+func csvMapping(req dto.ImportRequest) (statement.CSVMapping, error) {
+	mapping := statement.DefaultCSVMapping()
+	if req.DateColumn != "" {
+		mapping.DateColumn = req.DateColumn
+	}
+	if req.AmountColumn != "" {
+		mapping.AmountColumn = req.AmountColumn
+	}
+	if req.DescriptionColumn != "" {
+		mapping.DescriptionColumn = req.DescriptionColumn
+	}
+	mapping.CurrencyColumn = req.CurrencyColumn
+	if req.DateFormat != "" {
+		mapping.DateFormat = req.DateFormat
+	}
+	switch req.Delimiter {
+	case "":
+	case "tab", `\t`:
+		mapping.Delimiter = '\t'
+	default:
+		if utf8.RuneCountInString(req.Delimiter) != 1 {
+			return statement.CSVMapping{}, errors.New("delimiter must be a single character or tab")
+		}
+		mapping.Delimiter, _ = utf8.DecodeRuneInString(req.Delimiter)
+	}
+	switch req.DecimalSeparator {
+	case "", ".":
+	case ",":
+		mapping.DecimalComma = true
+	default:
+		return statement.CSVMapping{}, errors.New("decimal_separator must be . or ,")
+	}
+	return mapping, nil
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
+
+func toImportBatchResponse(batch models.ImportBatch) dto.ImportBatchResponse {
+	return dto.ImportBatchResponse{
+		ID:            batch.ID,
+		FileName:      batch.FileName,
+		Format:        batch.Format,
+		Status:        batch.Status,
+		RowsCount:     batch.RowsCount,
+		SkippedCount:  batch.SkippedCount,
+		ImportedCount: batch.ImportedCount,
+		CreatedAt:     batch.CreatedAt,
+		CommittedAt:   batch.CommittedAt,
+		UndoneAt:      batch.UndoneAt,
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CashFlowDefaultGranularity - интервал отчета о движении средств, если granularity не передан
+const CashFlowDefaultGranularity = "month"
+
+type IncomeService struct {
+	repo      repositories.IncomeRepositoryInterface
+	rate_repo repositories.ExchangeRateRepositoryInterface
+}
+
+func NewIncomeService(repo repositories.IncomeRepositoryInterface, rate_repo repositories.ExchangeRateRepositoryInterface) *IncomeService {
+	return &IncomeService{
+		repo:      repo,
+		rate_repo: rate_repo,
+	}
+}
+
+func (s *IncomeService) CreateIncome(ctx context.Context, userID uint, req dto.CreateIncomeRequest) (dto.IncomeResponse, error) {
+	if strings.TrimSpace(req.Source) == "" {
+		return dto.IncomeResponse{}, errors.New("source is required")
+	}
+	if req.Amount <= 0 {
+		return dto.IncomeResponse{}, errors.New("amount must be greater than zero")
+	}
+	if req.Date.IsZero() {
+		return dto.IncomeResponse{}, errors.New("date is required")
+	}
+	currency := req.Currency
+	if currency != "" {
+		var err error
+		currency, err = NormalizeCurrency(currency)
+		if err != nil {
+			return dto.IncomeResponse{}, err
+		}
+	}
+	req_income := models.Income{
+		UserID:      userID,
+		Source:      strings.TrimSpace(req.Source),
+		Amount:      req.Amount,
+		Description: req.Description,
+		Date:        req.Date,
+		Currency:    currency,
+	}
+	err := s.convertToBase(ctx, &req_income)
+	if err != nil {
+		return dto.IncomeResponse{}, err
+	}
+	res_income, err := s.repo.CreateIncome(ctx, req_income)
+	if err != nil {
+		return dto.IncomeResponse{}, err
+	}
+	return toIncomeResponse(res_income), nil
+}
+
+func (s *IncomeService) GetIncome(ctx context.Context, userID uint, incomeID int) (dto.IncomeResponse, error) {
+	income, err := s.repo.GetIncomeByID(ctx, userID, incomeID)
+	if err != nil {
+		return dto.IncomeResponse{}, err
+	}
+	return toIncomeResponse(income), nil
+}
+
+func (s *IncomeService) GetIncomes(ctx context.Context, userID uint) ([]dto.IncomeResponse, error) {
+	incomes, err := s.repo.GetIncomes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]dto.IncomeResponse, 0, len(incomes))
+	for _, income := range incomes {
+		response = append(response, toIncomeResponse(income))
+	}
+	return response, nil
+}
+
+func (s *IncomeService) UpdateIncome(ctx context.Context, userID uint, incomeID int, req dto.UpdateIncomeRequest) (dto.IncomeResponse, error) {
+	income, err := s.repo.GetIncomeByID(ctx, userID, incomeID)
+	if err != nil {
+		return dto.IncomeResponse{}, err
+	}
+
+	// Применяем только переданные поля, остальные остаются без изменений
+	if req.Source != nil {
+		if strings.TrimSpace(*req.Source) == "" {
+			return dto.IncomeResponse{}, errors.New("source must not be empty")
+		}
+		income.Source = strings.TrimSpace(*req.Source)
+	}
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			return dto.IncomeResponse{}, errors.New("amount must be greater than zero")
+		}
+		income.Amount = *req.Amount
+	}
+	if req.Description != nil {
+		income.Description = *req.Description
+	}
+	if req.Date != nil {
+		income.Date = *req.Date
+	}
+	if req.Currency != nil {
+		income.Currency, err = NormalizeCurrency(*req.Currency)
+		if err != nil {
+			return dto.IncomeResponse{}, err
+		}
+	}
+	// Сумма в базовой валюте пересчитывается по курсу на (возможно новую) дату дохода
+	err = s.convertToBase(ctx, &income)
+	if err != nil {
+		return dto.IncomeResponse{}, err
+	}
+
+	res_income, err := s.repo.UpdateIncome(ctx, income)
+	if err != nil {
+		return dto.IncomeResponse{}, err
+	}
+	return toIncomeResponse(res_income), nil
+}
+
+func (s *IncomeService) DeleteIncome(ctx context.Context, userID uint, incomeID int) error {
+	return s.repo.DeleteIncome(ctx, userID, incomeID)
+}
+
+// GetCashFlow возвращает доходы, расходы и чистый поток по интервалам за период [from, to].
+// По умолчанию берутся последние 12 месяцев с разбивкой по месяцам
+func (s *IncomeService) GetCashFlow(ctx context.Context, userID uint, req dto.CashFlowRequest) (dto.CashFlowResponse, error) {
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = CashFlowDefaultGranularity
+	}
+	switch granularity {
+	case "day", "week", "month", "quarter", "year":
+	default:
+		return dto.CashFlowResponse{}, fmt.Errorf("unsupported granularity: %s. Available values: day, week, month, quarter, year", granularity)
+	}
+
+	to := req.To
+	if to.IsZero() {
+		now := time.Now()
+		to = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	from := req.From
+	if from.IsZero() {
+		from = time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, to.Location())
+	}
+	// to задается датой и включается в период целиком
+	to_exclusive := to.AddDate(0, 0, 1)
+	if !from.Before(to_exclusive) {
+		return dto.CashFlowResponse{}, errors.New("from must not be after to")
+	}
+
+	buckets, err := s.repo.GetCashFlow(ctx, userID, from, to_exclusive, granularity)
+	if err != nil {
+		return dto.CashFlowResponse{}, err
+	}
+
+	response := dto.CashFlowResponse{
+		From:        from,
+		To:          to,
+		Granularity: granularity,
+		Buckets:     make([]dto.CashFlowBucket, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		response.Buckets = append(response.Buckets, dto.CashFlowBucket{
+			Period:   bucket.Period,
+			Income:   bucket.Income,
+			Expenses: bucket.Expenses,
+			Net:      bucket.Income - bucket.Expenses,
+		})
+		response.TotalIncome += bucket.Income
+		response.TotalExpenses += bucket.Expenses
+	}
+	response.Net = response.TotalIncome - response.TotalExpenses
+	return response, nil
+}
+
+func toIncomeResponse(income models.Income) dto.IncomeResponse {
+	return dto.IncomeResponse{
+		ID:          income.ID,
+		Source:      income.Source,
+		Amount:      income.Amount,
+		Description: income.Description,
+		Date:        income.Date,
+		CreatedAt:   income.CreatedAt,
+		Currency:    income.Currency,
+		BaseAmount:  income.BaseAmount,
+	}
+}
+
+// convertToBase заполняет сумму дохода в базовой валюте пользователя по курсу на дату дохода.
+// Если валюта дохода не задана, доход вносится в базовой валюте
+func (s *IncomeService) convertToBase(ctx context.Context, income *models.Income) error {
+	base, rate, err := s.rate_repo.GetRateToBase(ctx, income.UserID, income.Currency, income.Date)
+	if err != nil {
+		return err
+	}
+	if income.Currency == "" {
+		income.Currency = base
+	}
+	income.BaseAmount = income.Amount.Mul(rate)
+	return nil
+}
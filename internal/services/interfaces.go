@@ -3,26 +3,50 @@ package services
 import (
 	"context"
 	"finance/internal/dto"
+	"finance/internal/models"
+	"io"
 	"time"
 )
 
+type AnalyticsServiceInterface interface {
+	GetExpenseTimeSeries(ctx context.Context, userID uint, req dto.TimeSeriesRequest) (dto.TimeSeriesResponse, error)
+	GetExpenseTrends(ctx context.Context, userID uint, req dto.TrendsRequest) (dto.TrendsResponse, error)
+	GetCategoryShares(ctx context.Context, userID uint, req dto.CategorySharesRequest) (dto.CategorySharesResponse, error)
+}
+
 type AuthServiceInterface interface {
 	SignUp(ctx context.Context, req dto.RegisterRequest) (*dto.UserInfo, error)
-	SignIn(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error)
+	SignIn(ctx context.Context, req dto.LoginRequest, client dto.ClientInfo) (*dto.AuthResponse, error)
 	GenerateRefreshToken() (dto.RefreshTokenRequest, error)
-	GenerateAccessToken(userID int) (dto.AccessTokenRequest, error)
+	GenerateAccessToken(userID int, sessionID string, issuedAt time.Time) (dto.AccessTokenRequest, error)
 	ValidateToken(ctx context.Context, req dto.AccessTokenRequest) (*dto.UserID, error)
-	GetUserIDbyRefreshToken(ctx context.Context, refresh_token string) (int, error)
-	RemoveOldRefreshToken(ctx context.Context, userID int) error
-	SaveNewRefreshToken(ctx context.Context, user_id int, token dto.RefreshTokenRequest) error
+	Refresh(ctx context.Context, refresh_token string, client dto.ClientInfo) (*dto.TokenResponse, error)
+	GetSessions(ctx context.Context, userID int, currentSessionID string) (dto.SessionsListResponse, error)
+	DeleteSession(ctx context.Context, userID int, sessionID string) error
+	TouchSession(ctx context.Context, userID int, sessionID string) error
+	DeleteExpiredRefreshTokens(ctx context.Context) (int64, error)
+	Logout(ctx context.Context, access_token string, refresh_token string) error
+	LogoutAll(ctx context.Context, userID int) error
+	RevokeAllSessions(ctx context.Context, userID int) (models.RevokedToken, error)
+	CacheRevocation(token models.RevokedToken)
+	SyncRevokedTokens(ctx context.Context) (int64, error)
 }
 
 type BudgetServiceInterface interface {
 	CreateBudget(ctx context.Context, userID uint, category_id int, req dto.CreateBudgetRequest) (dto.BudgetResponse, error)
 	GetUserBudgets(ctx context.Context, userID uint, category_id int) ([]dto.BudgetResponse, error)
-	//UpdateBudget(ctx context.Context, userID uint, category_id int, budgetID int, req dto.UpdateBudgetRequest) error
+	UpdateBudget(ctx context.Context, userID uint, category_id int, budgetID int, req dto.UpdateBudgetRequest) (dto.BudgetResponse, error)
 	DeleteBudget(ctx context.Context, userID uint, category_id, budgetID int) error
-	//CheckBudgetStatus(ctx context.Context, userID uint) ([]*dto.BudgetStatus, error)
+	CheckBudgetStatus(ctx context.Context, userID uint) ([]*dto.BudgetStatus, error)
+	RecalculateBudgets(ctx context.Context, userID uint) (dto.RecalculateBudgetsResponse, error)
+	GetBudgetHistory(ctx context.Context, userID uint, category_id int, budgetID int) (dto.BudgetHistoryResponse, error)
+	RolloverDueBudgets(ctx context.Context, now time.Time) (int, error)
+}
+
+type BudgetAlertServiceInterface interface {
+	GetAlerts(ctx context.Context, userID uint, query dto.BudgetAlertsQuery) (dto.BudgetAlertsListResponse, error)
+	AcknowledgeAlert(ctx context.Context, userID uint, alertID int) error
+	CreateNearEndAlerts(ctx context.Context, now time.Time) (int64, error)
 }
 
 type CategoryServiceInterface interface {
@@ -37,14 +61,63 @@ type CategoryServiceInterface interface {
 type ExpenseServiceInterface interface {
 	CreateExpense(ctx context.Context, userID uint, category_id int, req dto.CreateExpenseRequest) (dto.ExpenseResponse, error)
 	GetUserExpense(ctx context.Context, userID uint, category_id int, expenseID int) (dto.ExpenseResponse, error)
-	GetUserExpenses(ctx context.Context, category_id int, userID uint) ([]dto.ExpenseResponse, error)
+	GetUserExpenses(ctx context.Context, category_id int, userID uint, query dto.ExpenseListQuery) (dto.ExpensesListResponse, error)
+	UpdateExpense(ctx context.Context, userID uint, category_id int, expenseID int, req dto.UpdateExpenseRequest) (dto.ExpenseResponse, error)
 	DeleteExpense(ctx context.Context, userID uint, category_id int, expenseID int) error
 	GetExpenseAnalytics(ctx context.Context, userID uint, category_id int, period dto.ExpensePeriod) (dto.ExpenseAnalytics, error)
-	updateBudgetsAfterExpense(ctx context.Context, userID uint, categoryID int, amount float64, expenseDate time.Time) error
+}
+
+type ExchangeRateServiceInterface interface {
+	ImportRates(ctx context.Context, req dto.ImportExchangeRatesRequest) (dto.ImportExchangeRatesResponse, error)
+	ImportRatesCSV(ctx context.Context, r io.Reader) (dto.ImportExchangeRatesResponse, error)
+}
+
+type ExportServiceInterface interface {
+	Export(ctx context.Context, userID uint, req dto.ExportRequest) (ExportFile, error)
+}
+
+type ImportServiceInterface interface {
+	PreviewImport(ctx context.Context, userID uint, fileName string, r io.Reader, req dto.ImportRequest) (dto.ImportPreviewResponse, error)
+	GetImport(ctx context.Context, userID uint, importID int) (dto.ImportPreviewResponse, error)
+	GetImports(ctx context.Context, userID uint) (dto.ImportsListResponse, error)
+	CommitImport(ctx context.Context, userID uint, importID int, req dto.CommitImportRequest) (dto.ImportPreviewResponse, error)
+	UndoImport(ctx context.Context, userID uint, importID int) (dto.UndoImportResponse, error)
+}
+
+type IncomeServiceInterface interface {
+	CreateIncome(ctx context.Context, userID uint, req dto.CreateIncomeRequest) (dto.IncomeResponse, error)
+	GetIncome(ctx context.Context, userID uint, incomeID int) (dto.IncomeResponse, error)
+	GetIncomes(ctx context.Context, userID uint) ([]dto.IncomeResponse, error)
+	UpdateIncome(ctx context.Context, userID uint, incomeID int, req dto.UpdateIncomeRequest) (dto.IncomeResponse, error)
+	DeleteIncome(ctx context.Context, userID uint, incomeID int) error
+	GetCashFlow(ctx context.Context, userID uint, req dto.CashFlowRequest) (dto.CashFlowResponse, error)
+}
+
+type RecurringExpenseServiceInterface interface {
+	CreateRecurringExpense(ctx context.Context, userID uint, category_id int, req dto.CreateRecurringExpenseRequest) (dto.RecurringExpenseResponse, error)
+	GetRecurringExpense(ctx context.Context, userID uint, category_id int, recurringID int) (dto.RecurringExpenseResponse, error)
+	GetRecurringExpenses(ctx context.Context, userID uint, category_id int) ([]dto.RecurringExpenseResponse, error)
+	UpdateRecurringExpense(ctx context.Context, userID uint, category_id int, recurringID int, req dto.UpdateRecurringExpenseRequest) (dto.RecurringExpenseResponse, error)
+	DeleteRecurringExpense(ctx context.Context, userID uint, category_id int, recurringID int) error
+	ProcessDueRecurringExpenses(ctx context.Context, now time.Time) (int, error)
+}
+
+type RuleServiceInterface interface {
+	CreateRule(ctx context.Context, userID uint, req dto.CreateRuleRequest) (dto.RuleResponse, error)
+	GetRules(ctx context.Context, userID uint) (dto.RulesListResponse, error)
+	DeleteRule(ctx context.Context, userID uint, ruleID int) error
+	TestRule(ctx context.Context, userID uint, req dto.TestRuleRequest) (dto.TestRuleResponse, error)
+	ApplyRules(ctx context.Context, userID uint) (dto.ApplyRulesResponse, error)
+	CreateExpense(ctx context.Context, userID uint, req dto.CreateExpenseRequest) (dto.CategorizedExpenseResponse, error)
+}
+
+type TagServiceInterface interface {
+	GetTagAnalytics(ctx context.Context, userID uint, req dto.TagAnalyticsRequest) (dto.TagAnalyticsResponse, error)
 }
 
 type UserServiceInterface interface {
 	GetProfile(ctx context.Context, userID uint) (dto.UserProfile, error)
 	DeleteAccount(ctx context.Context, userID uint) error
 	GetUserStats(ctx context.Context, userID uint) (dto.UserStats, error)
+	SetBaseCurrency(ctx context.Context, userID uint, currency string) (dto.UserProfile, error)
 }
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"finance/pkg"
+	"fmt"
+	"time"
+)
+
+type RecurringExpenseService struct {
+	repo            repositories.RecurringExpenseRepositoryInterface
+	expense_service ExpenseServiceInterface
+	tx              repositories.TransactorInterface
+}
+
+func NewRecurringExpenseService(repo repositories.RecurringExpenseRepositoryInterface, expense_service ExpenseServiceInterface, tx repositories.TransactorInterface) *RecurringExpenseService {
+	return &RecurringExpenseService{
+		repo:            repo,
+		expense_service: expense_service,
+		tx:              tx,
+	}
+}
+
+func (r *RecurringExpenseService) CreateRecurringExpense(ctx context.Context, userID uint, category_id int, req dto.CreateRecurringExpenseRequest) (dto.RecurringExpenseResponse, error) {
+	if req.Amount <= 0 {
+		return dto.RecurringExpenseResponse{}, errors.New("amount must be greater than zero")
+	}
+	if req.StartDate.IsZero() {
+		return dto.RecurringExpenseResponse{}, errors.New("start_date is required")
+	}
+	if req.EndDate != nil && req.EndDate.Before(req.StartDate) {
+		return dto.RecurringExpenseResponse{}, errors.New("end_date must not be before start_date")
+	}
+	day_of_month, err := scheduleDayOfMonth(req.Frequency, req.DayOfMonth, req.StartDate)
+	if err != nil {
+		return dto.RecurringExpenseResponse{}, err
+	}
+	next_run, err := pkg.FirstOccurrence(req.StartDate, req.Frequency, derefDay(day_of_month))
+	if err != nil {
+		return dto.RecurringExpenseResponse{}, err
+	}
+
+	req_recurring := models.RecurringExpense{
+		UserID:      userID,
+		CategoryID:  uint(category_id),
+		Amount:      req.Amount,
+		Description: req.Description,
+		Frequency:   req.Frequency,
+		DayOfMonth:  day_of_month,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		NextRunAt:   next_run,
+		IsActive:    req.EndDate == nil || !next_run.After(*req.EndDate),
+	}
+	res_recurring, err := r.repo.CreateRecurringExpense(ctx, req_recurring)
+	if err != nil {
+		return dto.RecurringExpenseResponse{}, err
+	}
+	return toRecurringExpenseResponse(res_recurring), nil
+}
+
+func (r *RecurringExpenseService) GetRecurringExpense(ctx context.Context, userID uint, category_id int, recurringID int) (dto.RecurringExpenseResponse, error) {
+	recurring, err := r.repo.GetRecurringExpenseByID(ctx, userID, category_id, recurringID)
+	if err != nil {
+		return dto.RecurringExpenseResponse{}, err
+	}
+	return toRecurringExpenseResponse(recurring), nil
+}
+
+func (r *RecurringExpenseService) GetRecurringExpenses(ctx context.Context, userID uint, category_id int) ([]dto.RecurringExpenseResponse, error) {
+	recurring_expenses, err := r.repo.GetRecurringExpenses(ctx, userID, category_id)
+	if err != nil {
+		return nil, err
+	}
+	response := make([]dto.RecurringExpenseResponse, 0, len(recurring_expenses))
+	for _, recurring := range recurring_expenses {
+		response = append(response, toRecurringExpenseResponse(recurring))
+	}
+	return response, nil
+}
+
+func (r *RecurringExpenseService) UpdateRecurringExpense(ctx context.Context, userID uint, category_id int, recurringID int, req dto.UpdateRecurringExpenseRequest) (dto.RecurringExpenseResponse, error) {
+	recurring, err := r.repo.GetRecurringExpenseByID(ctx, userID, category_id, recurringID)
+	if err != nil {
+		return dto.RecurringExpenseResponse{}, err
+	}
+
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			return dto.RecurringExpenseResponse{}, errors.New("amount must be greater than zero")
+		}
+		recurring.Amount = *req.Amount
+	}
+	if req.Description != nil {
+		recurring.Description = *req.Description
+	}
+	if req.EndDate != nil {
+		if req.EndDate.Before(recurring.StartDate) {
+			return dto.RecurringExpenseResponse{}, errors.New("end_date must not be before start_date")
+		}
+		recurring.EndDate = req.EndDate
+	}
+	// Возобновление приостановленного расхода: платежи за время паузы не создаются
+	reactivated := req.IsActive != nil && *req.IsActive && !recurring.IsActive
+	if req.IsActive != nil {
+		recurring.IsActive = *req.IsActive
+	}
+	if req.Frequency != nil || req.DayOfMonth != nil {
+		if req.Frequency != nil {
+			recurring.Frequency = *req.Frequency
+		}
+		day := req.DayOfMonth
+		if day == nil && req.Frequency == nil {
+			day = recurring.DayOfMonth
+		}
+		recurring.DayOfMonth, err = scheduleDayOfMonth(recurring.Frequency, day, recurring.StartDate)
+		if err != nil {
+			return dto.RecurringExpenseResponse{}, err
+		}
+		// Новое расписание действует начиная с ближайшего еще не созданного платежа
+		recurring.NextRunAt, err = pkg.FirstOccurrence(recurring.NextRunAt, recurring.Frequency, derefDay(recurring.DayOfMonth))
+		if err != nil {
+			return dto.RecurringExpenseResponse{}, err
+		}
+	}
+
+	if reactivated {
+		recurring.NextRunAt, err = pkg.OccurrenceNotBefore(recurring.NextRunAt, recurring.Frequency, derefDay(recurring.DayOfMonth), time.Now())
+		if err != nil {
+			return dto.RecurringExpenseResponse{}, err
+		}
+		recurring.IsActive = recurring.EndDate == nil || !recurring.NextRunAt.After(*recurring.EndDate)
+	}
+
+	err = r.repo.UpdateRecurringExpense(ctx, recurring)
+	if err != nil {
+		return dto.RecurringExpenseResponse{}, err
+	}
+	return toRecurringExpenseResponse(recurring), nil
+}
+
+func (r *RecurringExpenseService) DeleteRecurringExpense(ctx context.Context, userID uint, category_id int, recurringID int) error {
+	return r.repo.DeleteRecurringExpense(ctx, userID, category_id, recurringID)
+}
+
+// ProcessDueRecurringExpenses создает обычные расходы для всех наступивших платежей, включая пропущенные
+// за время простоя сервера. Каждый регулярный расход обрабатывается в своей транзакции: расходы
+// создаются и next_run_at сдвигается атомарно, поэтому повторный запуск не создает дубликатов.
+// Возвращает количество созданных расходов
+func (r *RecurringExpenseService) ProcessDueRecurringExpenses(ctx context.Context, now time.Time) (int, error) {
+	ids, err := r.repo.GetDueRecurringExpenseIDs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for _, id := range ids {
+		count := 0
+		err := r.tx.WithinTx(ctx, func(ctx context.Context) error {
+			var err error
+			count, err = r.materializeRecurringExpense(ctx, id, now)
+			return err
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recurring expense %d: %w", id, err))
+			continue
+		}
+		created += count
+	}
+	return created, errors.Join(errs...)
+}
+
+// materializeRecurringExpense создает расходы по всем наступившим платежам одного регулярного расхода.
+// Вызывается внутри транзакции
+func (r *RecurringExpenseService) materializeRecurringExpense(ctx context.Context, id uint, now time.Time) (int, error) {
+	recurring, err := r.repo.LockRecurringExpense(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	// После блокировки next_run_at перечитан: если платеж уже обработан другим запуском, цикл не выполнится
+	for recurring.IsActive && !recurring.NextRunAt.After(now) {
+		if recurring.EndDate != nil && recurring.NextRunAt.After(*recurring.EndDate) {
+			recurring.IsActive = false
+			break
+		}
+		_, err := r.expense_service.CreateExpense(ctx, recurring.UserID, int(recurring.CategoryID), dto.CreateExpenseRequest{
+			Amount:      recurring.Amount,
+			Description: recurring.Description,
+			Date:        recurring.NextRunAt,
+		})
+		if err != nil {
+			return 0, err
+		}
+		created++
+
+		recurring.NextRunAt, err = pkg.NextOccurrence(recurring.NextRunAt, recurring.Frequency, derefDay(recurring.DayOfMonth))
+		if err != nil {
+			return 0, err
+		}
+	}
+	if recurring.EndDate != nil && recurring.NextRunAt.After(*recurring.EndDate) {
+		recurring.IsActive = false
+	}
+
+	err = r.repo.UpdateNextRun(ctx, recurring.ID, recurring.NextRunAt, recurring.IsActive)
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
+
+// scheduleDayOfMonth проверяет расписание и возвращает день месяца платежа.
+// Для ежемесячного расписания по умолчанию берется день start_date, для ежегодного - всегда день start_date
+func scheduleDayOfMonth(frequency string, day_of_month *int, start_date time.Time) (*int, error) {
+	switch frequency {
+	case "daily", "weekly":
+		return nil, nil
+	case "monthly":
+		if day_of_month == nil {
+			day := start_date.Day()
+			return &day, nil
+		}
+		if *day_of_month < 1 || *day_of_month > 31 {
+			return nil, errors.New("day_of_month must be between 1 and 31")
+		}
+		day := *day_of_month
+		return &day, nil
+	case "yearly":
+		day := start_date.Day()
+		return &day, nil
+	default:
+		return nil, fmt.Errorf("unsupported frequency: %s", frequency)
+	}
+}
+
+func derefDay(day_of_month *int) int {
+	if day_of_month == nil {
+		return 0
+	}
+	return *day_of_month
+}
+
+func toRecurringExpenseResponse(recurring models.RecurringExpense) dto.RecurringExpenseResponse {
+	return dto.RecurringExpenseResponse{
+		ID:           recurring.ID,
+		CategoryID:   recurring.CategoryID,
+		CategoryName: recurring.CategoryName,
+		Amount:       recurring.Amount,
+		Description:  recurring.Description,
+		Frequency:    recurring.Frequency,
+		DayOfMonth:   recurring.DayOfMonth,
+		StartDate:    recurring.StartDate,
+		EndDate:      recurring.EndDate,
+		NextRunAt:    recurring.NextRunAt,
+		IsActive:     recurring.IsActive,
+		CreatedAt:    recurring.CreatedAt,
+	}
+}
@@ -0,0 +1,311 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/models"
+	"finance/internal/repositories"
+	"finance/pkg/money"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	RuleMatchContains = "contains"
+	RuleMatchRegex    = "regex"
+
+	// MaxRulePatternLength - максимальная длина шаблона правила категоризации в символах
+	MaxRulePatternLength = 200
+	// UncategorizedCategoryName - категория расходов, к которым не подошло ни одно правило.
+	// Создается при первом таком расходе
+	UncategorizedCategoryName = "Uncategorized"
+)
+
+type RuleService struct {
+	repo            repositories.RuleRepositoryInterface
+	category_repo   repositories.CategoryRepositoryInterface
+	expense_repo    repositories.ExpenseRepositoryInterface
+	expense_service ExpenseServiceInterface
+	tx              repositories.TransactorInterface
+}
+
+func NewRuleService(repo repositories.RuleRepositoryInterface, category_repo repositories.CategoryRepositoryInterface, expense_repo repositories.ExpenseRepositoryInterface, expense_service ExpenseServiceInterface, tx repositories.TransactorInterface) *RuleService {
+	return &RuleService{
+		repo:            repo,
+		category_repo:   category_repo,
+		expense_repo:    expense_repo,
+		expense_service: expense_service,
+		tx:              tx,
+	}
+}
+
+func (r *RuleService) CreateRule(ctx context.Context, userID uint, req dto.CreateRuleRequest) (dto.RuleResponse, error) {
+	match_type := strings.ToLower(strings.TrimSpace(req.MatchType))
+	if match_type == "" {
+		match_type = RuleMatchContains
+	}
+	pattern := strings.TrimSpace(req.Pattern)
+	if utf8.RuneCountInString(pattern) > MaxRulePatternLength {
+		return dto.RuleResponse{}, fmt.Errorf("pattern is longer than %d characters", MaxRulePatternLength)
+	}
+	switch match_type {
+	case RuleMatchContains:
+	case RuleMatchRegex:
+		if pattern == "" {
+			return dto.RuleResponse{}, errors.New("pattern is required for regex rules")
+		}
+		if _, err := compileRulePattern(pattern); err != nil {
+			return dto.RuleResponse{}, fmt.Errorf("invalid regex pattern: %w", err)
+		}
+	default:
+		return dto.RuleResponse{}, fmt.Errorf("invalid match_type: %s, must be contains or regex", req.MatchType)
+	}
+	if pattern == "" && req.MinAmount == nil && req.MaxAmount == nil {
+		return dto.RuleResponse{}, errors.New("rule needs a pattern or an amount range")
+	}
+	if (req.MinAmount != nil && req.MinAmount.IsNegative()) || (req.MaxAmount != nil && req.MaxAmount.IsNegative()) {
+		return dto.RuleResponse{}, errors.New("min_amount and max_amount must not be negative")
+	}
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		return dto.RuleResponse{}, errors.New("min_amount must not be greater than max_amount")
+	}
+	tags, err := NormalizeTags(req.Tags)
+	if err != nil {
+		return dto.RuleResponse{}, err
+	}
+	if _, err := r.category_repo.GetCategoryByID(ctx, userID, int(req.CategoryID)); err != nil {
+		return dto.RuleResponse{}, err
+	}
+	rule, err := r.repo.CreateRule(ctx, models.CategorizationRule{
+		UserID:     userID,
+		CategoryID: req.CategoryID,
+		MatchType:  match_type,
+		Pattern:    pattern,
+		MinAmount:  req.MinAmount,
+		MaxAmount:  req.MaxAmount,
+		Tags:       tags,
+		Priority:   req.Priority,
+	})
+	if err != nil {
+		return dto.RuleResponse{}, err
+	}
+	return toRuleResponse(rule), nil
+}
+
+func (r *RuleService) GetRules(ctx context.Context, userID uint) (dto.RulesListResponse, error) {
+	rules, err := r.repo.GetRules(ctx, userID)
+	if err != nil {
+		return dto.RulesListResponse{}, err
+	}
+	response := dto.RulesListResponse{
+		Rules: make([]dto.RuleResponse, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, toRuleResponse(rule))
+	}
+	return response, nil
+}
+
+func (r *RuleService) DeleteRule(ctx context.Context, userID uint, ruleID int) error {
+	return r.repo.DeleteRule(ctx, userID, ruleID)
+}
+
+// TestRule показывает, какое правило сработает для примера операции, ничего не изменяя
+func (r *RuleService) TestRule(ctx context.Context, userID uint, req dto.TestRuleRequest) (dto.TestRuleResponse, error) {
+	rules, err := r.repo.GetRules(ctx, userID)
+	if err != nil {
+		return dto.TestRuleResponse{}, err
+	}
+	rule := newRuleMatcher(rules).Match(req.Description, req.Amount)
+	if rule == nil {
+		return dto.TestRuleResponse{
+			CategoryName: UncategorizedCategoryName,
+			Tags:         []string{},
+		}, nil
+	}
+	response := toRuleResponse(*rule)
+	return dto.TestRuleResponse{
+		Matched:      true,
+		Rule:         &response,
+		CategoryID:   &response.CategoryID,
+		CategoryName: response.CategoryName,
+		Tags:         response.Tags,
+	}, nil
+}
+
+// CreateExpense создает расход в категории первого подходящего правила и добавляет теги правила к тегам запроса.
+// Если ни одно правило не подошло, расход попадает в категорию Uncategorized
+func (r *RuleService) CreateExpense(ctx context.Context, userID uint, req dto.CreateExpenseRequest) (dto.CategorizedExpenseResponse, error) {
+	var response dto.CategorizedExpenseResponse
+	err := r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		rules, err := r.repo.GetRules(ctx, userID)
+		if err != nil {
+			return err
+		}
+		var category_id uint
+		if rule := newRuleMatcher(rules).Match(req.Description, &req.Amount); rule != nil {
+			category_id = rule.CategoryID
+			req.Tags = append(append([]string{}, req.Tags...), rule.Tags...)
+			response.RuleID = &rule.ID
+		} else {
+			category, err := r.category_repo.GetOrCreateCategory(ctx, userID, UncategorizedCategoryName)
+			if err != nil {
+				return err
+			}
+			category_id = category.ID
+		}
+		response.ExpenseResponse, err = r.expense_service.CreateExpense(ctx, userID, int(category_id), req)
+		return err
+	})
+	if err != nil {
+		return dto.CategorizedExpenseResponse{}, err
+	}
+	return response, nil
+}
+
+// ApplyRules повторно применяет правила к расходам категории Uncategorized и переносит подходящие расходы
+// в категории правил с пересчетом бюджетов. Все переносы выполняются в одной транзакции
+func (r *RuleService) ApplyRules(ctx context.Context, userID uint) (dto.ApplyRulesResponse, error) {
+	response := dto.ApplyRulesResponse{
+		Expenses: []dto.AppliedRule{},
+	}
+	err := r.tx.WithinTx(ctx, func(ctx context.Context) error {
+		categories, err := r.category_repo.GetCategories(ctx, userID)
+		if err != nil {
+			return err
+		}
+		var uncategorized *models.Category
+		for i := range categories {
+			if categories[i].Name == UncategorizedCategoryName {
+				uncategorized = &categories[i]
+				break
+			}
+		}
+		if uncategorized == nil {
+			return nil
+		}
+		rules, err := r.repo.GetRules(ctx, userID)
+		if err != nil {
+			return err
+		}
+		matcher := newRuleMatcher(rules)
+		expenses, err := r.expense_repo.GetExpensesByCategory(ctx, userID, int(uncategorized.ID))
+		if err != nil {
+			return err
+		}
+		response.Checked = len(expenses)
+		for _, expense := range expenses {
+			rule := matcher.Match(expense.Description, &expense.Amount)
+			if rule == nil || rule.CategoryID == uncategorized.ID {
+				continue
+			}
+			update := dto.UpdateExpenseRequest{
+				CategoryID: &rule.CategoryID,
+			}
+			if len(rule.Tags) > 0 {
+				current, err := r.expense_service.GetUserExpense(ctx, userID, int(uncategorized.ID), int(expense.ID))
+				if err != nil {
+					return err
+				}
+				tags := append(append([]string{}, current.Tags...), rule.Tags...)
+				update.Tags = &tags
+			}
+			_, err := r.expense_service.UpdateExpense(ctx, userID, int(uncategorized.ID), int(expense.ID), update)
+			if err != nil {
+				return fmt.Errorf("expense %d: %w", expense.ID, err)
+			}
+			response.Expenses = append(response.Expenses, dto.AppliedRule{
+				ExpenseID:  expense.ID,
+				RuleID:     rule.ID,
+				CategoryID: rule.CategoryID,
+			})
+		}
+		response.Categorized = len(response.Expenses)
+		return nil
+	})
+	if err != nil {
+		return dto.ApplyRulesResponse{}, err
+	}
+	return response, nil
+}
+
+// ruleMatcher применяет правила пользователя к операциям. Регулярные выражения компилируются один раз
+type ruleMatcher struct {
+	rules    []models.CategorizationRule
+	patterns []*regexp.Regexp
+}
+
+// newRuleMatcher готовит правила к применению. rules должны быть отсортированы в порядке применения,
+// как их возвращает репозиторий
+func newRuleMatcher(rules []models.CategorizationRule) *ruleMatcher {
+	matcher := &ruleMatcher{
+		rules:    rules,
+		patterns: make([]*regexp.Regexp, len(rules)),
+	}
+	for i, rule := range rules {
+		if rule.MatchType == RuleMatchRegex {
+			// Выражение проверено при создании правила, некомпилируемое правило просто не срабатывает
+			matcher.patterns[i], _ = compileRulePattern(rule.Pattern)
+		}
+	}
+	return matcher
+}
+
+// Match возвращает первое правило, под все условия которого подходит операция. Описание сравнивается без учета регистра.
+// amount = nil - сумма неизвестна, правила с диапазоном суммы не подходят
+func (m *ruleMatcher) Match(description string, amount *money.Amount) *models.CategorizationRule {
+	lower := strings.ToLower(description)
+	for i := range m.rules {
+		rule := &m.rules[i]
+		if rule.MinAmount != nil || rule.MaxAmount != nil {
+			if amount == nil {
+				continue
+			}
+			if rule.MinAmount != nil && *amount < *rule.MinAmount {
+				continue
+			}
+			if rule.MaxAmount != nil && *amount > *rule.MaxAmount {
+				continue
+			}
+		}
+		switch rule.MatchType {
+		case RuleMatchRegex:
+			if m.patterns[i] == nil || !m.patterns[i].MatchString(description) {
+				continue
+			}
+		default:
+			if !strings.Contains(lower, strings.ToLower(rule.Pattern)) {
+				continue
+			}
+		}
+		return rule
+	}
+	return nil
+}
+
+// compileRulePattern компилирует регулярное выражение правила без учета регистра
+func compileRulePattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("(?i)" + pattern)
+}
+
+func toRuleResponse(rule models.CategorizationRule) dto.RuleResponse {
+	tags := rule.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return dto.RuleResponse{
+		ID:           rule.ID,
+		CategoryID:   rule.CategoryID,
+		CategoryName: rule.CategoryName,
+		MatchType:    rule.MatchType,
+		Pattern:      rule.Pattern,
+		MinAmount:    rule.MinAmount,
+		MaxAmount:    rule.MaxAmount,
+		Tags:         tags,
+		Priority:     rule.Priority,
+		CreatedAt:    rule.CreatedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"finance/internal/models"
+	"finance/pkg/money"
+	"testing"
+)
+
+func amountPtr(minor int64) *money.Amount {
+	amount := money.FromMinor(minor)
+	return &amount
+}
+
+func TestRuleMatcherMatch(t *testing.T) {
+	// Правила в порядке применения, как их возвращает репозиторий
+	matcher := newRuleMatcher([]models.CategorizationRule{
+		{ID: 1, MatchType: RuleMatchContains, Pattern: "Taxi", MinAmount: amountPtr(100000)},
+		{ID: 2, MatchType: RuleMatchContains, Pattern: "taxi"},
+		{ID: 3, MatchType: RuleMatchRegex, Pattern: `^(pyaterochka|magnit)\b`},
+		{ID: 4, MatchType: RuleMatchContains, Pattern: "Кофейня"},
+		{ID: 5, MatchType: RuleMatchRegex, Pattern: `([invalid`},
+		{ID: 6, MatchType: RuleMatchContains, Pattern: "", MinAmount: amountPtr(5000), MaxAmount: amountPtr(10000)},
+	})
+
+	tests := []struct {
+		name        string
+		description string
+		amount      *money.Amount
+		want        uint // 0 - ни одно правило не подходит
+	}{
+		{"first rule wins over later", "YANDEX TAXI", amountPtr(150000), 1},
+		{"min amount is inclusive", "yandex taxi", amountPtr(100000), 1},
+		{"below min falls through", "Yandex.Taxi", amountPtr(99999), 2},
+		{"unknown amount skips ranged rule", "taxi ride", nil, 2},
+		{"regex is case insensitive", "MAGNIT 1234", amountPtr(100), 3},
+		{"regex anchors respected", "to magnit", amountPtr(100), 0},
+		{"regex word boundary", "pyaterochkaplus", amountPtr(100), 0},
+		{"cyrillic case folding", "КОФЕЙНЯ на углу", amountPtr(100), 4},
+		{"empty pattern matches any description in range", "anything", amountPtr(7500), 6},
+		{"max amount is inclusive", "anything", amountPtr(10000), 6},
+		{"above max", "anything", amountPtr(10001), 0},
+		{"empty description", "", amountPtr(5000), 6},
+		{"nothing matches", "groceries", amountPtr(100), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := matcher.Match(tt.description, tt.amount)
+			var got uint
+			if rule != nil {
+				got = rule.ID
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q) = rule %d, want %d", tt.description, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleMatcherInvalidRegex(t *testing.T) {
+	// Некомпилируемое выражение не срабатывает и не мешает следующим правилам
+	matcher := newRuleMatcher([]models.CategorizationRule{
+		{ID: 1, MatchType: RuleMatchRegex, Pattern: `(`},
+		{ID: 2, MatchType: RuleMatchContains, Pattern: "("},
+	})
+	rule := matcher.Match("(", amountPtr(100))
+	if rule == nil || rule.ID != 2 {
+		t.Errorf("Match = %v, want rule 2", rule)
+	}
+	if rule := newRuleMatcher(nil).Match("anything", amountPtr(100)); rule != nil {
+		t.Errorf("Match without rules = %v, want nil", rule)
+	}
+}
@@ -8,15 +8,39 @@ type Services struct { // создаем структуру, которая бу
 	CategoryServiceInterface
 	UserServiceInterface
 	BudgetServiceInterface
+	BudgetAlertServiceInterface
+	RecurringExpenseServiceInterface
+	IncomeServiceInterface
+	TagServiceInterface
+	AnalyticsServiceInterface
+	ExchangeRateServiceInterface
+	ExportServiceInterface
+	ImportServiceInterface
+	RuleServiceInterface
 }
 
 func NewServices(repo *repositories.Repositories) *Services {
+	expenseService := NewExpenseService(repo.ExpenseRepositoryInterface, repo.BudgetRepositoryInterface, repo.TagRepositoryInterface, repo.BudgetAlertRepositoryInterface, repo.ExchangeRateRepositoryInterface, repo.TransactorInterface)
+	// Удаление аккаунта отзывает токены пользователя через тот же сервис, чтобы обновился кэш отозванных токенов
+	authService := NewAuthService(repo.AuthRepositoryInterface, repo.TransactorInterface)
 	return &Services{
-		AuthServiceInterface:     NewAuthService(repo.AuthRepositoryInterface),
-		BudgetServiceInterface:   NewBudgetService(repo.BudgetRepositoryInterface, repo.ExpenseRepositoryInterface),
-		ExpenseServiceInterface:  NewExpenseService(repo.ExpenseRepositoryInterface, repo.BudgetRepositoryInterface),
-		CategoryServiceInterface: NewCategoryService(repo.CategoryRepositoryInterface, repo.BudgetRepositoryInterface, repo.ExpenseRepositoryInterface),
-		UserServiceInterface:     NewUserService(repo.UserRepositoryInterface),
+		AuthServiceInterface:         authService,
+		BudgetServiceInterface:       NewBudgetService(repo.BudgetRepositoryInterface, repo.BudgetAlertRepositoryInterface, repo.ExchangeRateRepositoryInterface, repo.TransactorInterface),
+		BudgetAlertServiceInterface:  NewBudgetAlertService(repo.BudgetAlertRepositoryInterface),
+		ExpenseServiceInterface:      expenseService,
+		CategoryServiceInterface:     NewCategoryService(repo.CategoryRepositoryInterface, repo.BudgetRepositoryInterface, repo.ExpenseRepositoryInterface, repo.TransactorInterface),
+		UserServiceInterface:         NewUserService(repo.UserRepositoryInterface, repo.ExchangeRateRepositoryInterface, repo.BudgetRepositoryInterface, authService, repo.TransactorInterface),
+		IncomeServiceInterface:       NewIncomeService(repo.IncomeRepositoryInterface, repo.ExchangeRateRepositoryInterface),
+		AnalyticsServiceInterface:    NewAnalyticsService(repo.AnalyticsRepositoryInterface),
+		TagServiceInterface:          NewTagService(repo.TagRepositoryInterface),
+		ExchangeRateServiceInterface: NewExchangeRateService(repo.ExchangeRateRepositoryInterface, repo.BudgetRepositoryInterface, repo.TransactorInterface),
+		// Регулярные расходы создают обычные расходы через тот же сервис, чтобы обновлялись бюджеты
+		RecurringExpenseServiceInterface: NewRecurringExpenseService(repo.RecurringExpenseRepositoryInterface, expenseService, repo.TransactorInterface),
+		// Импорт выписок создает и удаляет расходы через тот же сервис, чтобы обновлялись бюджеты
+		ImportServiceInterface: NewImportService(repo.ImportRepositoryInterface, repo.RuleRepositoryInterface, repo.CategoryRepositoryInterface, expenseService, repo.TransactorInterface),
+		// Правила создают и переносят расходы через тот же сервис, чтобы обновлялись бюджеты
+		RuleServiceInterface:   NewRuleService(repo.RuleRepositoryInterface, repo.CategoryRepositoryInterface, repo.ExpenseRepositoryInterface, expenseService, repo.TransactorInterface),
+		ExportServiceInterface: NewExportService(repo.ExportRepositoryInterface, repo.UserRepositoryInterface, repo.TransactorInterface),
 	}
 
 }
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"finance/internal/dto"
+	"finance/internal/repositories"
+	"time"
+)
+
+type TagService struct {
+	repo repositories.TagRepositoryInterface
+}
+
+func NewTagService(repo repositories.TagRepositoryInterface) *TagService {
+	return &TagService{
+		repo: repo,
+	}
+}
+
+// GetTagAnalytics возвращает сумму расходов по каждому тегу независимо от категорий.
+// to включается в период целиком; без from и to считаются все расходы
+func (t *TagService) GetTagAnalytics(ctx context.Context, userID uint, req dto.TagAnalyticsRequest) (dto.TagAnalyticsResponse, error) {
+	var from, to *time.Time
+	if !req.From.IsZero() {
+		from = &req.From
+	}
+	if !req.To.IsZero() {
+		to_exclusive := req.To.AddDate(0, 0, 1)
+		to = &to_exclusive
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return dto.TagAnalyticsResponse{}, errors.New("from must not be after to")
+	}
+
+	summaries, err := t.repo.GetTagAnalytics(ctx, userID, from, to)
+	if err != nil {
+		return dto.TagAnalyticsResponse{}, err
+	}
+	response := dto.TagAnalyticsResponse{
+		Tags: make([]dto.TagSummary, 0, len(summaries)),
+	}
+	for _, summary := range summaries {
+		response.Tags = append(response.Tags, dto.TagSummary{
+			Tag:             summary.Tag,
+			TotalAmount:     summary.TotalAmount,
+			ExpensesCount:   summary.ExpensesCount,
+			CategoriesCount: summary.CategoriesCount,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"finance/internal/models"
+	"sync"
+	"time"
+)
+
+// revocationCache - копия таблицы revoked_tokens в памяти, чтобы не ходить в базу при проверке каждого запроса.
+// Отзывы этого экземпляра попадают в кэш сразу, отзывы других экземпляров - при следующей синхронизации
+type revocationCache struct {
+	mu       sync.RWMutex
+	synced   bool
+	tokens   map[string]time.Time // jti -> когда токен истечет сам
+	sessions map[string]time.Time // family_id -> когда истекут все токены сеанса
+	users    map[int]time.Time    // user_id -> отозваны все токены, выданные не позже этого времени
+	// usersExpire - когда отзыв всех токенов пользователя можно забыть
+	usersExpire map[int]time.Time
+}
+
+func newRevocationCache() *revocationCache {
+	return &revocationCache{
+		tokens:      make(map[string]time.Time),
+		sessions:    make(map[string]time.Time),
+		users:       make(map[int]time.Time),
+		usersExpire: make(map[int]time.Time),
+	}
+}
+
+// Synced сообщает, загружен ли кэш из базы хотя бы один раз
+func (c *revocationCache) Synced() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.synced
+}
+
+// Add добавляет отзыв в кэш
+func (c *revocationCache) Add(token models.RevokedToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.add(token)
+}
+
+// Replace загружает отзывы из базы и удаляет истекшие. Отзывы уже в кэше сохраняются: отозванный токен
+// не может стать снова действительным, а запись о нем могла еще не попасть в выборку
+func (c *revocationCache) Replace(tokens []models.RevokedToken, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, token := range tokens {
+		c.add(token)
+	}
+	for jti, expires_at := range c.tokens {
+		if !expires_at.After(now) {
+			delete(c.tokens, jti)
+		}
+	}
+	for session_id, expires_at := range c.sessions {
+		if !expires_at.After(now) {
+			delete(c.sessions, session_id)
+		}
+	}
+	for user_id, expires_at := range c.usersExpire {
+		if !expires_at.After(now) {
+			delete(c.users, user_id)
+			delete(c.usersExpire, user_id)
+		}
+	}
+	c.synced = true
+}
+
+// IsRevoked проверяет токен по jti, сеансу и времени выдачи. Время выдачи в JWT хранится с точностью до секунды,
+// поэтому токен с iat в секунду отзыва всех токенов считается отозванным. Токены, выданные после отзыва,
+// получают iat не раньше следующей секунды (см. AuthService.issueTokens)
+func (c *revocationCache) IsRevoked(jti string, sessionID string, userID int, issuedAt time.Time) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if _, ok := c.tokens[jti]; ok {
+		return true
+	}
+	if _, ok := c.sessions[sessionID]; ok && sessionID != "" {
+		return true
+	}
+	revoked_at, ok := c.users[userID]
+	return ok && !issuedAt.After(revoked_at.Truncate(time.Second))
+}
+
+func (c *revocationCache) add(token models.RevokedToken) {
+	if token.JTI != "" {
+		c.tokens[token.JTI] = token.ExpiresAt
+		return
+	}
+	if token.FamilyID != "" {
+		if token.ExpiresAt.After(c.sessions[token.FamilyID]) {
+			c.sessions[token.FamilyID] = token.ExpiresAt
+		}
+		return
+	}
+	if token.RevokedAt.After(c.users[token.UserID]) {
+		c.users[token.UserID] = token.RevokedAt
+	}
+	if token.ExpiresAt.After(c.usersExpire[token.UserID]) {
+		c.usersExpire[token.UserID] = token.ExpiresAt
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"finance/internal/models"
+	"testing"
+	"time"
+)
+
+func TestRevocationCacheIsRevoked(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	revokedAt := now.Add(500 * time.Millisecond)
+	c := newRevocationCache()
+	c.Add(models.RevokedToken{UserID: 1, JTI: "jti-1", RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	c.Add(models.RevokedToken{UserID: 2, FamilyID: "session-2", RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	c.Add(models.RevokedToken{UserID: 3, RevokedAt: revokedAt, ExpiresAt: now.Add(time.Hour)})
+
+	tests := []struct {
+		name      string
+		jti       string
+		sessionID string
+		userID    int
+		issuedAt  time.Time
+		want      bool
+	}{
+		{"revoked jti", "jti-1", "session-1", 1, now, true},
+		{"other jti", "jti-2", "session-1", 1, now, false},
+		{"revoked session", "jti-3", "session-2", 2, now.Add(time.Minute), true},
+		{"other session", "jti-3", "session-3", 2, now, false},
+		{"token without session", "jti-3", "", 2, now, false},
+		{"issued before logout all", "jti-4", "session-4", 3, now.Add(-time.Minute), true},
+		// iat в JWT округляется до секунды: токен, выданный в ту же секунду, что и отзыв, отозван
+		{"issued in the same second", "jti-4", "session-4", 3, now, true},
+		{"issued in the next second", "jti-4", "session-4", 3, now.Add(time.Second), false},
+		{"other user", "jti-4", "session-4", 4, now.Add(-time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsRevoked(tt.jti, tt.sessionID, tt.userID, tt.issuedAt); got != tt.want {
+				t.Errorf("IsRevoked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevocationCacheKeepsLatest(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	c := newRevocationCache()
+	c.Add(models.RevokedToken{UserID: 1, RevokedAt: now, ExpiresAt: now.Add(2 * time.Hour)})
+	// Более ранний отзыв, пришедший позже, не сдвигает границу назад и не сокращает срок хранения
+	c.Add(models.RevokedToken{UserID: 1, RevokedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)})
+
+	if !c.IsRevoked("jti", "session", 1, now.Add(-time.Minute)) {
+		t.Error("token issued before the latest revocation must stay revoked")
+	}
+	c.Replace(nil, now.Add(90*time.Minute))
+	if !c.IsRevoked("jti", "session", 1, now.Add(-time.Minute)) {
+		t.Error("revocation must be kept until the latest expiry")
+	}
+}
+
+func TestRevocationCacheReplace(t *testing.T) {
+	now := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	c := newRevocationCache()
+	if c.Synced() {
+		t.Fatal("new cache must not be synced")
+	}
+
+	// Отзыв этого экземпляра, которого еще нет в выборке из базы
+	c.Add(models.RevokedToken{UserID: 1, JTI: "local", RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	c.Add(models.RevokedToken{UserID: 1, JTI: "expired", RevokedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)})
+	c.Replace([]models.RevokedToken{
+		{UserID: 2, JTI: "remote", RevokedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{UserID: 2, FamilyID: "remote-session", RevokedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{UserID: 2, FamilyID: "old-session", RevokedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Minute)},
+		{UserID: 3, RevokedAt: now, ExpiresAt: now.Add(time.Hour)},
+		{UserID: 4, RevokedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Minute)},
+	}, now)
+
+	if !c.Synced() {
+		t.Error("cache must be synced after Replace")
+	}
+	checks := []struct {
+		name      string
+		jti       string
+		sessionID string
+		userID    int
+		want      bool
+	}{
+		{"local revocation kept", "local", "", 1, true},
+		{"expired jti purged", "expired", "", 1, false},
+		{"remote jti loaded", "remote", "", 2, true},
+		{"remote session loaded", "jti", "remote-session", 2, true},
+		{"expired session purged", "jti", "old-session", 2, false},
+		{"user revocation loaded", "jti", "", 3, true},
+		{"expired user revocation purged", "jti", "", 4, false},
+	}
+	issuedAt := now.Add(-3 * time.Hour)
+	for _, tt := range checks {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.IsRevoked(tt.jti, tt.sessionID, tt.userID, issuedAt); got != tt.want {
+				t.Errorf("IsRevoked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
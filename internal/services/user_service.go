@@ -3,16 +3,27 @@ package services
 import (
 	"context"
 	"finance/internal/dto"
+	"finance/internal/models"
 	"finance/internal/repositories"
+	"fmt"
+	"time"
 )
 
 type UserService struct {
-	repo repositories.UserRepositoryInterface
+	repo         repositories.UserRepositoryInterface
+	rate_repo    repositories.ExchangeRateRepositoryInterface
+	budget_repo  repositories.BudgetRepositoryInterface
+	auth_service AuthServiceInterface
+	tx           repositories.TransactorInterface
 }
 
-func NewUserService(repo repositories.UserRepositoryInterface) *UserService {
+func NewUserService(repo repositories.UserRepositoryInterface, rate_repo repositories.ExchangeRateRepositoryInterface, budget_repo repositories.BudgetRepositoryInterface, auth_service AuthServiceInterface, tx repositories.TransactorInterface) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:         repo,
+		rate_repo:    rate_repo,
+		budget_repo:  budget_repo,
+		auth_service: auth_service,
+		tx:           tx,
 	}
 }
 
@@ -22,16 +33,83 @@ func (s *UserService) GetProfile(ctx context.Context, userID uint) (dto.UserProf
 		return dto.UserProfile{}, err
 	}
 	res_profile := dto.UserProfile{
-		Email:     userprofile.Email,
-		FirstName: userprofile.FirstName,
-		LastName:  userprofile.LastName,
-		CreatedAt: userprofile.TimeOfRegistration,
+		Email:        userprofile.Email,
+		FirstName:    userprofile.FirstName,
+		LastName:     userprofile.LastName,
+		CreatedAt:    userprofile.TimeOfRegistration,
+		BaseCurrency: userprofile.BaseCurrency,
 	}
 	return res_profile, nil
 }
 
+// SetBaseCurrency меняет базовую валюту пользователя и пересчитывает в нее все его расходы и доходы.
+// Если хотя бы для одного расхода или дохода нет курса на его дату или потраченную сумму бюджета нельзя пересчитать в его валюту,
+// валюта не меняется
+func (s *UserService) SetBaseCurrency(ctx context.Context, userID uint, currency string) (dto.UserProfile, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return dto.UserProfile{}, err
+	}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		missing, err := s.rate_repo.CountExpensesWithoutRate(ctx, userID, currency)
+		if err != nil {
+			return err
+		}
+		if missing > 0 {
+			return fmt.Errorf("no exchange rate to %s for %d expenses", currency, missing)
+		}
+		missing, err = s.rate_repo.CountIncomesWithoutRate(ctx, userID, currency)
+		if err != nil {
+			return err
+		}
+		if missing > 0 {
+			return fmt.Errorf("no exchange rate to %s for %d incomes", currency, missing)
+		}
+		err = s.repo.UpdateBaseCurrency(ctx, userID, currency)
+		if err != nil {
+			return err
+		}
+		_, err = s.rate_repo.RecalculateBaseAmounts(ctx, userID, time.Time{})
+		if err != nil {
+			return err
+		}
+		_, err = s.rate_repo.RecalculateIncomeBaseAmounts(ctx, userID, time.Time{})
+		if err != nil {
+			return err
+		}
+		// Бюджеты в других валютах могли считаться через базовую валюту
+		_, skipped, err := s.budget_repo.RecalculateSpentAmounts(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if skipped > 0 {
+			return errBudgetsWithoutRate(skipped)
+		}
+		return nil
+	})
+	if err != nil {
+		return dto.UserProfile{}, err
+	}
+	return s.GetProfile(ctx, userID)
+}
+
+// DeleteAccount удаляет пользователя и отзывает все выданные ему токены
 func (s *UserService) DeleteAccount(ctx context.Context, userID uint) error {
-	return s.repo.DeleteUser(ctx, userID)
+	var revoked models.RevokedToken
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		revoked, err = s.auth_service.RevokeAllSessions(ctx, int(userID))
+		if err != nil {
+			return err
+		}
+		return s.repo.DeleteUser(ctx, userID)
+	})
+	if err != nil {
+		return err
+	}
+	// Отзыв попадает в кэш только после фиксации: при откате аккаунт и его токены остаются действующими
+	s.auth_service.CacheRevocation(revoked)
+	return nil
 }
 
 func (s *UserService) GetUserStats(ctx context.Context, userID uint) (dto.UserStats, error) {
@@ -45,7 +123,13 @@ func (s *UserService) GetUserStats(ctx context.Context, userID uint) (dto.UserSt
 		TotalBudgets:    userstats.TotalBudgets,
 		MonthlyExpenses: userstats.MonthlyExpenses,
 		WeeklyExpenses:  userstats.WeeklyExpenses,
+		TotalIncome:     userstats.TotalIncome,
+		Currency:        userstats.Currency,
 		// TopCategories:   nil,
 	}
+	// Норма сбережений: какая доля дохода осталась после расходов. Без доходов считать не от чего
+	if userstats.TotalIncome.IsPositive() {
+		res_stats.SavingsRate = userstats.TotalIncome.Sub(userstats.TotalExpensesAmount).Ratio(userstats.TotalIncome) * 100
+	}
 	return res_stats, nil
 }
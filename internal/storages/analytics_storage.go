@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AnalyticsStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewAnalyticsStorage(pool *pgxpool.Pool) *AnalyticsStorage {
+	return &AnalyticsStorage{
+		pool: pool,
+	}
+}
+
+func (s *AnalyticsStorage) GetExpenseTimeSeries(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time, granularity string) ([]models.ExpenseBucket, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, granularity, from, to, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expense time series: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.ExpenseBucket
+	for rows.Next() {
+		var bucket models.ExpenseBucket
+		err := rows.Scan(
+			&bucket.Period,
+			&bucket.Amount,
+			&bucket.Count,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time series bucket: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over time series: %w", err)
+	}
+
+	return buckets, nil
+}
+
+func (s *AnalyticsStorage) GetCategoryPeriodTotals(ctx context.Context, query string, userID uint, categoryID int, current, previous, lastYear models.TimeRange) ([]models.CategoryPeriodTotals, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, categoryID,
+		current.From, current.To, previous.From, previous.To, lastYear.From, lastYear.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category period totals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []models.CategoryPeriodTotals
+	for rows.Next() {
+		var total models.CategoryPeriodTotals
+		err := rows.Scan(
+			&total.CategoryID,
+			&total.CategoryName,
+			&total.Current,
+			&total.Previous,
+			&total.LastYear,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category period totals: %w", err)
+		}
+		totals = append(totals, total)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over category period totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+func (s *AnalyticsStorage) GetCategoryShares(ctx context.Context, query string, userID uint, from, to time.Time, minShare float64) ([]models.CategoryShare, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, from, to, minShare)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category shares: %w", err)
+	}
+	defer rows.Close()
+
+	var shares []models.CategoryShare
+	for rows.Next() {
+		var share models.CategoryShare
+		err := rows.Scan(
+			&share.CategoryID,
+			&share.CategoryName,
+			&share.Amount,
+			&share.Percentage,
+			&share.CategoriesCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category share: %w", err)
+		}
+		shares = append(shares, share)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over category shares: %w", err)
+	}
+
+	return shares, nil
+}
@@ -2,10 +2,12 @@ package storage
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"finance/internal/models"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,25 +23,36 @@ func NewAuthStorage(pool *pgxpool.Pool) *AuthStorage {
 
 func (s *AuthStorage) CreateUser(ctx context.Context, query string, first_name string, last_name string, email string, password string, timeOfRegistration time.Time) (models.User, error) {
 	var result models.User
-	err := s.pool.QueryRow(ctx, query, first_name, last_name, email, password, timeOfRegistration).Scan(&result.ID, &result.Email, &result.FirstName, &result.LastName)
+	err := conn(ctx, s.pool).QueryRow(ctx, query, first_name, last_name, email, password, timeOfRegistration).Scan(&result.ID, &result.Email, &result.FirstName, &result.LastName)
 	if err != nil {
 		return models.User{}, err
 	}
 	return result, nil
 }
 
-func (s *AuthStorage) CheckUserVerification(ctx context.Context, query string, email string, hashpassword string) (models.User, error) {
+func (s *AuthStorage) GetUserByEmail(ctx context.Context, query string, email string) (models.User, error) {
 	var result models.User
-	err := s.pool.QueryRow(ctx, query, email, hashpassword).Scan(&result.ID, &result.Email, &result.FirstName, &result.LastName)
+	err := conn(ctx, s.pool).QueryRow(ctx, query, email).Scan(&result.ID, &result.Email, &result.FirstName, &result.LastName, &result.Password)
 	if err != nil {
-		return models.User{}, err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.User{}, nil // пользователь не найден
+		}
+		return models.User{}, fmt.Errorf("failed to get user by email: %w", err)
 	}
 	return result, nil
 }
 
+func (s *AuthStorage) UpdatePasswordHash(ctx context.Context, query string, userID int, hashpassword string) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, userID, hashpassword)
+	if err != nil {
+		return fmt.Errorf("failed to update password hash: %w", err)
+	}
+	return nil
+}
+
 func (s *AuthStorage) UserExistsByEmail(ctx context.Context, query string, email string) (bool, error) {
 	var exists bool
-	err := s.pool.QueryRow(ctx, query, email).Scan(&exists)
+	err := conn(ctx, s.pool).QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, err
 	}
@@ -47,30 +60,180 @@ func (s *AuthStorage) UserExistsByEmail(ctx context.Context, query string, email
 	return exists, nil
 }
 
-func (s *AuthStorage) GetUserIDbyRefreshToken(ctx context.Context, query string, refreshToken string) (int, error) {
-	var userID int
-	err := s.pool.QueryRow(ctx, query, refreshToken).Scan(&userID)
+func (s *AuthStorage) RemoveOldRefreshToken(ctx context.Context, query string, userID int) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, userID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return 0, nil // токен не найден или истек
-		}
-		return 0, err
+		return err
 	}
-	return userID, nil
+	return nil
 }
 
-func (s *AuthStorage) RemoveOldRefreshToken(ctx context.Context, query string, userID int) error {
-	_, err := s.pool.Exec(ctx, query, userID)
+func (s *AuthStorage) SaveNewRefreshToken(ctx context.Context, query string, user_id int, token models.RefreshToken) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, user_id, token.Token, token.FamilyID, token.ExpiresAt, token.UserAgent, token.IPAddress, token.SessionCreatedAt)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *AuthStorage) SaveNewRefreshToken(ctx context.Context, query string, user_id int, token models.RefreshToken) error {
-	_, err := s.pool.Exec(ctx, query, user_id, token.Token, token.ExpiresAt)
+func (s *AuthStorage) GetRefreshToken(ctx context.Context, query string, token string) (models.RefreshToken, error) {
+	var result models.RefreshToken
+	err := conn(ctx, s.pool).QueryRow(ctx, query, token).Scan(
+		&result.ID,
+		&result.UserID,
+		&result.Token,
+		&result.FamilyID,
+		&result.ExpiresAt,
+		&result.CreatedAt,
+		&result.RotatedAt,
+		&result.UserAgent,
+		&result.IPAddress,
+		&result.SessionCreatedAt,
+		&result.LastUsedAt,
+	)
 	if err != nil {
-		return err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.RefreshToken{}, nil // токен не найден
+		}
+		return models.RefreshToken{}, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return result, nil
+}
+
+func (s *AuthStorage) GetSessions(ctx context.Context, query string, userID int) ([]models.RefreshToken, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.RefreshToken
+	for rows.Next() {
+		var session models.RefreshToken
+		err := rows.Scan(
+			&session.FamilyID,
+			&session.UserAgent,
+			&session.IPAddress,
+			&session.SessionCreatedAt,
+			&session.LastUsedAt,
+			&session.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+func (s *AuthStorage) MarkRefreshTokenRotated(ctx context.Context, query string, tokenID int) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, tokenID)
+	if err != nil {
+		return fmt.Errorf("failed to mark refresh token rotated: %w", err)
 	}
 	return nil
 }
+
+func (s *AuthStorage) DeleteRefreshTokenFamily(ctx context.Context, query string, userID int, familyID string) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, userID, familyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete refresh token family: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (s *AuthStorage) TouchSession(ctx context.Context, query string, userID int, familyID string) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, userID, familyID)
+	if err != nil {
+		return fmt.Errorf("failed to update session last use: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthStorage) DeleteExpiredRefreshTokens(ctx context.Context, query string) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (s *AuthStorage) DeleteRefreshToken(ctx context.Context, query string, token string) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, token)
+	if err != nil {
+		return fmt.Errorf("failed to delete refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthStorage) RevokeToken(ctx context.Context, query string, token models.RevokedToken) (models.RevokedToken, error) {
+	var result models.RevokedToken
+	err := conn(ctx, s.pool).QueryRow(ctx, query, token.UserID, token.JTI, token.FamilyID, token.RevokedAt, token.ExpiresAt).Scan(
+		&result.ID,
+		&result.UserID,
+		&result.JTI,
+		&result.FamilyID,
+		&result.RevokedAt,
+		&result.ExpiresAt,
+	)
+	if err != nil {
+		return models.RevokedToken{}, fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return result, nil
+}
+
+func (s *AuthStorage) GetRevokedTokens(ctx context.Context, query string) ([]models.RevokedToken, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revoked tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []models.RevokedToken
+	for rows.Next() {
+		var token models.RevokedToken
+		err := rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&token.JTI,
+			&token.FamilyID,
+			&token.RevokedAt,
+			&token.ExpiresAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan revoked token: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over revoked tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *AuthStorage) GetUserTokensRevokedAt(ctx context.Context, query string, userID int) (time.Time, error) {
+	var revoked_at *time.Time
+	err := conn(ctx, s.pool).QueryRow(ctx, query, userID).Scan(&revoked_at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get user tokens revocation time: %w", err)
+	}
+	if revoked_at == nil {
+		return time.Time{}, nil
+	}
+	return *revoked_at, nil
+}
+
+func (s *AuthStorage) DeleteExpiredRevokedTokens(ctx context.Context, query string) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired revoked tokens: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BudgetAlertStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewBudgetAlertStorage(pool *pgxpool.Pool) *BudgetAlertStorage {
+	return &BudgetAlertStorage{
+		pool: pool,
+	}
+}
+
+func (s *BudgetAlertStorage) CreateThresholdAlerts(ctx context.Context, query string, userID uint, categoryID int, date time.Time) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, userID, categoryID, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create threshold alerts: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (s *BudgetAlertStorage) CreateNearEndAlerts(ctx context.Context, query string, now, until time.Time) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, now, until)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create near end alerts: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func (s *BudgetAlertStorage) GetUserAlerts(ctx context.Context, query string, userID uint, unacknowledgedOnly bool) ([]models.BudgetAlert, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, unacknowledgedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.BudgetAlert
+	for rows.Next() {
+		var alert models.BudgetAlert
+		err := rows.Scan(
+			&alert.ID,
+			&alert.UserID,
+			&alert.BudgetID,
+			&alert.CategoryID,
+			&alert.CategoryName,
+			&alert.AlertType,
+			&alert.Threshold,
+			&alert.Percentage,
+			&alert.BudgetEndDate,
+			&alert.AcknowledgedAt,
+			&alert.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over budget alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+func (s *BudgetAlertStorage) AcknowledgeAlert(ctx context.Context, query string, userID uint, alertID int) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, alertID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to acknowledge budget alert: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("alert not found or access denied")
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package storage
 import (
 	"context"
 	"finance/internal/models"
+	"finance/pkg/money"
 	"fmt"
 	"time"
 
@@ -20,14 +21,24 @@ func NewBudgetStorage(pool *pgxpool.Pool) *BudgetStorage {
 }
 
 func (s *BudgetStorage) CreateBudget(ctx context.Context, query string, budget models.Budget) (models.Budget, error) {
-	err := s.pool.QueryRow(ctx, query,
+	err := conn(ctx, s.pool).QueryRow(ctx, query,
 		budget.UserID,
 		budget.CategoryID,
 		budget.Amount,
 		budget.SpentAmount,
 		budget.Period,
 		budget.StartDate,
-		budget.EndDate).Scan(&budget.ID)
+		budget.EndDate,
+		budget.AlertThresholds,
+		budget.AutoRenew,
+		budget.CarryOver,
+		budget.CarriedAmount,
+		budget.WeekStart,
+		budget.MonthStartDay,
+		budget.YearStartMonth,
+		budget.Scope,
+		budget.Name,
+		budget.Currency).Scan(&budget.ID)
 
 	if err != nil {
 		return models.Budget{}, fmt.Errorf("failed to create budget: %w", err)
@@ -38,7 +49,7 @@ func (s *BudgetStorage) CreateBudget(ctx context.Context, query string, budget m
 
 func (s *BudgetStorage) GetBudgetByID(ctx context.Context, query string, userID uint, category_id int, budget_id int) (models.Budget, error) {
 	var budget models.Budget
-	row := s.pool.QueryRow(ctx, query, budget_id, userID, category_id)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, budget_id, userID, category_id)
 
 	err := row.Scan(
 		&budget.ID,
@@ -49,6 +60,17 @@ func (s *BudgetStorage) GetBudgetByID(ctx context.Context, query string, userID
 		&budget.Period,
 		&budget.StartDate,
 		&budget.EndDate,
+		&budget.AlertThresholds,
+		&budget.AutoRenew,
+		&budget.CarryOver,
+		&budget.CarriedAmount,
+		&budget.WeekStart,
+		&budget.MonthStartDay,
+		&budget.YearStartMonth,
+		&budget.Scope,
+		&budget.Name,
+		&budget.CategoryIDs,
+		&budget.Currency,
 	)
 
 	if err != nil {
@@ -60,7 +82,7 @@ func (s *BudgetStorage) GetBudgetByID(ctx context.Context, query string, userID
 
 func (s *BudgetStorage) GetUserBudgets(ctx context.Context, query string, category_id int, userID uint) ([]models.Budget, error) {
 	var budgets []models.Budget
-	rows, err := s.pool.Query(ctx, query, userID, category_id)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, category_id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user budgets: %w", err)
 	}
@@ -72,11 +94,23 @@ func (s *BudgetStorage) GetUserBudgets(ctx context.Context, query string, catego
 			&budget.ID,
 			&budget.UserID,
 			&budget.CategoryID,
+			&budget.CategoryName,
 			&budget.Amount,
 			&budget.SpentAmount,
 			&budget.Period,
 			&budget.StartDate,
 			&budget.EndDate,
+			&budget.AlertThresholds,
+			&budget.AutoRenew,
+			&budget.CarryOver,
+			&budget.CarriedAmount,
+			&budget.WeekStart,
+			&budget.MonthStartDay,
+			&budget.YearStartMonth,
+			&budget.Scope,
+			&budget.Name,
+			&budget.CategoryIDs,
+			&budget.Currency,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan budget: %w", err)
@@ -91,8 +125,40 @@ func (s *BudgetStorage) GetUserBudgets(ctx context.Context, query string, catego
 	return budgets, nil
 }
 
+func (s *BudgetStorage) UpdateBudget(ctx context.Context, query string, budget models.Budget) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query,
+		budget.Amount,
+		budget.SpentAmount,
+		budget.Period,
+		budget.StartDate,
+		budget.EndDate,
+		budget.ID,
+		budget.UserID,
+		budget.CategoryID,
+		budget.AlertThresholds,
+		budget.AutoRenew,
+		budget.CarryOver,
+		budget.CarriedAmount,
+		budget.WeekStart,
+		budget.MonthStartDay,
+		budget.YearStartMonth,
+		budget.Scope,
+		budget.Name,
+		budget.Currency,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update budget: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("budget not found or access denied")
+	}
+
+	return nil
+}
+
 func (s *BudgetStorage) DeleteBudget(ctx context.Context, query string, userID uint, category_id int, budget_id int) error {
-	result, err := s.pool.Exec(ctx, query, budget_id, userID, category_id)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, budget_id, userID, category_id)
 	if err != nil {
 		return fmt.Errorf("failed to delete budget: %w", err)
 	}
@@ -105,7 +171,7 @@ func (s *BudgetStorage) DeleteBudget(ctx context.Context, query string, userID u
 }
 
 func (s *BudgetStorage) DeleteBudgetsInCategory(ctx context.Context, query string, userID uint, categoryID int) error {
-	_, err := s.pool.Exec(ctx, query, userID, categoryID)
+	_, err := conn(ctx, s.pool).Exec(ctx, query, userID, categoryID)
 	if err != nil {
 		return fmt.Errorf("failed to delete budgets in category: %w", err)
 	}
@@ -113,8 +179,8 @@ func (s *BudgetStorage) DeleteBudgetsInCategory(ctx context.Context, query strin
 	return nil
 }
 
-func (s *BudgetStorage) UpdateSpentAmount(ctx context.Context, query string, category_id int, budgetID uint, spentAmount float64) error {
-	result, err := s.pool.Exec(ctx, query, spentAmount, budgetID, category_id)
+func (s *BudgetStorage) UpdateSpentAmount(ctx context.Context, query string, category_id int, budgetID uint, spentAmount money.Amount) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, spentAmount, budgetID, category_id)
 	if err != nil {
 		return fmt.Errorf("failed to update spent amount: %w", err)
 	}
@@ -126,9 +192,43 @@ func (s *BudgetStorage) UpdateSpentAmount(ctx context.Context, query string, cat
 	return nil
 }
 
+func (s *BudgetStorage) AdjustSpentAmount(ctx context.Context, query string, userID uint, categoryID int, date time.Time, currency string, delta, baseDelta money.Amount) ([]string, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, delta, userID, categoryID, date, currency, baseDelta)
+	if err != nil {
+		return nil, fmt.Errorf("failed to adjust spent amount: %w", err)
+	}
+	defer rows.Close()
+
+	// Валюты бюджетов, в которые сумму расхода пересчитать не удалось
+	var missing []string
+	for rows.Next() {
+		var currency string
+		if err := rows.Scan(&currency); err != nil {
+			return nil, fmt.Errorf("failed to scan budget currency: %w", err)
+		}
+		missing = append(missing, currency)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to adjust spent amount: %w", err)
+	}
+
+	return missing, nil
+}
+
+func (s *BudgetStorage) RecalculateSpentAmounts(ctx context.Context, query string, userID uint) (int64, int64, error) {
+	var updated, skipped int64
+	err := conn(ctx, s.pool).QueryRow(ctx, query, userID).Scan(&updated, &skipped)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to recalculate spent amounts: %w", err)
+	}
+
+	return updated, skipped, nil
+}
+
 func (s *BudgetStorage) GetActiveBudgetsByCategoryAndDate(ctx context.Context, query string, userID uint, categoryID int, date time.Time) ([]models.Budget, error) {
 	var budgets []models.Budget
-	rows, err := s.pool.Query(ctx, query, userID, categoryID, date)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, categoryID, date)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active budgets by category and date: %w", err)
 	}
@@ -145,6 +245,17 @@ func (s *BudgetStorage) GetActiveBudgetsByCategoryAndDate(ctx context.Context, q
 			&budget.Period,
 			&budget.StartDate,
 			&budget.EndDate,
+			&budget.AlertThresholds,
+			&budget.AutoRenew,
+			&budget.CarryOver,
+			&budget.CarriedAmount,
+			&budget.WeekStart,
+			&budget.MonthStartDay,
+			&budget.YearStartMonth,
+			&budget.Scope,
+			&budget.Name,
+			&budget.CategoryIDs,
+			&budget.Currency,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan budget: %w", err)
@@ -158,3 +269,152 @@ func (s *BudgetStorage) GetActiveBudgetsByCategoryAndDate(ctx context.Context, q
 
 	return budgets, nil
 }
+
+func (s *BudgetStorage) SetBudgetCategories(ctx context.Context, deleteQuery, insertQuery string, userID uint, budgetID uint, categoryIDs []uint) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, deleteQuery, budgetID)
+	if err != nil {
+		return fmt.Errorf("failed to clear budget categories: %w", err)
+	}
+
+	result, err := conn(ctx, s.pool).Exec(ctx, insertQuery, budgetID, userID, categoryIDs)
+	if err != nil {
+		return fmt.Errorf("failed to set budget categories: %w", err)
+	}
+
+	if result.RowsAffected() != int64(len(categoryIDs)) {
+		return fmt.Errorf("category not found or access denied")
+	}
+
+	return nil
+}
+
+func (s *BudgetStorage) GetBudgetSpentAmount(ctx context.Context, query string, budgetID uint, start, end time.Time, currency string) (money.Amount, int64, error) {
+	var spent money.Amount
+	var missing int64
+	err := conn(ctx, s.pool).QueryRow(ctx, query, budgetID, start, end, currency).Scan(&spent, &missing)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get budget spent amount: %w", err)
+	}
+	return spent, missing, nil
+}
+
+func (s *BudgetStorage) GetDueBudgetIDs(ctx context.Context, query string, now time.Time) ([]uint, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due budgets: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan budget id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over due budgets: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (s *BudgetStorage) LockBudget(ctx context.Context, query string, id uint) (models.Budget, error) {
+	var budget models.Budget
+	err := conn(ctx, s.pool).QueryRow(ctx, query, id).Scan(
+		&budget.ID,
+		&budget.UserID,
+		&budget.CategoryID,
+		&budget.Amount,
+		&budget.SpentAmount,
+		&budget.Period,
+		&budget.StartDate,
+		&budget.EndDate,
+		&budget.AlertThresholds,
+		&budget.AutoRenew,
+		&budget.CarryOver,
+		&budget.CarriedAmount,
+		&budget.WeekStart,
+		&budget.MonthStartDay,
+		&budget.YearStartMonth,
+		&budget.Scope,
+		&budget.Name,
+		&budget.CategoryIDs,
+		&budget.Currency,
+	)
+	if err != nil {
+		return models.Budget{}, fmt.Errorf("failed to lock budget: %w", err)
+	}
+	return budget, nil
+}
+
+func (s *BudgetStorage) HasOverlappingBudget(ctx context.Context, query string, budget models.Budget) (bool, error) {
+	var exists bool
+	err := conn(ctx, s.pool).QueryRow(ctx, query,
+		budget.UserID,
+		budget.CategoryID,
+		budget.Period,
+		budget.StartDate,
+		budget.EndDate,
+		budget.ID,
+		budget.Scope,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check overlapping budgets: %w", err)
+	}
+	return exists, nil
+}
+
+func (s *BudgetStorage) ArchiveBudgetPeriod(ctx context.Context, query string, budget models.Budget) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query,
+		budget.ID,
+		budget.UserID,
+		budget.Period,
+		budget.StartDate,
+		budget.EndDate,
+		budget.Amount,
+		budget.CarriedAmount,
+		budget.SpentAmount,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to archive budget period: %w", err)
+	}
+	return nil
+}
+
+func (s *BudgetStorage) GetBudgetHistory(ctx context.Context, query string, userID uint, categoryID int, budgetID int) ([]models.BudgetPeriod, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, budgetID, userID, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get budget history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.BudgetPeriod
+	for rows.Next() {
+		var period models.BudgetPeriod
+		err := rows.Scan(
+			&period.ID,
+			&period.BudgetID,
+			&period.UserID,
+			&period.Period,
+			&period.StartDate,
+			&period.EndDate,
+			&period.Amount,
+			&period.CarriedAmount,
+			&period.SpentAmount,
+			&period.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan budget period: %w", err)
+		}
+		history = append(history, period)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over budget history: %w", err)
+	}
+
+	return history, nil
+}
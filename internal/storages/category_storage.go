@@ -3,7 +3,9 @@ package storage
 import (
 	"context"
 	"finance/internal/models"
+	"finance/pkg/money"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -21,7 +23,7 @@ func NewCategoryStorage(pool *pgxpool.Pool) *CategoryStorage {
 
 func (c *CategoryStorage) CreateCategory(ctx context.Context, query string, category models.Category) (models.Category, error) {
 	var newCategory models.Category
-	err := c.pool.QueryRow(ctx, query, category.UserID, category.Name).Scan(
+	err := conn(ctx, c.pool).QueryRow(ctx, query, category.UserID, category.Name).Scan(
 		&newCategory.ID,
 		&newCategory.Name,
 		&newCategory.CreatedAt,
@@ -35,7 +37,7 @@ func (c *CategoryStorage) CreateCategory(ctx context.Context, query string, cate
 func (c *CategoryStorage) GetCategoryByID(ctx context.Context, query string, userID uint, categoryID int) (models.Category, error) {
 	var category models.Category
 
-	err := c.pool.QueryRow(ctx, query, categoryID, userID).Scan(
+	err := conn(ctx, c.pool).QueryRow(ctx, query, categoryID, userID).Scan(
 		&category.ID,
 		&category.Name,
 		&category.CreatedAt,
@@ -52,7 +54,7 @@ func (c *CategoryStorage) GetCategoryByID(ctx context.Context, query string, use
 }
 
 func (c *CategoryStorage) GetCategories(ctx context.Context, query string, userID uint) ([]models.Category, error) {
-	rows, err := c.pool.Query(ctx, query, userID)
+	rows, err := conn(ctx, c.pool).Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get categories: %w", err)
 	}
@@ -80,7 +82,7 @@ func (c *CategoryStorage) GetCategories(ctx context.Context, query string, userI
 }
 
 func (c *CategoryStorage) DeleteCategory(ctx context.Context, query string, userID uint, categoryID int) error {
-	result, err := c.pool.Exec(ctx, query, categoryID, userID)
+	result, err := conn(ctx, c.pool).Exec(ctx, query, categoryID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
@@ -93,7 +95,7 @@ func (c *CategoryStorage) DeleteCategory(ctx context.Context, query string, user
 }
 
 func (c *CategoryStorage) GetMostUsedCategories(ctx context.Context, query string, userID uint) ([]models.Category, error) {
-	rows, err := c.pool.Query(ctx, query, userID)
+	rows, err := conn(ctx, c.pool).Query(ctx, query, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get most used categories: %w", err)
 	}
@@ -118,9 +120,9 @@ func (c *CategoryStorage) GetMostUsedCategories(ctx context.Context, query strin
 	return categories, nil
 }
 
-func (c *CategoryStorage) GetTotalAmountInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (float64, error) {
-	var total float64
-	err := c.pool.QueryRow(ctx, query, userID, categoryID).Scan(&total)
+func (c *CategoryStorage) GetTotalAmountInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (money.Amount, error) {
+	var total money.Amount
+	err := conn(ctx, c.pool).QueryRow(ctx, query, userID, categoryID, from, to).Scan(&total)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get total amount: %w", err)
 	}
@@ -128,14 +130,16 @@ func (c *CategoryStorage) GetTotalAmountInCategory(ctx context.Context, query st
 	return total, nil
 }
 
-func (c *CategoryStorage) GetLargestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error) {
+func (c *CategoryStorage) GetLargestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error) {
 	var expense models.Expense
-	err := c.pool.QueryRow(ctx, query, userID, categoryID).Scan(
+	err := conn(ctx, c.pool).QueryRow(ctx, query, userID, categoryID, from, to).Scan(
 		&expense.ID,
 		&expense.UserID,
 		&expense.CategoryID,
 		&expense.CategoryName,
 		&expense.Amount,
+		&expense.Currency,
+		&expense.BaseAmount,
 		&expense.Description,
 		&expense.Date,
 		&expense.CreatedAt,
@@ -150,14 +154,16 @@ func (c *CategoryStorage) GetLargestExpenseInCategory(ctx context.Context, query
 	return expense, nil
 }
 
-func (c *CategoryStorage) GetSmallestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error) {
+func (c *CategoryStorage) GetSmallestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error) {
 	var expense models.Expense
-	err := c.pool.QueryRow(ctx, query, userID, categoryID).Scan(
+	err := conn(ctx, c.pool).QueryRow(ctx, query, userID, categoryID, from, to).Scan(
 		&expense.ID,
 		&expense.UserID,
 		&expense.CategoryID,
 		&expense.CategoryName,
 		&expense.Amount,
+		&expense.Currency,
+		&expense.BaseAmount,
 		&expense.Description,
 		&expense.Date,
 		&expense.CreatedAt,
@@ -172,9 +178,9 @@ func (c *CategoryStorage) GetSmallestExpenseInCategory(ctx context.Context, quer
 	return expense, nil
 }
 
-func (c *CategoryStorage) GetExpenseCountInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (int, error) {
+func (c *CategoryStorage) GetExpenseCountInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (int, error) {
 	var count int
-	err := c.pool.QueryRow(ctx, query, userID, categoryID).Scan(&count)
+	err := conn(ctx, c.pool).QueryRow(ctx, query, userID, categoryID, from, to).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get expense count: %w", err)
 	}
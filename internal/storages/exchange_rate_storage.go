@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ExchangeRateStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewExchangeRateStorage(pool *pgxpool.Pool) *ExchangeRateStorage {
+	return &ExchangeRateStorage{
+		pool: pool,
+	}
+}
+
+func (s *ExchangeRateStorage) UpsertRates(ctx context.Context, query string, rates []models.ExchangeRate) (int64, error) {
+	var saved int64
+	for _, rate := range rates {
+		result, err := conn(ctx, s.pool).Exec(ctx, query, rate.BaseCurrency, rate.QuoteCurrency, rate.Rate, rate.Date)
+		if err != nil {
+			return 0, fmt.Errorf("failed to save exchange rate %s/%s: %w", rate.BaseCurrency, rate.QuoteCurrency, err)
+		}
+		saved += result.RowsAffected()
+	}
+	return saved, nil
+}
+
+func (s *ExchangeRateStorage) GetRateToBase(ctx context.Context, query string, userID uint, currency string, date time.Time) (string, *float64, error) {
+	var baseCurrency string
+	var rate *float64
+	err := conn(ctx, s.pool).QueryRow(ctx, query, userID, currency, date).Scan(&baseCurrency, &rate)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+	return baseCurrency, rate, nil
+}
+
+func (s *ExchangeRateStorage) CountExpensesWithoutRate(ctx context.Context, query string, userID uint, currency string) (int, error) {
+	var count int
+	err := conn(ctx, s.pool).QueryRow(ctx, query, userID, currency).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count expenses without exchange rate: %w", err)
+	}
+	return count, nil
+}
+
+func (s *ExchangeRateStorage) RecalculateBaseAmounts(ctx context.Context, query string, userID uint, from time.Time) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, userID, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recalculate base amounts: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (s *ExchangeRateStorage) CountIncomesWithoutRate(ctx context.Context, query string, userID uint, currency string) (int, error) {
+	var count int
+	err := conn(ctx, s.pool).QueryRow(ctx, query, userID, currency).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count incomes without exchange rate: %w", err)
+	}
+	return count, nil
+}
+
+func (s *ExchangeRateStorage) RecalculateIncomeBaseAmounts(ctx context.Context, query string, userID uint, from time.Time) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, userID, from)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recalculate income base amounts: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
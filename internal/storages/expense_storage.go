@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -21,16 +22,27 @@ func NewExpenseStorage(pool *pgxpool.Pool) *ExpenseStorage {
 
 func (s *ExpenseStorage) CreateExpense(ctx context.Context, query string, expense models.Expense) (models.Expense, error) {
 	var new_expense models.Expense
-	err := s.pool.QueryRow(ctx, query, expense.UserID, expense.CategoryID, expense.Amount, expense.Description, expense.Date, expense.CreatedAt).Scan(&new_expense.ID, &new_expense.CategoryID, &new_expense.Amount, &new_expense.Description, &new_expense.Date, &new_expense.CreatedAt)
+	err := conn(ctx, s.pool).QueryRow(ctx, query, expense.UserID, expense.CategoryID, expense.Amount, expense.Description, expense.Date, expense.CreatedAt, expense.Currency, expense.BaseAmount).Scan(
+		&new_expense.ID,
+		&new_expense.UserID,
+		&new_expense.CategoryID,
+		&new_expense.CategoryName,
+		&new_expense.Amount,
+		&new_expense.Currency,
+		&new_expense.BaseAmount,
+		&new_expense.Description,
+		&new_expense.Date,
+		&new_expense.CreatedAt,
+	)
 	if err != nil {
-		return models.Expense{}, err
+		return models.Expense{}, fmt.Errorf("failed to create expense: %w", err)
 	}
-	return expense, nil
+	return new_expense, nil
 }
 
 func (s *ExpenseStorage) GetExpenseByID(ctx context.Context, query string, userID uint, categoryID int, id uint) (models.Expense, error) {
 	var expense models.Expense
-	row := s.pool.QueryRow(ctx, query, id, userID, categoryID)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, id, userID, categoryID)
 
 	err := row.Scan(
 		&expense.ID,
@@ -38,9 +50,12 @@ func (s *ExpenseStorage) GetExpenseByID(ctx context.Context, query string, userI
 		&expense.CategoryID,
 		&expense.CategoryName,
 		&expense.Amount,
+		&expense.Currency,
+		&expense.BaseAmount,
 		&expense.Description,
 		&expense.Date,
 		&expense.CreatedAt,
+		&expense.Tags,
 	)
 
 	if err != nil {
@@ -52,7 +67,7 @@ func (s *ExpenseStorage) GetExpenseByID(ctx context.Context, query string, userI
 
 func (s *ExpenseStorage) GetExpensesByUserID(ctx context.Context, query string, categoryID int, userID uint) ([]models.Expense, error) {
 	var expenses []models.Expense
-	rows, err := s.pool.Query(ctx, query, userID, categoryID)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, categoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expenses by user id: %w", err)
 	}
@@ -66,9 +81,47 @@ func (s *ExpenseStorage) GetExpensesByUserID(ctx context.Context, query string,
 			&expense.CategoryID,
 			&expense.CategoryName,
 			&expense.Amount,
+			&expense.Currency,
+			&expense.BaseAmount,
+			&expense.Description,
+			&expense.Date,
+			&expense.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expense: %w", err)
+		}
+		expenses = append(expenses, expense)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+func (s *ExpenseStorage) GetExpensesByFilter(ctx context.Context, query string, args ...any) ([]models.Expense, error) {
+	var expenses []models.Expense
+	rows, err := conn(ctx, s.pool).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses by filter: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var expense models.Expense
+		err := rows.Scan(
+			&expense.ID,
+			&expense.UserID,
+			&expense.CategoryID,
+			&expense.CategoryName,
+			&expense.Amount,
+			&expense.Currency,
+			&expense.BaseAmount,
 			&expense.Description,
 			&expense.Date,
 			&expense.CreatedAt,
+			&expense.Tags,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan expense: %w", err)
@@ -83,9 +136,9 @@ func (s *ExpenseStorage) GetExpensesByUserID(ctx context.Context, query string,
 	return expenses, nil
 }
 
-func (s *ExpenseStorage) GetExpensesByPeriod(ctx context.Context, query string, userID uint, categoryID int, period string) ([]models.Expense, error) {
+func (s *ExpenseStorage) GetExpensesByPeriod(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) ([]models.Expense, error) {
 	var expenses []models.Expense
-	rows, err := s.pool.Query(ctx, query, userID, categoryID)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, categoryID, from, to)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expenses by period: %w", err)
 	}
@@ -99,6 +152,8 @@ func (s *ExpenseStorage) GetExpensesByPeriod(ctx context.Context, query string,
 			&expense.CategoryID,
 			&expense.CategoryName,
 			&expense.Amount,
+			&expense.Currency,
+			&expense.BaseAmount,
 			&expense.Description,
 			&expense.Date,
 			&expense.CreatedAt,
@@ -116,8 +171,40 @@ func (s *ExpenseStorage) GetExpensesByPeriod(ctx context.Context, query string,
 	return expenses, nil
 }
 
+func (s *ExpenseStorage) UpdateExpense(ctx context.Context, query string, expense models.Expense) (models.Expense, error) {
+	var updated models.Expense
+	err := conn(ctx, s.pool).QueryRow(ctx, query,
+		expense.CategoryID,
+		expense.Amount,
+		expense.Description,
+		expense.Date,
+		expense.ID,
+		expense.UserID,
+		expense.Currency,
+		expense.BaseAmount,
+	).Scan(
+		&updated.ID,
+		&updated.UserID,
+		&updated.CategoryID,
+		&updated.CategoryName,
+		&updated.Amount,
+		&updated.Currency,
+		&updated.BaseAmount,
+		&updated.Description,
+		&updated.Date,
+		&updated.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Expense{}, fmt.Errorf("expense not found or category not owned by user")
+		}
+		return models.Expense{}, fmt.Errorf("failed to update expense: %w", err)
+	}
+	return updated, nil
+}
+
 func (s *ExpenseStorage) DeleteExpense(ctx context.Context, query string, userID uint, categoryID int, id uint) error {
-	result, err := s.pool.Exec(ctx, query, id, userID, categoryID)
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id, userID, categoryID)
 	if err != nil {
 		return fmt.Errorf("failed to delete expense: %w", err)
 	}
@@ -130,7 +217,7 @@ func (s *ExpenseStorage) DeleteExpense(ctx context.Context, query string, userID
 }
 
 func (s *ExpenseStorage) DeleteExpensesInCategory(ctx context.Context, query string, userID uint, categoryID int) error {
-	_, err := s.pool.Exec(ctx, query, userID, categoryID)
+	_, err := conn(ctx, s.pool).Exec(ctx, query, userID, categoryID)
 	if err != nil {
 		return fmt.Errorf("failed to delete expenses in category: %w", err)
 	}
@@ -140,7 +227,7 @@ func (s *ExpenseStorage) DeleteExpensesInCategory(ctx context.Context, query str
 
 func (s *ExpenseStorage) GetExpensesByCategory(ctx context.Context, query string, userID uint, categoryID int) ([]models.Expense, error) {
 	var expenses []models.Expense
-	rows, err := s.pool.Query(ctx, query, userID, categoryID)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, categoryID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expenses by category: %w", err)
 	}
@@ -154,6 +241,8 @@ func (s *ExpenseStorage) GetExpensesByCategory(ctx context.Context, query string
 			&expense.CategoryID,
 			&expense.CategoryName,
 			&expense.Amount,
+			&expense.Currency,
+			&expense.BaseAmount,
 			&expense.Description,
 			&expense.Date,
 			&expense.CreatedAt,
@@ -171,9 +260,9 @@ func (s *ExpenseStorage) GetExpensesByCategory(ctx context.Context, query string
 	return expenses, nil
 }
 
-func (s *ExpenseStorage) GetLargestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error) {
+func (s *ExpenseStorage) GetLargestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error) {
 	var expense models.Expense
-	row := s.pool.QueryRow(ctx, query, userID, categoryID)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, userID, categoryID, from, to)
 
 	err := row.Scan(
 		&expense.ID,
@@ -181,21 +270,26 @@ func (s *ExpenseStorage) GetLargestExpenseByPeriod(ctx context.Context, query st
 		&expense.CategoryID,
 		&expense.CategoryName,
 		&expense.Amount,
+		&expense.Currency,
+		&expense.BaseAmount,
 		&expense.Description,
 		&expense.Date,
 		&expense.CreatedAt,
 	)
 
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Expense{}, nil
+		}
 		return models.Expense{}, fmt.Errorf("failed to get largest expense: %w", err)
 	}
 
 	return expense, nil
 }
 
-func (s *ExpenseStorage) GetSmallestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error) {
+func (s *ExpenseStorage) GetSmallestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error) {
 	var expense models.Expense
-	row := s.pool.QueryRow(ctx, query, userID, categoryID)
+	row := conn(ctx, s.pool).QueryRow(ctx, query, userID, categoryID, from, to)
 
 	err := row.Scan(
 		&expense.ID,
@@ -203,12 +297,17 @@ func (s *ExpenseStorage) GetSmallestExpenseByPeriod(ctx context.Context, query s
 		&expense.CategoryID,
 		&expense.CategoryName,
 		&expense.Amount,
+		&expense.Currency,
+		&expense.BaseAmount,
 		&expense.Description,
 		&expense.Date,
 		&expense.CreatedAt,
 	)
 
 	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.Expense{}, nil
+		}
 		return models.Expense{}, fmt.Errorf("failed to get smallest expense: %w", err)
 	}
 
@@ -217,7 +316,7 @@ func (s *ExpenseStorage) GetSmallestExpenseByPeriod(ctx context.Context, query s
 
 func (s *ExpenseStorage) GetExpensesByCategoryAndPeriod(ctx context.Context, query string, userID uint, categoryID int, startDate, endDate time.Time) ([]models.Expense, error) {
 	var expenses []models.Expense
-	rows, err := s.pool.Query(ctx, query, userID, categoryID, startDate, endDate)
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, categoryID, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get expenses by category and period: %w", err)
 	}
@@ -231,6 +330,8 @@ func (s *ExpenseStorage) GetExpensesByCategoryAndPeriod(ctx context.Context, que
 			&expense.CategoryID,
 			&expense.CategoryName,
 			&expense.Amount,
+			&expense.Currency,
+			&expense.BaseAmount,
 			&expense.Description,
 			&expense.Date,
 			&expense.CreatedAt,
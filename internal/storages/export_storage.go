@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ExportStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewExportStorage(pool *pgxpool.Pool) *ExportStorage {
+	return &ExportStorage{
+		pool: pool,
+	}
+}
+
+// Методы Stream* передают строки в fn по мере чтения из базы и не собирают их в срез.
+// Ошибка fn прекращает чтение и возвращается как есть
+
+func (s *ExportStorage) StreamCategories(ctx context.Context, query string, userID uint, fn func(models.Category) error) error {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get categories for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category models.Category
+		if err := rows.Scan(&category.ID, &category.Name, &category.CreatedAt); err != nil {
+			return fmt.Errorf("failed to scan category: %w", err)
+		}
+		if err := fn(category); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over categories: %w", err)
+	}
+	return nil
+}
+
+func (s *ExportStorage) StreamExpenses(ctx context.Context, query string, userID uint, from, to *time.Time, fn func(models.Expense) error) error {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get expenses for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var expense models.Expense
+		err := rows.Scan(
+			&expense.ID,
+			&expense.CategoryID,
+			&expense.CategoryName,
+			&expense.Amount,
+			&expense.Currency,
+			&expense.BaseAmount,
+			&expense.Description,
+			&expense.Date,
+			&expense.CreatedAt,
+			&expense.Tags,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan expense: %w", err)
+		}
+		if err := fn(expense); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over expenses: %w", err)
+	}
+	return nil
+}
+
+func (s *ExportStorage) StreamBudgets(ctx context.Context, query string, userID uint, from, to *time.Time, fn func(models.Budget) error) error {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get budgets for export: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var budget models.Budget
+		err := rows.Scan(
+			&budget.ID,
+			&budget.CategoryID,
+			&budget.CategoryName,
+			&budget.Amount,
+			&budget.SpentAmount,
+			&budget.CarriedAmount,
+			&budget.Currency,
+			&budget.Period,
+			&budget.StartDate,
+			&budget.EndDate,
+			&budget.Scope,
+			&budget.Name,
+			&budget.CategoryIDs,
+			&budget.AutoRenew,
+			&budget.CarryOver,
+			&budget.AlertThresholds,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to scan budget: %w", err)
+		}
+		if err := fn(budget); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating over budgets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ImportStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewImportStorage(pool *pgxpool.Pool) *ImportStorage {
+	return &ImportStorage{
+		pool: pool,
+	}
+}
+
+func (s *ImportStorage) CreateBatch(ctx context.Context, query string, batch models.ImportBatch) (models.ImportBatch, error) {
+	row := conn(ctx, s.pool).QueryRow(ctx, query, batch.UserID, batch.FileName, batch.Format, batch.RowsCount, batch.SkippedCount)
+	new_batch, err := scanImportBatch(row)
+	if err != nil {
+		return models.ImportBatch{}, fmt.Errorf("failed to create import batch: %w", err)
+	}
+	return new_batch, nil
+}
+
+func (s *ImportStorage) AddRows(ctx context.Context, query string, batchID uint, rows []models.ImportRow) (int64, error) {
+	var added int64
+	for _, row := range rows {
+		result, err := conn(ctx, s.pool).Exec(ctx, query, batchID, row.RowNumber, row.Date, row.Amount, row.Currency, row.Description, row.CategoryID, row.RuleID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to add import row %d: %w", row.RowNumber, err)
+		}
+		added += result.RowsAffected()
+	}
+	return added, nil
+}
+
+func (s *ImportStorage) MarkDuplicates(ctx context.Context, query string, userID uint, batchID uint) (int64, error) {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, userID, batchID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark duplicate import rows: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
+
+func (s *ImportStorage) GetBatch(ctx context.Context, query string, userID uint, batchID int) (models.ImportBatch, error) {
+	batch, err := scanImportBatch(conn(ctx, s.pool).QueryRow(ctx, query, batchID, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return models.ImportBatch{}, fmt.Errorf("import not found or access denied")
+		}
+		return models.ImportBatch{}, fmt.Errorf("failed to get import batch: %w", err)
+	}
+	return batch, nil
+}
+
+func (s *ImportStorage) GetBatches(ctx context.Context, query string, userID uint) ([]models.ImportBatch, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import batches: %w", err)
+	}
+	defer rows.Close()
+
+	var batches []models.ImportBatch
+	for rows.Next() {
+		batch, err := scanImportBatch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import batch: %w", err)
+		}
+		batches = append(batches, batch)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over import batches: %w", err)
+	}
+
+	return batches, nil
+}
+
+func (s *ImportStorage) GetRows(ctx context.Context, query string, batchID uint) ([]models.ImportRow, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get import rows: %w", err)
+	}
+	defer rows.Close()
+
+	var import_rows []models.ImportRow
+	for rows.Next() {
+		var row models.ImportRow
+		err := rows.Scan(
+			&row.BatchID,
+			&row.RowNumber,
+			&row.Date,
+			&row.Amount,
+			&row.Currency,
+			&row.Description,
+			&row.CategoryID,
+			&row.CategoryName,
+			&row.RuleID,
+			&row.DuplicateExpenseID,
+			&row.ExpenseID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+		import_rows = append(import_rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over import rows: %w", err)
+	}
+
+	return import_rows, nil
+}
+
+func (s *ImportStorage) SetRowExpense(ctx context.Context, query string, batchID uint, rowNumber int, categoryID uint, expenseID uint) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, batchID, rowNumber, categoryID, expenseID)
+	if err != nil {
+		return fmt.Errorf("failed to link import row %d to expense: %w", rowNumber, err)
+	}
+	return nil
+}
+
+func (s *ImportStorage) GetBatchExpenses(ctx context.Context, query string, batchID uint) ([]models.Expense, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, batchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get imported expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var expenses []models.Expense
+	for rows.Next() {
+		var expense models.Expense
+		if err := rows.Scan(&expense.ID, &expense.CategoryID); err != nil {
+			return nil, fmt.Errorf("failed to scan imported expense: %w", err)
+		}
+		expenses = append(expenses, expense)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over imported expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+func (s *ImportStorage) UpdateBatchStatus(ctx context.Context, query string, userID uint, batchID uint) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, batchID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update import batch status: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("import not found or its status has already changed")
+	}
+
+	return nil
+}
+
+func (s *ImportStorage) DeleteBatch(ctx context.Context, query string, userID uint, batchID uint) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, batchID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete import batch: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("import not found or access denied")
+	}
+
+	return nil
+}
+
+func scanImportBatch(row pgx.Row) (models.ImportBatch, error) {
+	var batch models.ImportBatch
+	err := row.Scan(
+		&batch.ID,
+		&batch.UserID,
+		&batch.FileName,
+		&batch.Format,
+		&batch.Status,
+		&batch.RowsCount,
+		&batch.SkippedCount,
+		&batch.ImportedCount,
+		&batch.CreatedAt,
+		&batch.CommittedAt,
+		&batch.UndoneAt,
+	)
+	return batch, err
+}
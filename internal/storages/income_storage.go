@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IncomeStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewIncomeStorage(pool *pgxpool.Pool) *IncomeStorage {
+	return &IncomeStorage{
+		pool: pool,
+	}
+}
+
+func (s *IncomeStorage) CreateIncome(ctx context.Context, query string, income models.Income) (models.Income, error) {
+	var new_income models.Income
+	err := conn(ctx, s.pool).QueryRow(ctx, query, income.UserID, income.Source, income.Amount, income.Description, income.Date, income.Currency, income.BaseAmount).Scan(
+		&new_income.ID,
+		&new_income.UserID,
+		&new_income.Source,
+		&new_income.Amount,
+		&new_income.Description,
+		&new_income.Date,
+		&new_income.CreatedAt,
+		&new_income.Currency,
+		&new_income.BaseAmount,
+	)
+	if err != nil {
+		return models.Income{}, fmt.Errorf("failed to create income: %w", err)
+	}
+	return new_income, nil
+}
+
+func (s *IncomeStorage) GetIncomeByID(ctx context.Context, query string, userID uint, id int) (models.Income, error) {
+	var income models.Income
+	err := conn(ctx, s.pool).QueryRow(ctx, query, id, userID).Scan(
+		&income.ID,
+		&income.UserID,
+		&income.Source,
+		&income.Amount,
+		&income.Description,
+		&income.Date,
+		&income.CreatedAt,
+		&income.Currency,
+		&income.BaseAmount,
+	)
+	if err != nil {
+		return models.Income{}, fmt.Errorf("failed to get income by id: %w", err)
+	}
+	return income, nil
+}
+
+func (s *IncomeStorage) GetIncomes(ctx context.Context, query string, userID uint) ([]models.Income, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incomes: %w", err)
+	}
+	defer rows.Close()
+
+	var incomes []models.Income
+	for rows.Next() {
+		var income models.Income
+		err := rows.Scan(
+			&income.ID,
+			&income.UserID,
+			&income.Source,
+			&income.Amount,
+			&income.Description,
+			&income.Date,
+			&income.CreatedAt,
+			&income.Currency,
+			&income.BaseAmount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan income: %w", err)
+		}
+		incomes = append(incomes, income)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over incomes: %w", err)
+	}
+
+	return incomes, nil
+}
+
+func (s *IncomeStorage) UpdateIncome(ctx context.Context, query string, income models.Income) (models.Income, error) {
+	var updated models.Income
+	err := conn(ctx, s.pool).QueryRow(ctx, query,
+		income.Source,
+		income.Amount,
+		income.Description,
+		income.Date,
+		income.Currency,
+		income.BaseAmount,
+		income.ID,
+		income.UserID,
+	).Scan(
+		&updated.ID,
+		&updated.UserID,
+		&updated.Source,
+		&updated.Amount,
+		&updated.Description,
+		&updated.Date,
+		&updated.CreatedAt,
+		&updated.Currency,
+		&updated.BaseAmount,
+	)
+	if err != nil {
+		return models.Income{}, fmt.Errorf("failed to update income: %w", err)
+	}
+	return updated, nil
+}
+
+func (s *IncomeStorage) DeleteIncome(ctx context.Context, query string, userID uint, id int) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete income: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("income not found or access denied")
+	}
+
+	return nil
+}
+
+func (s *IncomeStorage) GetCashFlow(ctx context.Context, query string, userID uint, from, to time.Time, granularity string, step string) ([]models.CashFlowBucket, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, granularity, from, to, step)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cash flow: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.CashFlowBucket
+	for rows.Next() {
+		var bucket models.CashFlowBucket
+		err := rows.Scan(
+			&bucket.Period,
+			&bucket.Income,
+			&bucket.Expenses,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cash flow bucket: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over cash flow: %w", err)
+	}
+
+	return buckets, nil
+}
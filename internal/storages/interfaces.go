@@ -3,26 +3,67 @@ package storage
 import (
 	"context"
 	"finance/internal/models"
+	"finance/pkg/money"
 	"time"
 )
 
+// TransactorInterface выполняет операции нескольких хранилищ в одной транзакции
+type TransactorInterface interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	WithinSnapshot(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type AnalyticsStorageInterface interface {
+	GetExpenseTimeSeries(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time, granularity string) ([]models.ExpenseBucket, error)
+	GetCategoryPeriodTotals(ctx context.Context, query string, userID uint, categoryID int, current, previous, lastYear models.TimeRange) ([]models.CategoryPeriodTotals, error)
+	GetCategoryShares(ctx context.Context, query string, userID uint, from, to time.Time, minShare float64) ([]models.CategoryShare, error)
+}
+
 type AuthStorageInterface interface {
 	CreateUser(ctx context.Context, query string, first_name string, last_name string, email string, password string, timeOfRegistration time.Time) (models.User, error)
-	CheckUserVerification(ctx context.Context, query string, email string, hashpassword string) (models.User, error)
+	GetUserByEmail(ctx context.Context, query string, email string) (models.User, error)
+	UpdatePasswordHash(ctx context.Context, query string, userID int, hashpassword string) error
 	UserExistsByEmail(ctx context.Context, query string, email string) (bool, error)
-	GetUserIDbyRefreshToken(ctx context.Context, query string, refreshToken string) (int, error)
 	RemoveOldRefreshToken(ctx context.Context, query string, userID int) error
 	SaveNewRefreshToken(ctx context.Context, query string, user_id int, token models.RefreshToken) error
+	GetRefreshToken(ctx context.Context, query string, token string) (models.RefreshToken, error)
+	GetSessions(ctx context.Context, query string, userID int) ([]models.RefreshToken, error)
+	MarkRefreshTokenRotated(ctx context.Context, query string, tokenID int) error
+	DeleteRefreshToken(ctx context.Context, query string, token string) error
+	DeleteRefreshTokenFamily(ctx context.Context, query string, userID int, familyID string) (int64, error)
+	TouchSession(ctx context.Context, query string, userID int, familyID string) error
+	DeleteExpiredRefreshTokens(ctx context.Context, query string) (int64, error)
+	RevokeToken(ctx context.Context, query string, token models.RevokedToken) (models.RevokedToken, error)
+	GetRevokedTokens(ctx context.Context, query string) ([]models.RevokedToken, error)
+	GetUserTokensRevokedAt(ctx context.Context, query string, userID int) (time.Time, error)
+	DeleteExpiredRevokedTokens(ctx context.Context, query string) (int64, error)
 }
 
 type BudgetStorageInterface interface {
 	CreateBudget(ctx context.Context, query string, budget models.Budget) (models.Budget, error)
 	GetBudgetByID(ctx context.Context, query string, userID uint, category_id int, budget_id int) (models.Budget, error)
 	GetUserBudgets(ctx context.Context, query string, category_id int, userID uint) ([]models.Budget, error)
+	UpdateBudget(ctx context.Context, query string, budget models.Budget) error
 	DeleteBudget(ctx context.Context, query string, userID uint, category_id int, budget_id int) error
 	DeleteBudgetsInCategory(ctx context.Context, query string, userID uint, categoryID int) error
-	UpdateSpentAmount(ctx context.Context, query string, category_id int, budgetID uint, spentAmount float64) error
+	UpdateSpentAmount(ctx context.Context, query string, category_id int, budgetID uint, spentAmount money.Amount) error
+	AdjustSpentAmount(ctx context.Context, query string, userID uint, categoryID int, date time.Time, currency string, delta, baseDelta money.Amount) ([]string, error)
+	RecalculateSpentAmounts(ctx context.Context, query string, userID uint) (int64, int64, error)
 	GetActiveBudgetsByCategoryAndDate(ctx context.Context, query string, userID uint, categoryID int, date time.Time) ([]models.Budget, error)
+	SetBudgetCategories(ctx context.Context, deleteQuery, insertQuery string, userID uint, budgetID uint, categoryIDs []uint) error
+	GetBudgetSpentAmount(ctx context.Context, query string, budgetID uint, start, end time.Time, currency string) (money.Amount, int64, error)
+	GetDueBudgetIDs(ctx context.Context, query string, now time.Time) ([]uint, error)
+	LockBudget(ctx context.Context, query string, id uint) (models.Budget, error)
+	HasOverlappingBudget(ctx context.Context, query string, budget models.Budget) (bool, error)
+	ArchiveBudgetPeriod(ctx context.Context, query string, budget models.Budget) error
+	GetBudgetHistory(ctx context.Context, query string, userID uint, categoryID int, budgetID int) ([]models.BudgetPeriod, error)
+}
+
+type BudgetAlertStorageInterface interface {
+	CreateThresholdAlerts(ctx context.Context, query string, userID uint, categoryID int, date time.Time) (int64, error)
+	CreateNearEndAlerts(ctx context.Context, query string, now, until time.Time) (int64, error)
+	GetUserAlerts(ctx context.Context, query string, userID uint, unacknowledgedOnly bool) ([]models.BudgetAlert, error)
+	AcknowledgeAlert(ctx context.Context, query string, userID uint, alertID int) error
 }
 
 type CategoryStorageInterface interface {
@@ -31,27 +72,89 @@ type CategoryStorageInterface interface {
 	GetCategories(ctx context.Context, query string, userID uint) ([]models.Category, error)
 	DeleteCategory(ctx context.Context, query string, userID uint, categoryID int) error
 	GetMostUsedCategories(ctx context.Context, query string, userID uint) ([]models.Category, error)
-	GetTotalAmountInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (float64, error)
-	GetLargestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error)
-	GetSmallestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error)
-	GetExpenseCountInCategory(ctx context.Context, query string, userID uint, categoryID int, period string) (int, error)
+	GetTotalAmountInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (money.Amount, error)
+	GetLargestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error)
+	GetSmallestExpenseInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error)
+	GetExpenseCountInCategory(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (int, error)
 }
 
 type ExpenseStorageInterface interface {
 	CreateExpense(ctx context.Context, query string, expense models.Expense) (models.Expense, error)
 	GetExpenseByID(ctx context.Context, query string, userID uint, categoryID int, id uint) (models.Expense, error)
 	GetExpensesByUserID(ctx context.Context, query string, categoryID int, userID uint) ([]models.Expense, error)
-	GetExpensesByPeriod(ctx context.Context, query string, userID uint, categoryID int, period string) ([]models.Expense, error)
+	GetExpensesByFilter(ctx context.Context, query string, args ...any) ([]models.Expense, error)
+	GetExpensesByPeriod(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) ([]models.Expense, error)
+	UpdateExpense(ctx context.Context, query string, expense models.Expense) (models.Expense, error)
 	DeleteExpense(ctx context.Context, query string, userID uint, categoryID int, id uint) error
 	DeleteExpensesInCategory(ctx context.Context, query string, userID uint, categoryID int) error
 	GetExpensesByCategory(ctx context.Context, query string, userID uint, categoryID int) ([]models.Expense, error)
-	GetLargestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error)
-	GetSmallestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, period string) (models.Expense, error)
+	GetLargestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error)
+	GetSmallestExpenseByPeriod(ctx context.Context, query string, userID uint, categoryID int, from, to time.Time) (models.Expense, error)
 	GetExpensesByCategoryAndPeriod(ctx context.Context, query string, userID uint, categoryID int, startDate, endDate time.Time) ([]models.Expense, error)
 }
 
+type ExchangeRateStorageInterface interface {
+	UpsertRates(ctx context.Context, query string, rates []models.ExchangeRate) (int64, error)
+	GetRateToBase(ctx context.Context, query string, userID uint, currency string, date time.Time) (string, *float64, error)
+	CountExpensesWithoutRate(ctx context.Context, query string, userID uint, currency string) (int, error)
+	RecalculateBaseAmounts(ctx context.Context, query string, userID uint, from time.Time) (int64, error)
+	CountIncomesWithoutRate(ctx context.Context, query string, userID uint, currency string) (int, error)
+	RecalculateIncomeBaseAmounts(ctx context.Context, query string, userID uint, from time.Time) (int64, error)
+}
+
+type ExportStorageInterface interface {
+	StreamCategories(ctx context.Context, query string, userID uint, fn func(models.Category) error) error
+	StreamExpenses(ctx context.Context, query string, userID uint, from, to *time.Time, fn func(models.Expense) error) error
+	StreamBudgets(ctx context.Context, query string, userID uint, from, to *time.Time, fn func(models.Budget) error) error
+}
+
+type ImportStorageInterface interface {
+	CreateBatch(ctx context.Context, query string, batch models.ImportBatch) (models.ImportBatch, error)
+	AddRows(ctx context.Context, query string, batchID uint, rows []models.ImportRow) (int64, error)
+	MarkDuplicates(ctx context.Context, query string, userID uint, batchID uint) (int64, error)
+	GetBatch(ctx context.Context, query string, userID uint, batchID int) (models.ImportBatch, error)
+	GetBatches(ctx context.Context, query string, userID uint) ([]models.ImportBatch, error)
+	GetRows(ctx context.Context, query string, batchID uint) ([]models.ImportRow, error)
+	SetRowExpense(ctx context.Context, query string, batchID uint, rowNumber int, categoryID uint, expenseID uint) error
+	GetBatchExpenses(ctx context.Context, query string, batchID uint) ([]models.Expense, error)
+	UpdateBatchStatus(ctx context.Context, query string, userID uint, batchID uint) error
+	DeleteBatch(ctx context.Context, query string, userID uint, batchID uint) error
+}
+
+type IncomeStorageInterface interface {
+	CreateIncome(ctx context.Context, query string, income models.Income) (models.Income, error)
+	GetIncomeByID(ctx context.Context, query string, userID uint, id int) (models.Income, error)
+	GetIncomes(ctx context.Context, query string, userID uint) ([]models.Income, error)
+	UpdateIncome(ctx context.Context, query string, income models.Income) (models.Income, error)
+	DeleteIncome(ctx context.Context, query string, userID uint, id int) error
+	GetCashFlow(ctx context.Context, query string, userID uint, from, to time.Time, granularity string, step string) ([]models.CashFlowBucket, error)
+}
+
+type RecurringExpenseStorageInterface interface {
+	CreateRecurringExpense(ctx context.Context, query string, recurring models.RecurringExpense) (models.RecurringExpense, error)
+	GetRecurringExpenseByID(ctx context.Context, query string, userID uint, categoryID int, id int) (models.RecurringExpense, error)
+	GetRecurringExpenses(ctx context.Context, query string, userID uint, categoryID int) ([]models.RecurringExpense, error)
+	UpdateRecurringExpense(ctx context.Context, query string, recurring models.RecurringExpense) error
+	DeleteRecurringExpense(ctx context.Context, query string, userID uint, categoryID int, id int) error
+	GetDueRecurringExpenseIDs(ctx context.Context, query string, now time.Time) ([]uint, error)
+	LockRecurringExpense(ctx context.Context, query string, id uint) (models.RecurringExpense, error)
+	UpdateNextRun(ctx context.Context, query string, id uint, nextRunAt time.Time, isActive bool) error
+}
+
+type RuleStorageInterface interface {
+	CreateRule(ctx context.Context, query string, rule models.CategorizationRule) (models.CategorizationRule, error)
+	GetRules(ctx context.Context, query string, userID uint) ([]models.CategorizationRule, error)
+	DeleteRule(ctx context.Context, query string, userID uint, ruleID int) error
+}
+
+type TagStorageInterface interface {
+	SetExpenseTags(ctx context.Context, query string, userID uint, expenseID uint, tags []string) error
+	GetTagAnalytics(ctx context.Context, query string, userID uint, from, to *time.Time) ([]models.TagSummary, error)
+}
+
 type UserStorageInterface interface {
 	DeleteUser(ctx context.Context, query string, userID uint) error
 	GetUserStats(ctx context.Context, query string, userID uint) (models.UserStats, error)
 	GetProfile(ctx context.Context, query string, userID uint) (models.User, error)
+	UpdateBaseCurrency(ctx context.Context, query string, userID uint, currency string) error
 }
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RecurringExpenseStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewRecurringExpenseStorage(pool *pgxpool.Pool) *RecurringExpenseStorage {
+	return &RecurringExpenseStorage{
+		pool: pool,
+	}
+}
+
+func (s *RecurringExpenseStorage) CreateRecurringExpense(ctx context.Context, query string, recurring models.RecurringExpense) (models.RecurringExpense, error) {
+	var created models.RecurringExpense
+	err := conn(ctx, s.pool).QueryRow(ctx, query,
+		recurring.UserID,
+		recurring.CategoryID,
+		recurring.Amount,
+		recurring.Description,
+		recurring.Frequency,
+		recurring.DayOfMonth,
+		recurring.StartDate,
+		recurring.EndDate,
+		recurring.NextRunAt,
+		recurring.IsActive,
+	).Scan(
+		&created.ID,
+		&created.UserID,
+		&created.CategoryID,
+		&created.CategoryName,
+		&created.Amount,
+		&created.Description,
+		&created.Frequency,
+		&created.DayOfMonth,
+		&created.StartDate,
+		&created.EndDate,
+		&created.NextRunAt,
+		&created.IsActive,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return models.RecurringExpense{}, fmt.Errorf("failed to create recurring expense: %w", err)
+	}
+	return created, nil
+}
+
+func (s *RecurringExpenseStorage) GetRecurringExpenseByID(ctx context.Context, query string, userID uint, categoryID int, id int) (models.RecurringExpense, error) {
+	var recurring models.RecurringExpense
+	err := conn(ctx, s.pool).QueryRow(ctx, query, id, userID, categoryID).Scan(
+		&recurring.ID,
+		&recurring.UserID,
+		&recurring.CategoryID,
+		&recurring.CategoryName,
+		&recurring.Amount,
+		&recurring.Description,
+		&recurring.Frequency,
+		&recurring.DayOfMonth,
+		&recurring.StartDate,
+		&recurring.EndDate,
+		&recurring.NextRunAt,
+		&recurring.IsActive,
+		&recurring.CreatedAt,
+	)
+	if err != nil {
+		return models.RecurringExpense{}, fmt.Errorf("failed to get recurring expense by id: %w", err)
+	}
+	return recurring, nil
+}
+
+func (s *RecurringExpenseStorage) GetRecurringExpenses(ctx context.Context, query string, userID uint, categoryID int) ([]models.RecurringExpense, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recurring expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var recurring_expenses []models.RecurringExpense
+	for rows.Next() {
+		var recurring models.RecurringExpense
+		err := rows.Scan(
+			&recurring.ID,
+			&recurring.UserID,
+			&recurring.CategoryID,
+			&recurring.CategoryName,
+			&recurring.Amount,
+			&recurring.Description,
+			&recurring.Frequency,
+			&recurring.DayOfMonth,
+			&recurring.StartDate,
+			&recurring.EndDate,
+			&recurring.NextRunAt,
+			&recurring.IsActive,
+			&recurring.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recurring expense: %w", err)
+		}
+		recurring_expenses = append(recurring_expenses, recurring)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over recurring expenses: %w", err)
+	}
+
+	return recurring_expenses, nil
+}
+
+func (s *RecurringExpenseStorage) UpdateRecurringExpense(ctx context.Context, query string, recurring models.RecurringExpense) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query,
+		recurring.Amount,
+		recurring.Description,
+		recurring.Frequency,
+		recurring.DayOfMonth,
+		recurring.EndDate,
+		recurring.NextRunAt,
+		recurring.IsActive,
+		recurring.ID,
+		recurring.UserID,
+		recurring.CategoryID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring expense: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("recurring expense not found or access denied")
+	}
+
+	return nil
+}
+
+func (s *RecurringExpenseStorage) DeleteRecurringExpense(ctx context.Context, query string, userID uint, categoryID int, id int) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, id, userID, categoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring expense: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("recurring expense not found or access denied")
+	}
+
+	return nil
+}
+
+func (s *RecurringExpenseStorage) GetDueRecurringExpenseIDs(ctx context.Context, query string, now time.Time) ([]uint, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due recurring expenses: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan recurring expense id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over due recurring expenses: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (s *RecurringExpenseStorage) LockRecurringExpense(ctx context.Context, query string, id uint) (models.RecurringExpense, error) {
+	var recurring models.RecurringExpense
+	err := conn(ctx, s.pool).QueryRow(ctx, query, id).Scan(
+		&recurring.ID,
+		&recurring.UserID,
+		&recurring.CategoryID,
+		&recurring.CategoryName,
+		&recurring.Amount,
+		&recurring.Description,
+		&recurring.Frequency,
+		&recurring.DayOfMonth,
+		&recurring.StartDate,
+		&recurring.EndDate,
+		&recurring.NextRunAt,
+		&recurring.IsActive,
+		&recurring.CreatedAt,
+	)
+	if err != nil {
+		return models.RecurringExpense{}, fmt.Errorf("failed to lock recurring expense: %w", err)
+	}
+	return recurring, nil
+}
+
+func (s *RecurringExpenseStorage) UpdateNextRun(ctx context.Context, query string, id uint, nextRunAt time.Time, isActive bool) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, nextRunAt, isActive, id)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring expense schedule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RuleStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewRuleStorage(pool *pgxpool.Pool) *RuleStorage {
+	return &RuleStorage{
+		pool: pool,
+	}
+}
+
+func (s *RuleStorage) CreateRule(ctx context.Context, query string, rule models.CategorizationRule) (models.CategorizationRule, error) {
+	var new_rule models.CategorizationRule
+	err := conn(ctx, s.pool).QueryRow(ctx, query, rule.UserID, rule.CategoryID, rule.MatchType, rule.Pattern, rule.MinAmount, rule.MaxAmount, rule.Tags, rule.Priority).Scan(
+		&new_rule.ID,
+		&new_rule.UserID,
+		&new_rule.CategoryID,
+		&new_rule.CategoryName,
+		&new_rule.MatchType,
+		&new_rule.Pattern,
+		&new_rule.MinAmount,
+		&new_rule.MaxAmount,
+		&new_rule.Tags,
+		&new_rule.Priority,
+		&new_rule.CreatedAt,
+	)
+	if err != nil {
+		return models.CategorizationRule{}, fmt.Errorf("failed to create rule: %w", err)
+	}
+	return new_rule, nil
+}
+
+func (s *RuleStorage) GetRules(ctx context.Context, query string, userID uint) ([]models.CategorizationRule, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.CategorizationRule
+	for rows.Next() {
+		var rule models.CategorizationRule
+		err := rows.Scan(
+			&rule.ID,
+			&rule.UserID,
+			&rule.CategoryID,
+			&rule.CategoryName,
+			&rule.MatchType,
+			&rule.Pattern,
+			&rule.MinAmount,
+			&rule.MaxAmount,
+			&rule.Tags,
+			&rule.Priority,
+			&rule.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rule: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+func (s *RuleStorage) DeleteRule(ctx context.Context, query string, userID uint, ruleID int) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, ruleID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("rule not found or access denied")
+	}
+
+	return nil
+}
@@ -3,19 +3,39 @@ package storage
 import "github.com/jackc/pgx/v5/pgxpool"
 
 type Storages struct {
+	TransactorInterface
+	AnalyticsStorageInterface
 	AuthStorageInterface
 	BudgetStorageInterface
+	BudgetAlertStorageInterface
 	CategoryStorageInterface
 	ExpenseStorageInterface
+	ExchangeRateStorageInterface
+	ExportStorageInterface
+	ImportStorageInterface
+	IncomeStorageInterface
+	RecurringExpenseStorageInterface
+	RuleStorageInterface
+	TagStorageInterface
 	UserStorageInterface
 }
 
 func NewStorages(pool *pgxpool.Pool) *Storages {
 	return &Storages{
-		AuthStorageInterface:     NewAuthStorage(pool),
-		BudgetStorageInterface:   NewBudgetStorage(pool),
-		CategoryStorageInterface: NewCategoryStorage(pool),
-		ExpenseStorageInterface:  NewExpenseStorage(pool),
-		UserStorageInterface:     NewUserStorage(pool),
+		TransactorInterface:              NewTxManager(pool),
+		AnalyticsStorageInterface:        NewAnalyticsStorage(pool),
+		AuthStorageInterface:             NewAuthStorage(pool),
+		BudgetStorageInterface:           NewBudgetStorage(pool),
+		BudgetAlertStorageInterface:      NewBudgetAlertStorage(pool),
+		CategoryStorageInterface:         NewCategoryStorage(pool),
+		ExpenseStorageInterface:          NewExpenseStorage(pool),
+		ExchangeRateStorageInterface:     NewExchangeRateStorage(pool),
+		ExportStorageInterface:           NewExportStorage(pool),
+		ImportStorageInterface:           NewImportStorage(pool),
+		IncomeStorageInterface:           NewIncomeStorage(pool),
+		RecurringExpenseStorageInterface: NewRecurringExpenseStorage(pool),
+		RuleStorageInterface:             NewRuleStorage(pool),
+		TagStorageInterface:              NewTagStorage(pool),
+		UserStorageInterface:             NewUserStorage(pool),
 	}
 }
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"finance/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TagStorage struct {
+	pool *pgxpool.Pool
+}
+
+func NewTagStorage(pool *pgxpool.Pool) *TagStorage {
+	return &TagStorage{
+		pool: pool,
+	}
+}
+
+func (s *TagStorage) SetExpenseTags(ctx context.Context, query string, userID uint, expenseID uint, tags []string) error {
+	_, err := conn(ctx, s.pool).Exec(ctx, query, expenseID, userID, tags)
+	if err != nil {
+		return fmt.Errorf("failed to set expense tags: %w", err)
+	}
+	return nil
+}
+
+func (s *TagStorage) GetTagAnalytics(ctx context.Context, query string, userID uint, from, to *time.Time) ([]models.TagSummary, error) {
+	rows, err := conn(ctx, s.pool).Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag analytics: %w", err)
+	}
+	defer rows.Close()
+
+	var summaries []models.TagSummary
+	for rows.Next() {
+		var summary models.TagSummary
+		err := rows.Scan(
+			&summary.Tag,
+			&summary.TotalAmount,
+			&summary.ExpensesCount,
+			&summary.CategoriesCount,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tag summary: %w", err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over tag analytics: %w", err)
+	}
+
+	return summaries, nil
+}
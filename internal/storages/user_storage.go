@@ -21,7 +21,7 @@ func NewUserStorage(pool *pgxpool.Pool) *UserStorage {
 }
 
 func (s *UserStorage) DeleteUser(ctx context.Context, query string, userID uint) error {
-	_, err := s.pool.Exec(ctx, query, userID)
+	_, err := conn(ctx, s.pool).Exec(ctx, query, userID)
 	if err != nil {
 		return err
 	}
@@ -30,12 +30,15 @@ func (s *UserStorage) DeleteUser(ctx context.Context, query string, userID uint)
 
 func (s *UserStorage) GetUserStats(ctx context.Context, query string, userID uint) (models.UserStats, error) {
 	var stats models.UserStats
-	err := s.pool.QueryRow(ctx, query, userID).Scan(
+	err := conn(ctx, s.pool).QueryRow(ctx, query, userID).Scan(
 		&stats.TotalExpenses,
 		&stats.TotalCategories,
 		&stats.TotalBudgets,
 		&stats.MonthlyExpenses,
 		&stats.WeeklyExpenses,
+		&stats.TotalExpensesAmount,
+		&stats.TotalIncome,
+		&stats.Currency,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -48,12 +51,13 @@ func (s *UserStorage) GetUserStats(ctx context.Context, query string, userID uin
 
 func (s *UserStorage) GetProfile(ctx context.Context, query string, userID uint) (models.User, error) {
 	var user_profile models.User
-	err := s.pool.QueryRow(ctx, query, userID).Scan(
+	err := conn(ctx, s.pool).QueryRow(ctx, query, userID).Scan(
 		&user_profile.ID,
 		&user_profile.FirstName,
 		&user_profile.LastName,
 		&user_profile.Email,
 		&user_profile.TimeOfRegistration,
+		&user_profile.BaseCurrency,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -64,3 +68,14 @@ func (s *UserStorage) GetProfile(ctx context.Context, query string, userID uint)
 	return user_profile, nil
 
 }
+
+func (s *UserStorage) UpdateBaseCurrency(ctx context.Context, query string, userID uint, currency string) error {
+	result, err := conn(ctx, s.pool).Exec(ctx, query, userID, currency)
+	if err != nil {
+		return fmt.Errorf("failed to update base currency: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user with id %d not found", userID)
+	}
+	return nil
+}
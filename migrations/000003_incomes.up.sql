@@ -0,0 +1,11 @@
+CREATE TABLE incomes (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source VARCHAR(255) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    description TEXT NOT NULL DEFAULT '',
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_incomes_user_date ON incomes(user_id, date);
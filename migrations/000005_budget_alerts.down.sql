@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS budget_alerts;
+ALTER TABLE budgets DROP COLUMN IF EXISTS alert_thresholds;
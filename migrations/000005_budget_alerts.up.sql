@@ -0,0 +1,16 @@
+ALTER TABLE budgets ADD COLUMN alert_thresholds INTEGER[] NOT NULL DEFAULT '{80,100}';
+
+CREATE TABLE budget_alerts (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    alert_type VARCHAR(20) NOT NULL CHECK (alert_type IN ('warning', 'exceeded', 'near_end')),
+    threshold INTEGER NOT NULL DEFAULT 0,
+    period_start TIMESTAMP NOT NULL,
+    percentage DECIMAL(8,2) NOT NULL,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(budget_id, alert_type, threshold, period_start)
+);
+
+CREATE INDEX idx_budget_alerts_user_created ON budget_alerts(user_id, created_at DESC);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS budget_history;
+
+DROP INDEX IF EXISTS idx_budgets_rollover;
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_user_id_category_id_period_start_date_key;
+ALTER TABLE budgets ADD CONSTRAINT budgets_user_id_category_id_period_key UNIQUE (user_id, category_id, period);
+
+ALTER TABLE budgets DROP COLUMN IF EXISTS carried_amount;
+ALTER TABLE budgets DROP COLUMN IF EXISTS carry_over;
+ALTER TABLE budgets DROP COLUMN IF EXISTS auto_renew;
@@ -0,0 +1,22 @@
+ALTER TABLE budgets ADD COLUMN auto_renew BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE budgets ADD COLUMN carry_over BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE budgets ADD COLUMN carried_amount DECIMAL(12,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_user_id_category_id_period_key;
+ALTER TABLE budgets ADD CONSTRAINT budgets_user_id_category_id_period_start_date_key UNIQUE (user_id, category_id, period, start_date);
+
+CREATE INDEX idx_budgets_rollover ON budgets(end_date) WHERE auto_renew;
+
+CREATE TABLE budget_history (
+    id SERIAL PRIMARY KEY,
+    budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period VARCHAR(20) NOT NULL,
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    carried_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    spent_amount DECIMAL(12,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(budget_id, start_date)
+);
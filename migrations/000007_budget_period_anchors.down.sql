@@ -0,0 +1,7 @@
+DELETE FROM budgets WHERE period = 'custom';
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_period_check;
+ALTER TABLE budgets ADD CONSTRAINT budgets_period_check CHECK (period IN ('weekly', 'monthly', 'yearly'));
+
+ALTER TABLE budgets DROP COLUMN IF EXISTS year_start_month;
+ALTER TABLE budgets DROP COLUMN IF EXISTS month_start_day;
+ALTER TABLE budgets DROP COLUMN IF EXISTS week_start;
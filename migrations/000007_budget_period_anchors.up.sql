@@ -0,0 +1,6 @@
+ALTER TABLE budgets ADD COLUMN week_start SMALLINT NOT NULL DEFAULT 1 CHECK (week_start BETWEEN 0 AND 6);
+ALTER TABLE budgets ADD COLUMN month_start_day SMALLINT NOT NULL DEFAULT 1 CHECK (month_start_day BETWEEN 1 AND 31);
+ALTER TABLE budgets ADD COLUMN year_start_month SMALLINT NOT NULL DEFAULT 1 CHECK (year_start_month BETWEEN 1 AND 12);
+
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_period_check;
+ALTER TABLE budgets ADD CONSTRAINT budgets_period_check CHECK (period IN ('weekly', 'monthly', 'yearly', 'custom'));
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS budget_categories;
+
+DELETE FROM budgets WHERE category_id IS NULL;
+ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_scope_category_check;
+ALTER TABLE budgets ALTER COLUMN category_id SET NOT NULL;
+ALTER TABLE budgets DROP COLUMN IF EXISTS name;
+ALTER TABLE budgets DROP COLUMN IF EXISTS scope;
@@ -0,0 +1,12 @@
+ALTER TABLE budgets ADD COLUMN scope VARCHAR(10) NOT NULL DEFAULT 'category' CHECK (scope IN ('category', 'group', 'overall'));
+ALTER TABLE budgets ADD COLUMN name VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE budgets ALTER COLUMN category_id DROP NOT NULL;
+ALTER TABLE budgets ADD CONSTRAINT budgets_scope_category_check CHECK ((scope = 'category') = (category_id IS NOT NULL));
+
+CREATE TABLE budget_categories (
+    budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (budget_id, category_id)
+);
+
+CREATE INDEX idx_budget_categories_category ON budget_categories(category_id);
@@ -0,0 +1,7 @@
+DROP FUNCTION IF EXISTS exchange_rate(CHAR(3), CHAR(3), DATE);
+DROP TABLE IF EXISTS exchange_rates;
+
+ALTER TABLE budgets DROP COLUMN IF EXISTS currency;
+ALTER TABLE expenses DROP COLUMN IF EXISTS base_amount;
+ALTER TABLE expenses DROP COLUMN IF EXISTS currency;
+ALTER TABLE users DROP COLUMN IF EXISTS base_currency;
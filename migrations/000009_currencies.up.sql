@@ -0,0 +1,49 @@
+ALTER TABLE users ADD COLUMN base_currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE expenses ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+ALTER TABLE expenses ADD COLUMN base_amount DECIMAL(12,2);
+UPDATE expenses SET base_amount = amount;
+ALTER TABLE expenses ALTER COLUMN base_amount SET NOT NULL;
+
+ALTER TABLE budgets ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+CREATE TABLE exchange_rates (
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate NUMERIC(18,8) NOT NULL CHECK (rate > 0),
+    date DATE NOT NULL,
+    PRIMARY KEY (base_currency, quote_currency, date),
+    CHECK (base_currency <> quote_currency)
+);
+
+CREATE INDEX idx_exchange_rates_quote ON exchange_rates(quote_currency, base_currency, date);
+
+-- exchange_rate возвращает курс from_currency к to_currency на дату: последний известный прямой курс,
+-- обратный курс или кросс-курс через общую валюту. Если курса нет, возвращает NULL
+CREATE OR REPLACE FUNCTION exchange_rate(from_currency CHAR(3), to_currency CHAR(3), on_date DATE)
+RETURNS NUMERIC AS $$
+    SELECT CASE WHEN from_currency = to_currency THEN 1::NUMERIC ELSE COALESCE(
+        (SELECT r.rate FROM exchange_rates r
+         WHERE r.base_currency = from_currency AND r.quote_currency = to_currency AND r.date <= on_date
+         ORDER BY r.date DESC LIMIT 1),
+        (SELECT 1 / r.rate FROM exchange_rates r
+         WHERE r.base_currency = to_currency AND r.quote_currency = from_currency AND r.date <= on_date
+         ORDER BY r.date DESC LIMIT 1),
+        (SELECT f.rate / t.rate
+         FROM (SELECT DISTINCT ON (quote_currency) quote_currency, rate FROM exchange_rates
+               WHERE base_currency = from_currency AND date <= on_date
+               ORDER BY quote_currency, date DESC) f
+         JOIN (SELECT DISTINCT ON (quote_currency) quote_currency, rate FROM exchange_rates
+               WHERE base_currency = to_currency AND date <= on_date
+               ORDER BY quote_currency, date DESC) t ON t.quote_currency = f.quote_currency
+         LIMIT 1),
+        (SELECT t.rate / f.rate
+         FROM (SELECT DISTINCT ON (base_currency) base_currency, rate FROM exchange_rates
+               WHERE quote_currency = from_currency AND date <= on_date
+               ORDER BY base_currency, date DESC) f
+         JOIN (SELECT DISTINCT ON (base_currency) base_currency, rate FROM exchange_rates
+               WHERE quote_currency = to_currency AND date <= on_date
+               ORDER BY base_currency, date DESC) t ON t.base_currency = f.base_currency
+         LIMIT 1)
+    ) END
+$$ LANGUAGE SQL STABLE;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS import_rows;
+DROP TABLE IF EXISTS import_batches;
+DROP TABLE IF EXISTS categorization_rules;
+
+ALTER TABLE expenses ADD CONSTRAINT expenses_user_id_category_id_date_key UNIQUE (user_id, category_id, date);
@@ -0,0 +1,46 @@
+-- В выписке бывает несколько операций одной категории за день, поэтому дата расхода больше не уникальна
+ALTER TABLE expenses DROP CONSTRAINT IF EXISTS expenses_user_id_category_id_date_key;
+
+CREATE TABLE categorization_rules (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    pattern VARCHAR(200) NOT NULL,
+    priority INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_categorization_rules_user ON categorization_rules(user_id, priority DESC, id);
+
+CREATE TABLE import_batches (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL DEFAULT '',
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'ofx')),
+    status VARCHAR(20) NOT NULL DEFAULT 'preview' CHECK (status IN ('preview', 'committed', 'undone')),
+    rows_count INTEGER NOT NULL DEFAULT 0,
+    skipped_count INTEGER NOT NULL DEFAULT 0,
+    imported_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    committed_at TIMESTAMP WITH TIME ZONE,
+    undone_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_import_batches_user_created ON import_batches(user_id, created_at DESC);
+
+-- Строки выписки: до подтверждения - предпросмотр, после - ссылка на созданный расход
+CREATE TABLE import_rows (
+    batch_id INTEGER NOT NULL REFERENCES import_batches(id) ON DELETE CASCADE,
+    row_number INTEGER NOT NULL,
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    amount DECIMAL(12,2) NOT NULL CHECK (amount > 0),
+    currency CHAR(3),
+    description TEXT NOT NULL DEFAULT '',
+    category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL,
+    rule_id INTEGER REFERENCES categorization_rules(id) ON DELETE SET NULL,
+    duplicate_expense_id INTEGER REFERENCES expenses(id) ON DELETE SET NULL,
+    expense_id INTEGER REFERENCES expenses(id) ON DELETE SET NULL,
+    PRIMARY KEY (batch_id, row_number)
+);
+
+CREATE INDEX idx_import_rows_expense ON import_rows(expense_id) WHERE expense_id IS NOT NULL;
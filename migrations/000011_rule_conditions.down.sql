@@ -0,0 +1,9 @@
+-- Правила только по сумме подошли бы к любой операции, а регулярные выражения - ни к одной
+DELETE FROM categorization_rules WHERE pattern = '' OR match_type = 'regex';
+
+ALTER TABLE categorization_rules DROP CONSTRAINT IF EXISTS categorization_rules_condition_check;
+ALTER TABLE categorization_rules ALTER COLUMN pattern DROP DEFAULT;
+ALTER TABLE categorization_rules DROP COLUMN IF EXISTS tags;
+ALTER TABLE categorization_rules DROP COLUMN IF EXISTS max_amount;
+ALTER TABLE categorization_rules DROP COLUMN IF EXISTS min_amount;
+ALTER TABLE categorization_rules DROP COLUMN IF EXISTS match_type;
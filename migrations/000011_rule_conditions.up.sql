@@ -0,0 +1,9 @@
+-- Условия правила: шаблон описания (подстрока или регулярное выражение) и диапазон суммы.
+-- Пустой шаблон подходит к любому описанию, незаданная граница суммы не ограничивает ее
+ALTER TABLE categorization_rules
+    ADD COLUMN match_type VARCHAR(10) NOT NULL DEFAULT 'contains' CHECK (match_type IN ('contains', 'regex')),
+    ADD COLUMN min_amount DECIMAL(12,2) CHECK (min_amount >= 0),
+    ADD COLUMN max_amount DECIMAL(12,2) CHECK (max_amount >= 0),
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ALTER COLUMN pattern SET DEFAULT '',
+    ADD CONSTRAINT categorization_rules_condition_check CHECK (pattern <> '' OR min_amount IS NOT NULL OR max_amount IS NOT NULL);
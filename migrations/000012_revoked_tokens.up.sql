@@ -0,0 +1,12 @@
+-- Отозванные access-токены. Строка с jti отзывает один токен, строка без jti - все токены пользователя,
+-- выданные не позже revoked_at. Внешнего ключа на users нет: отзыв должен пережить удаление аккаунта.
+-- Строки удаляются после expires_at, когда отозванные токены истекли бы сами
+CREATE TABLE revoked_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    jti VARCHAR(64) UNIQUE,
+    revoked_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_revoked_tokens_expires ON revoked_tokens(expires_at);
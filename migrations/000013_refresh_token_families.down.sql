@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_refresh_tokens_user;
+DROP INDEX IF EXISTS idx_refresh_tokens_family;
+-- Без семейств использованные токены снова стали бы действительными
+DELETE FROM refresh_tokens WHERE rotated_at IS NOT NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN expires_at TYPE TIMESTAMP;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS rotated_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS created_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS family_id;
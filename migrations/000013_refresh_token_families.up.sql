@@ -0,0 +1,15 @@
+-- Refresh-токены одного входа образуют семейство. При обновлении токен помечается использованным (rotated_at),
+-- а новый токен получает тот же family_id. Повторное использование помеченного токена отзывает все семейство
+ALTER TABLE refresh_tokens
+    ADD COLUMN family_id VARCHAR(64),
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ADD COLUMN rotated_at TIMESTAMP WITH TIME ZONE,
+    -- Срок действия сравнивается с временем сервера, поэтому часовой пояс должен храниться вместе с ним
+    ALTER COLUMN expires_at TYPE TIMESTAMP WITH TIME ZONE;
+
+-- Каждый токен, выданный до появления семейств, считается отдельным сеансом
+UPDATE refresh_tokens SET family_id = 'legacy-' || id;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_user ON refresh_tokens(user_id);
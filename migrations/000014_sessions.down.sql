@@ -0,0 +1,8 @@
+-- Без family_id строка отзывала бы все токены пользователя
+DELETE FROM revoked_tokens WHERE family_id IS NOT NULL;
+ALTER TABLE revoked_tokens DROP COLUMN IF EXISTS family_id;
+
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS last_used_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS session_created_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS ip_address;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS user_agent;
@@ -0,0 +1,18 @@
+-- Сеанс - семейство refresh-токенов одного входа. В каждой строке хранится, откуда токен был получен:
+-- при входе и при каждом обновлении user_agent и ip_address перезаписываются данными последнего запроса
+ALTER TABLE refresh_tokens
+    ADD COLUMN user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ADD COLUMN ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    ADD COLUMN session_created_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN last_used_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE refresh_tokens SET session_created_at = created_at, last_used_at = created_at;
+
+ALTER TABLE refresh_tokens
+    ALTER COLUMN session_created_at SET NOT NULL,
+    ALTER COLUMN session_created_at SET DEFAULT CURRENT_TIMESTAMP,
+    ALTER COLUMN last_used_at SET NOT NULL,
+    ALTER COLUMN last_used_at SET DEFAULT CURRENT_TIMESTAMP;
+
+-- Строка с family_id отзывает все access-токены сеанса
+ALTER TABLE revoked_tokens ADD COLUMN family_id VARCHAR(64);
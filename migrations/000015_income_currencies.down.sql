@@ -0,0 +1,2 @@
+ALTER TABLE incomes DROP COLUMN IF EXISTS base_amount;
+ALTER TABLE incomes DROP COLUMN IF EXISTS currency;
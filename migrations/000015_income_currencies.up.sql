@@ -0,0 +1,5 @@
+ALTER TABLE incomes ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+ALTER TABLE incomes ADD COLUMN base_amount DECIMAL(12,2);
+-- Доходы до появления валют вносились в базовой валюте владельца
+UPDATE incomes i SET currency = u.base_currency, base_amount = i.amount FROM users u WHERE u.id = i.user_id;
+ALTER TABLE incomes ALTER COLUMN base_amount SET NOT NULL;
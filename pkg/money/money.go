@@ -0,0 +1,186 @@
+// Package money хранит денежные суммы в целых копейках, чтобы суммы и разности не теряли точность
+package money
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale - количество знаков после запятой, совпадает с DECIMAL(12,2) в базе
+const Scale = 2
+
+// minorPerUnit - количество минимальных единиц (копеек, центов) в одной единице валюты
+const minorPerUnit = 100
+
+// Amount - денежная сумма в минимальных единицах валюты. Нулевое значение - 0.00
+type Amount int64
+
+// FromMinor возвращает сумму из количества минимальных единиц
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// FromFloat округляет v до копеек (половина - от нуля). Используется только для значений,
+// которые изначально вычислены приближенно, например при пересчете по курсу
+func FromFloat(v float64) Amount {
+	return Amount(math.Round(v * minorPerUnit))
+}
+
+// Parse разбирает десятичную запись суммы ("12", "12.5", "-0.01", "1e3").
+// Больше двух знаков после запятой - ошибка, а не округление
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || strings.ContainsAny(s, "/") {
+		return 0, fmt.Errorf("некорректная сумма: %q", s)
+	}
+	r.Mul(r, big.NewRat(minorPerUnit, 1))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("сумма %q содержит больше %d знаков после запятой", s, Scale)
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("сумма %q слишком большая", s)
+	}
+	return Amount(r.Num().Int64()), nil
+}
+
+// Minor возвращает сумму в минимальных единицах валюты
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Float64 возвращает приближенное значение суммы для расчета процентов и долей
+func (a Amount) Float64() float64 {
+	return float64(a) / minorPerUnit
+}
+
+// String возвращает сумму с двумя знаками после запятой, например "-12.05"
+func (a Amount) String() string {
+	sign := ""
+	minor := int64(a)
+	if minor < 0 {
+		sign = "-"
+	}
+	units, cents := minor/minorPerUnit, minor%minorPerUnit
+	if units < 0 {
+		units = -units
+	}
+	if cents < 0 {
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, units, cents)
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+func (a Amount) Neg() Amount {
+	return -a
+}
+
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+// Mul умножает сумму на коэффициент (например, курс валюты) с округлением до копеек, половина - от нуля
+func (a Amount) Mul(factor float64) Amount {
+	r := new(big.Rat).SetInt64(int64(a))
+	f := new(big.Rat)
+	if f.SetFloat64(factor) == nil {
+		return 0
+	}
+	return Amount(roundRat(r.Mul(r, f)).Int64())
+}
+
+// Div делит сумму на n частей с округлением до копеек, половина - от нуля. При n <= 0 возвращает 0
+func (a Amount) Div(n int) Amount {
+	if n <= 0 {
+		return 0
+	}
+	return Amount(roundRat(big.NewRat(int64(a), int64(n))).Int64())
+}
+
+// Ratio возвращает отношение a к b, например долю потраченного бюджета. При b = 0 возвращает 0
+func (a Amount) Ratio(b Amount) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Sum складывает суммы
+func Sum(amounts ...Amount) Amount {
+	var total Amount
+	for _, amount := range amounts {
+		total += amount
+	}
+	return total
+}
+
+// roundRat округляет r до целого, половина - от нуля
+func roundRat(r *big.Rat) *big.Int {
+	num := new(big.Int).Set(r.Num())
+	den := r.Denom()
+	neg := num.Sign() < 0
+	num.Abs(num)
+	// (2*|num| + den) / (2*den) - округление половины вверх для неотрицательного числа
+	num.Mul(num, big.NewInt(2)).Add(num, den)
+	num.Quo(num, new(big.Int).Mul(den, big.NewInt(2)))
+	if neg {
+		num.Neg(num)
+	}
+	return num
+}
+
+// MarshalJSON записывает сумму точным числом с двумя знаками после запятой: 12.50
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON принимает число или строку с числом. null оставляет сумму без изменений
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	amount, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// UnmarshalText разбирает сумму из текста
+func (a *Amount) UnmarshalText(text []byte) error {
+	amount, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
+
+// UnmarshalParam разбирает сумму из query-параметра при биндинге формы в gin
+func (a *Amount) UnmarshalParam(param string) error {
+	return a.UnmarshalText([]byte(param))
+}
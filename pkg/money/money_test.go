@@ -0,0 +1,260 @@
+package money
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// roundHalfAway - эталонное целочисленное деление с округлением половины от нуля
+func roundHalfAway(num, den int64) int64 {
+	q, r := num/den, num%den
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= den {
+		if num < 0 {
+			q--
+		} else {
+			q++
+		}
+	}
+	return q
+}
+
+func TestParseStringRoundTrip(t *testing.T) {
+	roundTrip := func(minor int64) bool {
+		amount := FromMinor(minor)
+		parsed, err := Parse(amount.String())
+		return err == nil && parsed == amount
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+	for _, minor := range []int64{0, 1, -1, 99, -99, 100, -100, math.MaxInt64, math.MinInt64} {
+		if !roundTrip(minor) {
+			t.Errorf("round trip failed for %d", minor)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"12", 1200},
+		{"12.5", 1250},
+		{"12.05", 1205},
+		{"-0.01", -1},
+		{" 7.10 ", 710},
+		{"1e3", 100000},
+		{"1.5e-1", 15},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "abc", "1.001", "0.005", "1/2", "1e-3", "100000000000000000000"} {
+		if _, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) expected error", in)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{1205, "12.05"},
+		{-1205, "-12.05"},
+		{100, "1.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestAddSubInverse(t *testing.T) {
+	inverse := func(a, b int64) bool {
+		x, y := FromMinor(a), FromMinor(b)
+		return x.Add(y).Sub(y) == x && x.Sub(y).Add(y) == x && x.Add(y.Neg()) == x.Sub(y)
+	}
+	if err := quick.Check(inverse, &quick.Config{MaxCount: 10000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSumOrderIndependent(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		amounts := make([]Amount, rnd.Intn(200))
+		var reference int64
+		for j := range amounts {
+			minor := rnd.Int63n(2_000_000) - 1_000_000
+			amounts[j] = FromMinor(minor)
+			reference += minor
+		}
+		if got := Sum(amounts...); got.Minor() != reference {
+			t.Fatalf("Sum = %d, want %d", got.Minor(), reference)
+		}
+		rnd.Shuffle(len(amounts), func(a, b int) { amounts[a], amounts[b] = amounts[b], amounts[a] })
+		if got := Sum(amounts...); got.Minor() != reference {
+			t.Fatalf("Sum after shuffle = %d, want %d", got.Minor(), reference)
+		}
+	}
+
+	// Десять раз по 0.10 - ровно 1.00, в отличие от сложения float64
+	dimes := make([]Amount, 10)
+	for i := range dimes {
+		dimes[i] = FromMinor(10)
+	}
+	if got := Sum(dimes...); got != FromMinor(100) {
+		t.Errorf("Sum of ten 0.10 = %s, want 1.00", got)
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		n      int
+		want   Amount
+	}{
+		{100, 3, 33},
+		{200, 3, 67},
+		{-200, 3, -67},
+		{5, 2, 3},
+		{-5, 2, -3},
+		{1, 4, 0},
+		{100, 0, 0},
+		{100, -1, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Div(tt.n); got != tt.want {
+			t.Errorf("Amount(%d).Div(%d) = %d, want %d", int64(tt.amount), tt.n, got, tt.want)
+		}
+	}
+
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < 10000; i++ {
+		minor := rnd.Int63n(2_000_000_000) - 1_000_000_000
+		n := rnd.Intn(1000) + 1
+		if got, want := FromMinor(minor).Div(n).Minor(), roundHalfAway(minor, int64(n)); got != want {
+			t.Fatalf("Amount(%d).Div(%d) = %d, want %d", minor, n, got, want)
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		factor float64
+		want   Amount
+	}{
+		{1000, 1.5, 1500},
+		{1, 0.5, 1},
+		{-1, 0.5, -1},
+		{3, 0.5, 2},
+		{1005, 0.1, 101},
+		{10000, 0.9123, 9123},
+		{100, 0, 0},
+		{100, math.NaN(), 0},
+		{100, math.Inf(1), 0},
+	}
+	for _, tt := range tests {
+		if got := tt.amount.Mul(tt.factor); got != tt.want {
+			t.Errorf("Amount(%d).Mul(%v) = %d, want %d", int64(tt.amount), tt.factor, got, tt.want)
+		}
+	}
+
+	// Множители вида k/1024 представимы в float64 точно, поэтому результат big.Rat.SetFloat64
+	// сравнивается с целочисленным эталоном без погрешности
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < 10000; i++ {
+		minor := rnd.Int63n(2_000_000_000) - 1_000_000_000
+		k := rnd.Int63n(8192) - 4096
+		factor := float64(k) / 1024
+		if got, want := FromMinor(minor).Mul(factor).Minor(), roundHalfAway(minor*k, 1024); got != want {
+			t.Fatalf("Amount(%d).Mul(%v) = %d, want %d", minor, factor, got, want)
+		}
+	}
+}
+
+func TestNumericRoundTrip(t *testing.T) {
+	m := pgtype.NewMap()
+	roundTrip := func(minor int64, format int16) bool {
+		amount := FromMinor(minor)
+		buf, err := m.Encode(pgtype.NumericOID, format, amount, nil)
+		if err != nil {
+			t.Logf("encode %d: %v", minor, err)
+			return false
+		}
+		var got Amount
+		if err := m.Scan(pgtype.NumericOID, format, buf, &got); err != nil {
+			t.Logf("scan %d: %v", minor, err)
+			return false
+		}
+		return got == amount
+	}
+	for _, format := range []int16{pgtype.TextFormatCode, pgtype.BinaryFormatCode} {
+		err := quick.Check(func(minor int64) bool { return roundTrip(minor, format) }, &quick.Config{MaxCount: 2000})
+		if err != nil {
+			t.Errorf("format %d: %v", format, err)
+		}
+		for _, minor := range []int64{0, 1, -1, 100, -100, math.MaxInt64, math.MinInt64} {
+			if !roundTrip(minor, format) {
+				t.Errorf("format %d: round trip failed for %d", format, minor)
+			}
+		}
+	}
+}
+
+func TestScanNumeric(t *testing.T) {
+	m := pgtype.NewMap()
+	tests := []struct {
+		in   string
+		want Amount
+	}{
+		{"12.5", 1250},
+		{"12.345", 1235},
+		{"-12.345", -1235},
+		{"12.3449", 1234},
+		{"1000", 100000},
+		{"0.004", 0},
+	}
+	for _, tt := range tests {
+		var got Amount
+		if err := m.Scan(pgtype.NumericOID, pgtype.TextFormatCode, []byte(tt.in), &got); err != nil {
+			t.Errorf("scan %q: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("scan %q = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+
+	var amount Amount
+	if err := amount.ScanNumeric(pgtype.Numeric{}); err == nil {
+		t.Error("scan NULL expected error")
+	}
+	if err := amount.ScanNumeric(pgtype.Numeric{NaN: true, Valid: true}); err == nil {
+		t.Error("scan NaN expected error")
+	}
+}
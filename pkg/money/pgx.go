@@ -0,0 +1,50 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ScanNumeric читает сумму из колонки numeric. Значения с большим числом знаков после запятой
+// (например, результат пересчета по курсу) округляются до копеек, половина - от нуля
+func (a *Amount) ScanNumeric(v pgtype.Numeric) error {
+	if !v.Valid {
+		return fmt.Errorf("нельзя записать NULL в денежную сумму")
+	}
+	if v.NaN || v.InfinityModifier != pgtype.Finite {
+		return fmt.Errorf("нельзя записать %v в денежную сумму", v.InfinityModifier)
+	}
+	exp := int64(v.Exp) + Scale
+	r := new(big.Rat).SetInt(v.Int)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(abs(exp)), nil)
+	if exp >= 0 {
+		r.Mul(r, new(big.Rat).SetInt(scale))
+	} else {
+		r.Quo(r, new(big.Rat).SetInt(scale))
+	}
+	rounded := roundRat(r)
+	if !rounded.IsInt64() {
+		return fmt.Errorf("сумма %s слишком большая", v.Int.String())
+	}
+	*a = Amount(rounded.Int64())
+	return nil
+}
+
+// NumericValue передает сумму в параметр numeric без потери точности
+func (a Amount) NumericValue() (pgtype.Numeric, error) {
+	return pgtype.Numeric{Int: big.NewInt(int64(a)), Exp: -Scale, Valid: true}, nil
+}
+
+// Float64Value передает сумму в параметр double precision
+func (a Amount) Float64Value() (pgtype.Float8, error) {
+	return pgtype.Float8{Float64: a.Float64(), Valid: true}, nil
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,145 @@
+// Package password хэширует и проверяет пароли пользователей.
+//
+// Новые хэши создаются алгоритмом argon2id и хранятся в формате PHC:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<соль>$<хэш>
+//
+// Алгоритм, его версия и параметры записаны в самом хэше, поэтому параметры можно менять без миграции:
+// старые хэши проверяются со своими параметрами, а Verify сообщает, что их пора перехэшировать.
+// Кроме argon2id проверяются хэши bcrypt ($2a$, $2b$, $2y$) и устаревшие хэши SHA-1 без префикса
+package password
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Params - настраиваемые параметры argon2id
+type Params struct {
+	Memory      uint32 // объем памяти в КиБ
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32 // длина соли в байтах
+	KeyLength   uint32 // длина хэша в байтах
+}
+
+// DefaultParams - параметры по умолчанию, рекомендованные RFC 9106 для систем с ограниченной памятью
+var DefaultParams = Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+const argon2idPrefix = "$argon2id$"
+
+// Hasher хэширует пароли с текущими параметрами и проверяет хэши всех поддерживаемых форматов
+type Hasher struct {
+	params Params
+	// legacySecret - значение SECRET_HASH, с которым создавались хэши SHA-1
+	legacySecret string
+}
+
+// New создает Hasher. legacySecret нужен только для проверки устаревших хэшей SHA-1
+func New(params Params, legacySecret string) *Hasher {
+	return &Hasher{
+		params:       params,
+		legacySecret: legacySecret,
+	}
+}
+
+// Hash возвращает хэш argon2id пароля со случайной солью в формате PHC
+func (h *Hasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("не удалось сгенерировать соль: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, h.params.Iterations, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		h.params.Memory,
+		h.params.Iterations,
+		h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify проверяет пароль по хэшу любого поддерживаемого формата. needsRehash = true, если пароль верный,
+// но хэш создан другим алгоритмом или с другими параметрами и его нужно заменить результатом Hash
+func (h *Hasher) Verify(password string, encoded string) (ok bool, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		return h.verifyArgon2id(password, encoded)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, fmt.Errorf("неверный хэш bcrypt: %w", err)
+		}
+		return true, true, nil
+	case strings.HasPrefix(encoded, "$"):
+		return false, false, errors.New("неподдерживаемый формат хэша пароля")
+	default:
+		return h.verifyLegacySHA1(password, encoded), true, nil
+	}
+}
+
+func (h *Hasher) verifyArgon2id(password string, encoded string) (bool, bool, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", соль, хэш
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return false, false, errors.New("неверный формат хэша argon2id")
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return false, false, fmt.Errorf("неверная версия хэша argon2id: %w", err)
+	}
+	if version != argon2.Version {
+		return false, false, fmt.Errorf("неподдерживаемая версия argon2id: %d", version)
+	}
+	var params Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return false, false, fmt.Errorf("неверные параметры хэша argon2id: %w", err)
+	}
+	if params.Iterations == 0 || params.Parallelism == 0 {
+		return false, false, errors.New("неверные параметры хэша argon2id")
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false, false, fmt.Errorf("неверная соль хэша argon2id: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false, false, errors.New("неверный хэш argon2id")
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return false, false, nil
+	}
+	return true, params != h.params, nil
+}
+
+// verifyLegacySHA1 проверяет хэш прежнего формата: hex(SECRET_HASH || sha1(пароль)).
+// Секрет в таком хэше не участвует в вычислении, поэтому хэш подлежит замене при первом успешном входе
+func (h *Hasher) verifyLegacySHA1(password string, encoded string) bool {
+	digest := sha1.Sum([]byte(password))
+	expected := hex.EncodeToString(append([]byte(h.legacySecret), digest[:]...))
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(encoded)) == 1
+}
@@ -0,0 +1,155 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams - облегченные параметры, чтобы тесты не тратили 64 МиБ на каждый хэш
+var testParams = Params{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+const testSecret = "legacy-secret"
+
+func legacyHash(secret, password string) string {
+	digest := sha1.Sum([]byte(password))
+	return hex.EncodeToString(append([]byte(secret), digest[:]...))
+}
+
+func TestHashVerify(t *testing.T) {
+	h := New(testParams, testSecret)
+	encoded, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("unexpected hash format: %s", encoded)
+	}
+
+	ok, needsRehash, err := h.Verify("correct horse", encoded)
+	if err != nil || !ok || needsRehash {
+		t.Errorf("Verify(correct) = %v, %v, %v; want true, false, nil", ok, needsRehash, err)
+	}
+	ok, needsRehash, err = h.Verify("wrong horse", encoded)
+	if err != nil || ok || needsRehash {
+		t.Errorf("Verify(wrong) = %v, %v, %v; want false, false, nil", ok, needsRehash, err)
+	}
+
+	// Соль случайная: одинаковые пароли дают разные хэши
+	again, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if again == encoded {
+		t.Error("expected different hashes for the same password")
+	}
+}
+
+func TestVerifyParamsChanged(t *testing.T) {
+	old := New(testParams, testSecret)
+	encoded, err := old.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+
+	stronger := testParams
+	stronger.Iterations = 2
+	ok, needsRehash, err := New(stronger, testSecret).Verify("secret", encoded)
+	if err != nil || !ok || !needsRehash {
+		t.Errorf("Verify with new params = %v, %v, %v; want true, true, nil", ok, needsRehash, err)
+	}
+	ok, needsRehash, err = New(stronger, testSecret).Verify("other", encoded)
+	if err != nil || ok || needsRehash {
+		t.Errorf("Verify(wrong) with new params = %v, %v, %v; want false, false, nil", ok, needsRehash, err)
+	}
+}
+
+func TestVerifyLegacySHA1(t *testing.T) {
+	h := New(testParams, testSecret)
+	encoded := legacyHash(testSecret, "qwerty")
+
+	ok, needsRehash, err := h.Verify("qwerty", encoded)
+	if err != nil || !ok || !needsRehash {
+		t.Errorf("Verify(correct) = %v, %v, %v; want true, true, nil", ok, needsRehash, err)
+	}
+	if ok, _, err := h.Verify("qwerty1", encoded); err != nil || ok {
+		t.Errorf("Verify(wrong) = %v, %v; want false, nil", ok, err)
+	}
+	// Хэш, созданный с другим SECRET_HASH, не подходит
+	if ok, _, err := h.Verify("qwerty", legacyHash("other-secret", "qwerty")); err != nil || ok {
+		t.Errorf("Verify(other secret) = %v, %v; want false, nil", ok, err)
+	}
+	if ok, _, err := h.Verify("qwerty", ""); err != nil || ok {
+		t.Errorf("Verify(empty hash) = %v, %v; want false, nil", ok, err)
+	}
+
+	// После перехэширования пароль проверяется уже по argon2id
+	rehashed, err := h.Hash("qwerty")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if ok, needsRehash, err := h.Verify("qwerty", rehashed); err != nil || !ok || needsRehash {
+		t.Errorf("Verify(rehashed) = %v, %v, %v; want true, false, nil", ok, needsRehash, err)
+	}
+}
+
+func TestVerifyBcrypt(t *testing.T) {
+	h := New(testParams, testSecret)
+	hashed, err := bcrypt.GenerateFromPassword([]byte("letmein"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt error: %v", err)
+	}
+
+	ok, needsRehash, err := h.Verify("letmein", string(hashed))
+	if err != nil || !ok || !needsRehash {
+		t.Errorf("Verify(correct) = %v, %v, %v; want true, true, nil", ok, needsRehash, err)
+	}
+	ok, needsRehash, err = h.Verify("letmeout", string(hashed))
+	if err != nil || ok || needsRehash {
+		t.Errorf("Verify(wrong) = %v, %v, %v; want false, false, nil", ok, needsRehash, err)
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	h := New(testParams, testSecret)
+	valid, err := h.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	parts := strings.Split(valid, "$")
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"unknown scheme", "$scrypt$ln=15,r=8,p=1$c2FsdA$aGFzaA"},
+		{"argon2i", "$argon2i$v=19$m=64,t=1,p=1$" + parts[4] + "$" + parts[5]},
+		{"missing parts", "$argon2id$v=19$m=64,t=1,p=1$" + parts[4]},
+		{"bad version", "$argon2id$v=16$m=64,t=1,p=1$" + parts[4] + "$" + parts[5]},
+		{"bad params", "$argon2id$v=19$m=64,t=x,p=1$" + parts[4] + "$" + parts[5]},
+		{"zero iterations", "$argon2id$v=19$m=64,t=0,p=1$" + parts[4] + "$" + parts[5]},
+		{"bad salt", "$argon2id$v=19$m=64,t=1,p=1$!!!$" + parts[5]},
+		{"empty key", "$argon2id$v=19$m=64,t=1,p=1$" + parts[4] + "$"},
+		{"broken bcrypt", "$2a$04$short"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, needsRehash, err := h.Verify("secret", tt.encoded)
+			if err == nil {
+				t.Error("expected error")
+			}
+			if ok || needsRehash {
+				t.Errorf("Verify = %v, %v; want false, false", ok, needsRehash)
+			}
+		})
+	}
+}
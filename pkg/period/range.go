@@ -0,0 +1,101 @@
+// Package period нарезает время на календарные периоды для бюджетов и аналитики
+package period
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// DateRange - полуоткрытый интервал дат [Start, End)
+type DateRange struct {
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+// Days возвращает число календарных дней в интервале
+func (r DateRange) Days() int {
+	return daysBetween(r.Start, r.End)
+}
+
+// ElapsedDays возвращает число дней интервала, прошедших к now включительно.
+// Для текущего месяца среднее считается по уже прошедшим дням, а не по всему месяцу
+func (r DateRange) ElapsedDays(now time.Time) int {
+	end := r.End
+	tomorrow := startOfDay(now).AddDate(0, 0, 1)
+	if tomorrow.Before(end) {
+		end = tomorrow
+	}
+	if !r.Start.Before(end) {
+		return 0
+	}
+	return daysBetween(r.Start, end)
+}
+
+// newDateRange подписывает интервал: календарные периоды как "2026-03", "2026-Q1", "2026", "2026-W10",
+// остальные как "2026-03-01/2026-03-15" с включительной датой конца
+func newDateRange(start, end time.Time) DateRange {
+	label := start.Format("2006-01-02") + "/" + end.AddDate(0, 0, -1).Format("2006-01-02")
+	switch months := calendarMonths(start, end); {
+	case months == 1:
+		label = start.Format("2006-01")
+	case months == 3 && (start.Month()-1)%3 == 0:
+		label = fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	case months == 12 && start.Month() == time.January:
+		label = strconv.Itoa(start.Year())
+	case start.Weekday() == time.Monday && start.AddDate(0, 0, 7).Equal(end):
+		year, week := start.ISOWeek()
+		label = fmt.Sprintf("%d-W%02d", year, week)
+	}
+	return DateRange{Label: label, Start: start, End: end}
+}
+
+// calendarMonths возвращает 1, 3 или 12, если интервал - ровно столько целых месяцев с первого числа, иначе 0
+func calendarMonths(start, end time.Time) int {
+	if start.Day() != 1 {
+		return 0
+	}
+	for _, months := range []int{1, 3, 12} {
+		if start.AddDate(0, months, 0).Equal(end) {
+			return months
+		}
+	}
+	return 0
+}
+
+// Previous возвращает предыдущий период той же длины: для месяца, квартала и года - предыдущий
+// календарный период, для остальных интервалов - столько же дней непосредственно перед Start
+func (r DateRange) Previous() DateRange {
+	if months := calendarMonths(r.Start, r.End); months > 0 {
+		return newDateRange(r.Start.AddDate(0, -months, 0), r.Start)
+	}
+	return newDateRange(r.Start.AddDate(0, 0, -r.Days()), r.Start)
+}
+
+// YearAgo возвращает тот же период годом раньше. Неделя сдвигается на 52 недели, чтобы остаться с понедельника
+func (r DateRange) YearAgo() DateRange {
+	if r.Start.Weekday() == time.Monday && r.Days() == 7 {
+		return newDateRange(r.Start.AddDate(0, 0, -364), r.End.AddDate(0, 0, -364))
+	}
+	return newDateRange(r.Start.AddDate(-1, 0, 0), r.End.AddDate(-1, 0, 0))
+}
+
+// Truncate оставляет не больше days первых дней интервала
+func (r DateRange) Truncate(days int) DateRange {
+	end := r.Start.AddDate(0, 0, days)
+	if end.After(r.End) {
+		end = r.End
+	}
+	return newDateRange(r.Start, end)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween считает календарные дни, округление убирает сдвиг при переходе на летнее время
+func daysBetween(start, end time.Time) int {
+	return int(math.Round(end.Sub(start).Hours() / 24))
+}
@@ -0,0 +1,113 @@
+package period
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestDaysAcrossDST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name string
+		spec Spec
+		t    time.Time
+		days int
+	}{
+		// 29 марта 2026 в Берлине переход на летнее время: в сутках 23 часа
+		{"month with spring forward", DefaultSpec(Monthly), day(2026, 3, 15), 31},
+		{"week with spring forward", DefaultSpec(Weekly), day(2026, 3, 27), 7},
+		// 25 октября 2026 - переход на зимнее время: в сутках 25 часов
+		{"month with fall back", DefaultSpec(Monthly), day(2026, 10, 15), 31},
+		{"week with fall back", DefaultSpec(Weekly), day(2026, 10, 23), 7},
+		{"year", DefaultSpec(Yearly), day(2026, 6, 1), 365},
+		{"leap year", DefaultSpec(Yearly), day(2024, 6, 1), 366},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Containing(tt.t)
+			if err != nil {
+				t.Fatalf("Containing error: %v", err)
+			}
+			if got.Days() != tt.days {
+				t.Errorf("Days() = %d, want %d (%s)", got.Days(), tt.days, got.End.Sub(got.Start))
+			}
+		})
+	}
+
+	// Интервал с переходом на летнее время на час короче целого числа суток
+	spring, err := Explicit(day(2026, 3, 28), day(2026, 3, 30))
+	if err != nil {
+		t.Fatalf("Explicit error: %v", err)
+	}
+	if spring.End.Sub(spring.Start) != 71*time.Hour {
+		t.Fatalf("expected DST in range, got %s", spring.End.Sub(spring.Start))
+	}
+	if spring.Days() != 3 {
+		t.Errorf("Days() = %d, want 3", spring.Days())
+	}
+	if got := spring.ElapsedDays(time.Date(2026, 3, 29, 12, 0, 0, 0, berlin)); got != 2 {
+		t.Errorf("ElapsedDays() = %d, want 2", got)
+	}
+}
+
+func TestElapsedDays(t *testing.T) {
+	march := DateRange{Start: date(2026, 3, 1), End: date(2026, 4, 1)}
+	tests := []struct {
+		name string
+		now  time.Time
+		want int
+	}{
+		{"first day", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), 1},
+		{"middle of month", time.Date(2026, 3, 11, 15, 0, 0, 0, time.UTC), 11},
+		{"last day", time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC), 31},
+		{"after period", date(2026, 5, 1), 31},
+		{"before period", date(2026, 2, 10), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := march.ElapsedDays(tt.now); got != tt.want {
+				t.Errorf("ElapsedDays() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreviousAndYearAgo(t *testing.T) {
+	tests := []struct {
+		name      string
+		r         DateRange
+		prevStart time.Time
+		prevEnd   time.Time
+		agoStart  time.Time
+		agoEnd    time.Time
+	}{
+		{"month", newDateRange(date(2026, 3, 1), date(2026, 4, 1)),
+			date(2026, 2, 1), date(2026, 3, 1), date(2025, 3, 1), date(2025, 4, 1)},
+		{"quarter", newDateRange(date(2026, 1, 1), date(2026, 4, 1)),
+			date(2025, 10, 1), date(2026, 1, 1), date(2025, 1, 1), date(2025, 4, 1)},
+		{"week keeps monday", newDateRange(date(2026, 3, 9), date(2026, 3, 16)),
+			date(2026, 3, 2), date(2026, 3, 9), date(2025, 3, 10), date(2025, 3, 17)},
+		{"explicit days", newDateRange(date(2026, 3, 5), date(2026, 3, 15)),
+			date(2026, 2, 23), date(2026, 3, 5), date(2025, 3, 5), date(2025, 3, 15)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertRange(t, "Previous", tt.r.Previous(), tt.prevStart, tt.prevEnd)
+			assertRange(t, "YearAgo", tt.r.YearAgo(), tt.agoStart, tt.agoEnd)
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	march := newDateRange(date(2026, 3, 1), date(2026, 4, 1))
+	assertRange(t, "Truncate(10)", march.Truncate(10), date(2026, 3, 1), date(2026, 3, 11))
+	assertRange(t, "Truncate(40)", march.Truncate(40), date(2026, 3, 1), date(2026, 4, 1))
+}
@@ -0,0 +1,105 @@
+package period
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Resolve превращает период аналитики в интервал дат.
+// period задает календарный период: weekly, monthly, quarterly, yearly (текущие неделя, месяц, квартал, год),
+// "2026-03" (месяц), "2026-Q1" или "Q1" (квартал), "2026" (год).
+// Вместо period можно передать явный диапазон from/to, to включается в период целиком.
+// Если не задано ничего, возвращается текущий месяц
+func Resolve(period string, from, to, now time.Time) (DateRange, error) {
+	period = strings.TrimSpace(period)
+	if period != "" && (!from.IsZero() || !to.IsZero()) {
+		return DateRange{}, errors.New("period and from/to cannot be used together")
+	}
+	if !from.IsZero() || !to.IsZero() {
+		return explicitRange(from, to, now)
+	}
+	if period == "" {
+		period = "monthly"
+	}
+
+	today := startOfDay(now)
+	switch strings.ToLower(period) {
+	case Weekly, "week":
+		// в аналитике неделя начинается с понедельника
+		return DefaultSpec(Weekly).Containing(today)
+	case Monthly, "month":
+		return DefaultSpec(Monthly).Containing(today)
+	case "quarterly", "quarter":
+		return quarterRange(today.Year(), (int(today.Month())-1)/3+1, today.Location()), nil
+	case Yearly, "year":
+		return DefaultSpec(Yearly).Containing(today)
+	}
+
+	if t, err := time.ParseInLocation("2006-01", period, today.Location()); err == nil {
+		return monthRange(t.Year(), t.Month(), today.Location()), nil
+	}
+	upper := strings.ToUpper(period)
+	if year, quarter, ok := parseQuarter(upper, today.Year()); ok {
+		return quarterRange(year, quarter, today.Location()), nil
+	}
+	if len(period) == 4 {
+		if year, err := strconv.Atoi(period); err == nil && year > 0 {
+			return yearRange(year, today.Location()), nil
+		}
+	}
+	return DateRange{}, fmt.Errorf("unsupported period: %s. Allowed values: weekly, monthly, quarterly, yearly, YYYY-MM, YYYY-Qn, Qn, YYYY", period)
+}
+
+func explicitRange(from, to, now time.Time) (DateRange, error) {
+	if from.IsZero() {
+		return DateRange{}, errors.New("from is required")
+	}
+	start := startOfDay(from)
+	if to.IsZero() {
+		to = now
+	}
+	end := startOfDay(to).AddDate(0, 0, 1)
+	if !start.Before(end) {
+		return DateRange{}, errors.New("from must not be after to")
+	}
+	return newDateRange(start, end), nil
+}
+
+// parseQuarter разбирает "2026-Q1" и "Q1", для второй формы год берется из defaultYear
+func parseQuarter(value string, defaultYear int) (int, int, bool) {
+	year := defaultYear
+	if idx := strings.Index(value, "-Q"); idx > 0 {
+		y, err := strconv.Atoi(value[:idx])
+		if err != nil || y <= 0 {
+			return 0, 0, false
+		}
+		year = y
+		value = value[idx+1:]
+	}
+	if len(value) != 2 || value[0] != 'Q' {
+		return 0, 0, false
+	}
+	quarter := int(value[1] - '0')
+	if quarter < 1 || quarter > 4 {
+		return 0, 0, false
+	}
+	return year, quarter, true
+}
+
+func monthRange(year int, month time.Month, loc *time.Location) DateRange {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return newDateRange(start, start.AddDate(0, 1, 0))
+}
+
+func quarterRange(year, quarter int, loc *time.Location) DateRange {
+	start := time.Date(year, time.Month((quarter-1)*3+1), 1, 0, 0, 0, 0, loc)
+	return newDateRange(start, start.AddDate(0, 3, 0))
+}
+
+func yearRange(year int, loc *time.Location) DateRange {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	return newDateRange(start, start.AddDate(1, 0, 0))
+}
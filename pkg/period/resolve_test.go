@@ -0,0 +1,73 @@
+package period
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	// 2026-03-11 - среда
+	now := time.Date(2026, 3, 11, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		period string
+		from   time.Time
+		to     time.Time
+		start  time.Time
+		end    time.Time
+		label  string
+	}{
+		{"default is current month", "", time.Time{}, time.Time{}, date(2026, 3, 1), date(2026, 4, 1), "2026-03"},
+		{"weekly", "weekly", time.Time{}, time.Time{}, date(2026, 3, 9), date(2026, 3, 16), "2026-W11"},
+		{"monthly", "monthly", time.Time{}, time.Time{}, date(2026, 3, 1), date(2026, 4, 1), "2026-03"},
+		{"quarterly", "quarterly", time.Time{}, time.Time{}, date(2026, 1, 1), date(2026, 4, 1), "2026-Q1"},
+		{"yearly", "Yearly", time.Time{}, time.Time{}, date(2026, 1, 1), date(2027, 1, 1), "2026"},
+		{"YYYY-MM", "2026-02", time.Time{}, time.Time{}, date(2026, 2, 1), date(2026, 3, 1), "2026-02"},
+		{"YYYY-MM december", "2025-12", time.Time{}, time.Time{}, date(2025, 12, 1), date(2026, 1, 1), "2025-12"},
+		{"Qn of current year", "Q2", time.Time{}, time.Time{}, date(2026, 4, 1), date(2026, 7, 1), "2026-Q2"},
+		{"lowercase qn", "q4", time.Time{}, time.Time{}, date(2026, 10, 1), date(2027, 1, 1), "2026-Q4"},
+		{"YYYY-Qn", "2025-Q4", time.Time{}, time.Time{}, date(2025, 10, 1), date(2026, 1, 1), "2025-Q4"},
+		{"YYYY", "2024", time.Time{}, time.Time{}, date(2024, 1, 1), date(2025, 1, 1), "2024"},
+		{"from/to includes to", "", date(2026, 3, 1), date(2026, 3, 10), date(2026, 3, 1), date(2026, 3, 11), "2026-03-01/2026-03-10"},
+		{"from/to single day", "", date(2026, 3, 5), date(2026, 3, 5), date(2026, 3, 5), date(2026, 3, 6), "2026-03-05/2026-03-05"},
+		{"from/to whole month", "", date(2026, 2, 1), date(2026, 2, 28), date(2026, 2, 1), date(2026, 3, 1), "2026-02"},
+		{"from without to ends today", "", date(2026, 3, 1), time.Time{}, date(2026, 3, 1), date(2026, 3, 12), "2026-03-01/2026-03-11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.period, tt.from, tt.to, now)
+			if err != nil {
+				t.Fatalf("Resolve error: %v", err)
+			}
+			assertRange(t, "Resolve", got, tt.start, tt.end)
+			if got.Label != tt.label {
+				t.Errorf("Label = %q, want %q", got.Label, tt.label)
+			}
+		})
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	now := time.Date(2026, 3, 11, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		period string
+		from   time.Time
+		to     time.Time
+	}{
+		{"unknown period", "daily", time.Time{}, time.Time{}},
+		{"quarter out of range", "Q5", time.Time{}, time.Time{}},
+		{"month out of range", "2026-13", time.Time{}, time.Time{}},
+		{"bad year in quarter", "abcd-Q1", time.Time{}, time.Time{}},
+		{"period with from", "monthly", date(2026, 3, 1), time.Time{}},
+		{"to without from", "", time.Time{}, date(2026, 3, 1)},
+		{"from after to", "", date(2026, 3, 10), date(2026, 3, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Resolve(tt.period, tt.from, tt.to, now); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package period
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Периоды бюджетов
+const (
+	Weekly  = "weekly"
+	Monthly = "monthly"
+	Yearly  = "yearly"
+	// Custom - произвольный интервал, границы которого задаются явно
+	Custom = "custom"
+)
+
+// Spec - правило нарезки времени на периоды бюджета.
+// Для недели важен WeekStart, для месяца - MonthStartDay (например, день зарплаты 25-го),
+// для года - YearStartMonth и MonthStartDay (начало финансового года)
+type Spec struct {
+	Period         string
+	WeekStart      time.Weekday
+	MonthStartDay  int
+	YearStartMonth time.Month
+}
+
+// DefaultSpec возвращает календарные периоды: неделя с понедельника, месяц с 1-го числа, год с 1 января
+func DefaultSpec(period string) Spec {
+	return Spec{
+		Period:         period,
+		WeekStart:      time.Monday,
+		MonthStartDay:  1,
+		YearStartMonth: time.January,
+	}
+}
+
+// Validate проверяет период и его точки отсчета
+func (s Spec) Validate() error {
+	switch s.Period {
+	case Weekly, Monthly, Yearly, Custom:
+	default:
+		return fmt.Errorf("unsupported period: %s. Allowed values: weekly, monthly, yearly, custom", s.Period)
+	}
+	if s.WeekStart < time.Sunday || s.WeekStart > time.Saturday {
+		return errors.New("week_start must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	if s.MonthStartDay < 1 || s.MonthStartDay > 31 {
+		return errors.New("month_start_day must be between 1 and 31")
+	}
+	if s.YearStartMonth < time.January || s.YearStartMonth > time.December {
+		return errors.New("year_start_month must be between 1 and 12")
+	}
+	return nil
+}
+
+// Containing возвращает период, в который попадает t.
+// Если в месяце меньше дней, чем MonthStartDay, период начинается в последний день месяца
+func (s Spec) Containing(t time.Time) (DateRange, error) {
+	if err := s.Validate(); err != nil {
+		return DateRange{}, err
+	}
+	day := startOfDay(t)
+	switch s.Period {
+	case Weekly:
+		offset := (int(day.Weekday()) - int(s.WeekStart) + 7) % 7
+		start := day.AddDate(0, 0, -offset)
+		return newDateRange(start, start.AddDate(0, 0, 7)), nil
+	case Monthly:
+		year, month := day.Year(), day.Month()
+		if day.Before(anchorDate(year, month, s.MonthStartDay, day.Location())) {
+			year, month = shiftMonth(year, month, -1)
+		}
+		nextYear, nextMonth := shiftMonth(year, month, 1)
+		return newDateRange(
+			anchorDate(year, month, s.MonthStartDay, day.Location()),
+			anchorDate(nextYear, nextMonth, s.MonthStartDay, day.Location()),
+		), nil
+	case Yearly:
+		year := day.Year()
+		if day.Before(anchorDate(year, s.YearStartMonth, s.MonthStartDay, day.Location())) {
+			year--
+		}
+		return newDateRange(
+			anchorDate(year, s.YearStartMonth, s.MonthStartDay, day.Location()),
+			anchorDate(year+1, s.YearStartMonth, s.MonthStartDay, day.Location()),
+		), nil
+	default:
+		return DateRange{}, errors.New("custom period boundaries must be set explicitly")
+	}
+}
+
+// Next возвращает период, следующий сразу за r. Если r не выровнен по точкам отсчета
+// (например, бюджет создан до появления календарных периодов), следующий период начинается
+// с r.End и заканчивается на ближайшей границе календарного периода.
+// Для custom следующий период имеет ту же длину, что и r
+func (s Spec) Next(r DateRange) (DateRange, error) {
+	if s.Period == Custom {
+		return newDateRange(r.End, r.End.AddDate(0, 0, r.Days())), nil
+	}
+	next, err := s.Containing(r.End)
+	if err != nil {
+		return DateRange{}, err
+	}
+	if next.Start.Equal(r.End) {
+		return next, nil
+	}
+	if next.End.Sub(r.End) < 24*time.Hour {
+		// r.End совпадает с концом дня накануне границы: берем полный следующий период
+		return s.Containing(next.End)
+	}
+	return newDateRange(r.End, next.End), nil
+}
+
+// Explicit возвращает интервал с дня start по день end включительно, как from/to в Resolve
+func Explicit(start, end time.Time) (DateRange, error) {
+	if start.IsZero() || end.IsZero() {
+		return DateRange{}, errors.New("custom period requires start and end dates")
+	}
+	start, end = startOfDay(start), startOfDay(end)
+	if end.Before(start) {
+		return DateRange{}, errors.New("period start must not be after its end")
+	}
+	return newDateRange(start, end.AddDate(0, 0, 1)), nil
+}
+
+// anchorDate возвращает day-е число месяца, а если в месяце меньше дней - его последний день
+func anchorDate(year int, month time.Month, day int, loc *time.Location) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	if day > last {
+		day = last
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+func shiftMonth(year int, month time.Month, delta int) (int, time.Month) {
+	t := time.Date(year, month+time.Month(delta), 1, 0, 0, 0, 0, time.UTC)
+	return t.Year(), t.Month()
+}
@@ -0,0 +1,187 @@
+package period
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func assertRange(t *testing.T, name string, got DateRange, start, end time.Time) {
+	t.Helper()
+	if !got.Start.Equal(start) || !got.End.Equal(end) {
+		t.Errorf("%s = [%s, %s), want [%s, %s)", name,
+			got.Start.Format("2006-01-02"), got.End.Format("2006-01-02"),
+			start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+}
+
+func TestSpecContaining(t *testing.T) {
+	weekly := func(start time.Weekday) Spec {
+		spec := DefaultSpec(Weekly)
+		spec.WeekStart = start
+		return spec
+	}
+	monthly := func(day int) Spec {
+		spec := DefaultSpec(Monthly)
+		spec.MonthStartDay = day
+		return spec
+	}
+	fiscal := DefaultSpec(Yearly)
+	fiscal.YearStartMonth = time.April
+
+	tests := []struct {
+		name  string
+		spec  Spec
+		t     time.Time
+		start time.Time
+		end   time.Time
+		label string
+	}{
+		// 2026-03-11 - среда
+		{"week from monday", weekly(time.Monday), date(2026, 3, 11), date(2026, 3, 9), date(2026, 3, 16), "2026-W11"},
+		{"week from sunday", weekly(time.Sunday), date(2026, 3, 11), date(2026, 3, 8), date(2026, 3, 15), "2026-03-08/2026-03-14"},
+		{"week from saturday", weekly(time.Saturday), date(2026, 3, 11), date(2026, 3, 7), date(2026, 3, 14), "2026-03-07/2026-03-13"},
+		{"week start day itself", weekly(time.Sunday), date(2026, 3, 8), date(2026, 3, 8), date(2026, 3, 15), "2026-03-08/2026-03-14"},
+		{"sunday in monday week", weekly(time.Monday), date(2026, 3, 8), date(2026, 3, 2), date(2026, 3, 9), "2026-W10"},
+		{"time of day ignored", weekly(time.Monday), time.Date(2026, 3, 15, 23, 59, 0, 0, time.UTC), date(2026, 3, 9), date(2026, 3, 16), "2026-W11"},
+
+		{"calendar month", monthly(1), date(2026, 3, 11), date(2026, 3, 1), date(2026, 4, 1), "2026-03"},
+		{"day 25 before anchor", monthly(25), date(2026, 2, 10), date(2026, 1, 25), date(2026, 2, 25), "2026-01-25/2026-02-24"},
+		{"day 25 on anchor", monthly(25), date(2026, 2, 25), date(2026, 2, 25), date(2026, 3, 25), "2026-02-25/2026-03-24"},
+		{"day 25 across year", monthly(25), date(2026, 1, 3), date(2025, 12, 25), date(2026, 1, 25), "2025-12-25/2026-01-24"},
+		{"day 31 in february", monthly(31), date(2026, 2, 15), date(2026, 1, 31), date(2026, 2, 28), "2026-01-31/2026-02-27"},
+		{"day 31 on last day of february", monthly(31), date(2026, 2, 28), date(2026, 2, 28), date(2026, 3, 31), "2026-02-28/2026-03-30"},
+		{"day 31 in leap february", monthly(31), date(2024, 2, 28), date(2024, 1, 31), date(2024, 2, 29), "2024-01-31/2024-02-28"},
+		{"day 31 on leap day", monthly(31), date(2024, 2, 29), date(2024, 2, 29), date(2024, 3, 31), "2024-02-29/2024-03-30"},
+		{"day 31 in 30-day month", monthly(31), date(2026, 4, 30), date(2026, 4, 30), date(2026, 5, 31), "2026-04-30/2026-05-30"},
+		{"day 30 in leap february", monthly(30), date(2024, 3, 1), date(2024, 2, 29), date(2024, 3, 30), "2024-02-29/2024-03-29"},
+
+		{"calendar year", DefaultSpec(Yearly), date(2026, 3, 11), date(2026, 1, 1), date(2027, 1, 1), "2026"},
+		{"fiscal year before april", fiscal, date(2026, 3, 31), date(2025, 4, 1), date(2026, 4, 1), "2025-04-01/2026-03-31"},
+		{"fiscal year from april", fiscal, date(2026, 4, 1), date(2026, 4, 1), date(2027, 4, 1), "2026-04-01/2027-03-31"},
+		{"fiscal year in december", fiscal, date(2026, 12, 31), date(2026, 4, 1), date(2027, 4, 1), "2026-04-01/2027-03-31"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Containing(tt.t)
+			if err != nil {
+				t.Fatalf("Containing error: %v", err)
+			}
+			assertRange(t, "Containing", got, tt.start, tt.end)
+			if got.Label != tt.label {
+				t.Errorf("Label = %q, want %q", got.Label, tt.label)
+			}
+		})
+	}
+}
+
+func TestSpecContainingInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec Spec
+	}{
+		{"unknown period", DefaultSpec("daily")},
+		{"custom", DefaultSpec(Custom)},
+		{"week start", Spec{Period: Weekly, WeekStart: 7, MonthStartDay: 1, YearStartMonth: time.January}},
+		{"month start day zero", Spec{Period: Monthly, MonthStartDay: 0, YearStartMonth: time.January}},
+		{"month start day 32", Spec{Period: Monthly, MonthStartDay: 32, YearStartMonth: time.January}},
+		{"year start month", Spec{Period: Yearly, MonthStartDay: 1, YearStartMonth: 13}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.spec.Containing(date(2026, 3, 11)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSpecNext(t *testing.T) {
+	monthly25 := DefaultSpec(Monthly)
+	monthly25.MonthStartDay = 25
+	monthly31 := DefaultSpec(Monthly)
+	monthly31.MonthStartDay = 31
+	weeklySunday := DefaultSpec(Weekly)
+	weeklySunday.WeekStart = time.Sunday
+	fiscal := DefaultSpec(Yearly)
+	fiscal.YearStartMonth = time.April
+
+	tests := []struct {
+		name      string
+		spec      Spec
+		current   DateRange
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"calendar month", DefaultSpec(Monthly), DateRange{Start: date(2026, 1, 1), End: date(2026, 2, 1)}, date(2026, 2, 1), date(2026, 3, 1)},
+		{"month across year", DefaultSpec(Monthly), DateRange{Start: date(2025, 12, 1), End: date(2026, 1, 1)}, date(2026, 1, 1), date(2026, 2, 1)},
+		{"day 25", monthly25, DateRange{Start: date(2026, 1, 25), End: date(2026, 2, 25)}, date(2026, 2, 25), date(2026, 3, 25)},
+		{"day 31 into february", monthly31, DateRange{Start: date(2025, 12, 31), End: date(2026, 1, 31)}, date(2026, 1, 31), date(2026, 2, 28)},
+		{"day 31 out of february", monthly31, DateRange{Start: date(2026, 1, 31), End: date(2026, 2, 28)}, date(2026, 2, 28), date(2026, 3, 31)},
+		{"day 31 out of leap february", monthly31, DateRange{Start: date(2024, 1, 31), End: date(2024, 2, 29)}, date(2024, 2, 29), date(2024, 3, 31)},
+		{"week from sunday", weeklySunday, DateRange{Start: date(2026, 3, 8), End: date(2026, 3, 15)}, date(2026, 3, 15), date(2026, 3, 22)},
+		{"fiscal year", fiscal, DateRange{Start: date(2025, 4, 1), End: date(2026, 4, 1)}, date(2026, 4, 1), date(2027, 4, 1)},
+		// Бюджет создан до появления точек отсчета: следующий период добирается до ближайшей границы
+		{"unaligned", DefaultSpec(Monthly), DateRange{Start: date(2026, 3, 10), End: date(2026, 3, 20)}, date(2026, 3, 20), date(2026, 4, 1)},
+		// Конец хранился как последний момент дня накануне границы
+		{"end at end of day", DefaultSpec(Monthly), DateRange{Start: date(2026, 2, 1), End: time.Date(2026, 2, 28, 23, 59, 59, 0, time.UTC)}, date(2026, 3, 1), date(2026, 4, 1)},
+		{"custom keeps length", DefaultSpec(Custom), DateRange{Start: date(2026, 3, 1), End: date(2026, 3, 11)}, date(2026, 3, 11), date(2026, 3, 21)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.spec.Next(tt.current)
+			if err != nil {
+				t.Fatalf("Next error: %v", err)
+			}
+			assertRange(t, "Next", got, tt.wantStart, tt.wantEnd)
+		})
+	}
+}
+
+func TestExplicit(t *testing.T) {
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+		label     string
+	}{
+		{"end date included", date(2026, 3, 1), date(2026, 3, 15), date(2026, 3, 1), date(2026, 3, 16), "2026-03-01/2026-03-15"},
+		{"single day", date(2026, 3, 1), date(2026, 3, 1), date(2026, 3, 1), date(2026, 3, 2), "2026-03-01/2026-03-01"},
+		{"whole month", date(2026, 3, 1), date(2026, 3, 31), date(2026, 3, 1), date(2026, 4, 1), "2026-03"},
+		{"time of day ignored", time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC), time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC), date(2026, 3, 1), date(2026, 3, 3), "2026-03-01/2026-03-02"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Explicit(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("Explicit error: %v", err)
+			}
+			assertRange(t, "Explicit", got, tt.wantStart, tt.wantEnd)
+			if got.Label != tt.label {
+				t.Errorf("Label = %q, want %q", got.Label, tt.label)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+	}{
+		{"end before start", date(2026, 3, 2), date(2026, 3, 1)},
+		{"no start", time.Time{}, date(2026, 3, 1)},
+		{"no end", date(2026, 3, 1), time.Time{}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Explicit(tt.start, tt.end); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
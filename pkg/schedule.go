@@ -0,0 +1,68 @@
+package pkg
+
+import (
+	"fmt"
+	"time"
+)
+
+// FirstOccurrence возвращает дату первого платежа регулярного расхода не раньше startDate.
+// Для ежемесячного и ежегодного расписания dayOfMonth задает день месяца платежа
+func FirstOccurrence(startDate time.Time, frequency string, dayOfMonth int) (time.Time, error) {
+	switch frequency {
+	case "daily", "weekly":
+		return startDate, nil
+	case "monthly", "yearly":
+		first := dateWithDay(startDate, startDate.Year(), startDate.Month(), dayOfMonth)
+		if first.Before(startDate) {
+			return NextOccurrence(first, frequency, dayOfMonth)
+		}
+		return first, nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported frequency: %s. Allowed values: daily, weekly, monthly, yearly", frequency)
+	}
+}
+
+// NextOccurrence возвращает дату платежа, следующего за current.
+// Если в месяце меньше дней, чем dayOfMonth, платеж переносится на последний день месяца
+func NextOccurrence(current time.Time, frequency string, dayOfMonth int) (time.Time, error) {
+	switch frequency {
+	case "daily":
+		return current.AddDate(0, 0, 1), nil
+	case "weekly":
+		return current.AddDate(0, 0, 7), nil
+	case "monthly":
+		return dateWithDay(current, current.Year(), current.Month()+1, dayOfMonth), nil
+	case "yearly":
+		return dateWithDay(current, current.Year()+1, current.Month(), dayOfMonth), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported frequency: %s. Allowed values: daily, weekly, monthly, yearly", frequency)
+	}
+}
+
+// OccurrenceNotBefore возвращает первую дату платежа по расписанию, начиная с current, которая не раньше from.
+// Пропущенные платежи между current и from не возвращаются
+func OccurrenceNotBefore(current time.Time, frequency string, dayOfMonth int, from time.Time) (time.Time, error) {
+	for current.Before(from) {
+		var err error
+		current, err = NextOccurrence(current, frequency, dayOfMonth)
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+	return current, nil
+}
+
+// dateWithDay собирает дату из года, месяца и дня, ограничивая день длиной месяца.
+// Время суток и часовой пояс берутся из clock
+func dateWithDay(clock time.Time, year int, month time.Month, day int) time.Time {
+	// time.Date нормализует месяц за пределами 1..12, поэтому сначала берем первое число
+	first := time.Date(year, month, 1, clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), clock.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	if day > last {
+		day = last
+	}
+	if day < 1 {
+		day = 1
+	}
+	return first.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func at(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+}
+
+func TestFirstOccurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		start      time.Time
+		frequency  string
+		dayOfMonth int
+		want       time.Time
+	}{
+		{"daily starts on start date", at(2026, 3, 20), "daily", 0, at(2026, 3, 20)},
+		{"weekly starts on start date", at(2026, 3, 20), "weekly", 0, at(2026, 3, 20)},
+		{"monthly day later this month", at(2026, 3, 10), "monthly", 15, at(2026, 3, 15)},
+		{"monthly day on start date", at(2026, 3, 15), "monthly", 15, at(2026, 3, 15)},
+		{"monthly day already passed", at(2026, 3, 20), "monthly", 15, at(2026, 4, 15)},
+		{"monthly day 31 in february", at(2026, 2, 10), "monthly", 31, at(2026, 2, 28)},
+		{"monthly day 31 in leap february", at(2024, 2, 10), "monthly", 31, at(2024, 2, 29)},
+		{"monthly day 31 in april", at(2026, 4, 1), "monthly", 31, at(2026, 4, 30)},
+		{"yearly day later this month", at(2026, 3, 1), "yearly", 10, at(2026, 3, 10)},
+		{"yearly day already passed", at(2026, 3, 20), "yearly", 10, at(2027, 3, 10)},
+		{"yearly leap day in common year", at(2025, 2, 1), "yearly", 29, at(2025, 2, 28)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FirstOccurrence(tt.start, tt.frequency, tt.dayOfMonth)
+			if err != nil {
+				t.Fatalf("FirstOccurrence error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("FirstOccurrence = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := FirstOccurrence(at(2026, 3, 1), "hourly", 0); err == nil {
+		t.Error("expected error for unsupported frequency")
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    time.Time
+		frequency  string
+		dayOfMonth int
+		want       time.Time
+	}{
+		{"daily", at(2026, 3, 31), "daily", 0, at(2026, 4, 1)},
+		{"daily across year", at(2025, 12, 31), "daily", 0, at(2026, 1, 1)},
+		{"weekly", at(2026, 3, 27), "weekly", 0, at(2026, 4, 3)},
+		{"monthly", at(2026, 3, 15), "monthly", 15, at(2026, 4, 15)},
+		{"monthly across year", at(2025, 12, 15), "monthly", 15, at(2026, 1, 15)},
+		{"monthly day 31 into february", at(2026, 1, 31), "monthly", 31, at(2026, 2, 28)},
+		// После короткого месяца платеж возвращается на свой день, а не остается 28-м
+		{"monthly day 31 out of february", at(2026, 2, 28), "monthly", 31, at(2026, 3, 31)},
+		{"monthly day 31 into leap february", at(2024, 1, 31), "monthly", 31, at(2024, 2, 29)},
+		{"monthly day 30 into april", at(2026, 3, 30), "monthly", 30, at(2026, 4, 30)},
+		{"yearly", at(2026, 3, 10), "yearly", 10, at(2027, 3, 10)},
+		{"yearly leap day into common year", at(2024, 2, 29), "yearly", 29, at(2025, 2, 28)},
+		{"yearly leap day back into leap year", at(2027, 2, 28), "yearly", 29, at(2028, 2, 29)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextOccurrence(tt.current, tt.frequency, tt.dayOfMonth)
+			if err != nil {
+				t.Fatalf("NextOccurrence error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("NextOccurrence = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NextOccurrence(at(2026, 3, 1), "", 0); err == nil {
+		t.Error("expected error for unsupported frequency")
+	}
+}
+
+func TestOccurrenceNotBefore(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    time.Time
+		frequency  string
+		dayOfMonth int
+		from       time.Time
+		want       time.Time
+	}{
+		{"already in the future", at(2026, 5, 15), "monthly", 15, at(2026, 3, 1), at(2026, 5, 15)},
+		{"equal to from", at(2026, 3, 15), "monthly", 15, at(2026, 3, 15), at(2026, 3, 15)},
+		{"daily skips missed days", at(2026, 3, 1), "daily", 0, at(2026, 3, 20), at(2026, 3, 20)},
+		{"daily later in the day", at(2026, 3, 1), "daily", 0, time.Date(2026, 3, 20, 12, 0, 0, 0, time.UTC), at(2026, 3, 21)},
+		{"weekly keeps weekday", at(2026, 3, 2), "weekly", 0, at(2026, 3, 20), at(2026, 3, 23)},
+		{"monthly keeps day 31", at(2026, 1, 31), "monthly", 31, at(2026, 4, 15), at(2026, 4, 30)},
+		{"monthly day 31 after short month", at(2026, 1, 31), "monthly", 31, at(2026, 3, 1), at(2026, 3, 31)},
+		{"yearly", at(2023, 6, 1), "yearly", 1, at(2026, 3, 1), at(2026, 6, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OccurrenceNotBefore(tt.current, tt.frequency, tt.dayOfMonth, tt.from)
+			if err != nil {
+				t.Fatalf("OccurrenceNotBefore error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("OccurrenceNotBefore = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := OccurrenceNotBefore(at(2026, 3, 1), "hourly", 0, at(2026, 4, 1)); err == nil {
+		t.Error("expected error for unsupported frequency")
+	}
+}
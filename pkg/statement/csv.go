@@ -0,0 +1,192 @@
+package statement
+
+import (
+	"encoding/csv"
+	"errors"
+	"finance/pkg/money"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CSVMapping - какие колонки CSV содержат поля операции и в каком виде записаны даты и суммы.
+// Колонки задаются по названию в заголовке, регистр не важен
+type CSVMapping struct {
+	DateColumn        string
+	AmountColumn      string
+	DescriptionColumn string
+	CurrencyColumn    string // необязательная колонка
+	// DateFormat - шаблон даты из YYYY, YY, MM, DD, HH, mm, ss, например DD.MM.YYYY
+	DateFormat   string
+	Delimiter    rune
+	DecimalComma bool // суммы записаны с запятой: 1 234,56. Если в сумме есть и точка, и запятая, решает последний разделитель
+}
+
+// DefaultCSVMapping возвращает разметку для файла с колонками date, amount, description, currency
+// и датами в формате YYYY-MM-DD
+func DefaultCSVMapping() CSVMapping {
+	return CSVMapping{
+		DateColumn:        "date",
+		AmountColumn:      "amount",
+		DescriptionColumn: "description",
+		DateFormat:        "YYYY-MM-DD",
+		Delimiter:         ',',
+	}
+}
+
+// dateFormatTokens - замены элементов шаблона даты на раскладку time.Parse. Порядок важен: YYYY раньше YY
+var dateFormatTokens = strings.NewReplacer(
+	"YYYY", "2006",
+	"YY", "06",
+	"MM", "01",
+	"DD", "02",
+	"HH", "15",
+	"mm", "04",
+	"ss", "05",
+)
+
+// ParseCSV читает операции из CSV с заголовком. Если операций больше limit, возвращается ошибка
+func ParseCSV(r io.Reader, mapping CSVMapping, limit int) ([]Transaction, error) {
+	layout := dateFormatTokens.Replace(mapping.DateFormat)
+	decimal := '.'
+	if mapping.DecimalComma {
+		decimal = ','
+	}
+	reader := csv.NewReader(r)
+	reader.Comma = mapping.Delimiter
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать заголовок CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		// Excel добавляет BOM в начало файла в UTF-8
+		name = strings.TrimPrefix(name, "\ufeff")
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	column := func(name string, required bool) (int, error) {
+		if name == "" && !required {
+			return -1, nil
+		}
+		index, ok := columns[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, fmt.Errorf("в заголовке CSV нет колонки %q", name)
+		}
+		return index, nil
+	}
+	dateColumn, err := column(mapping.DateColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	amountColumn, err := column(mapping.AmountColumn, true)
+	if err != nil {
+		return nil, err
+	}
+	descriptionColumn, err := column(mapping.DescriptionColumn, false)
+	if err != nil {
+		return nil, err
+	}
+	currencyColumn, err := column(mapping.CurrencyColumn, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []Transaction
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// csv.ParseError уже содержит номер строки
+			return nil, fmt.Errorf("не удалось прочитать CSV: %w", err)
+		}
+		if isEmptyRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(transactions) == limit {
+			return nil, fmt.Errorf("слишком много операций в выписке, максимум %d", limit)
+		}
+		field := func(index int) string {
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		date, err := time.Parse(layout, field(dateColumn))
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: дата %q не соответствует формату %s", line, field(dateColumn), mapping.DateFormat)
+		}
+		amount, err := parseAmount(field(amountColumn), decimal)
+		if err != nil {
+			return nil, fmt.Errorf("строка %d: %w", line, err)
+		}
+		transactions = append(transactions, Transaction{
+			Line:        line,
+			Date:        date,
+			Amount:      amount,
+			Currency:    field(currencyColumn),
+			Description: field(descriptionColumn),
+		})
+	}
+	return transactions, nil
+}
+
+// parseAmount разбирает сумму из выписки: убирает пробелы и разделители разрядов.
+// Если в сумме есть и запятая, и точка, десятичным разделителем считается стоящий последним.
+// Повторяющийся разделитель отделяет разряды. Единственный разделитель считается десятичным,
+// если совпадает с decimal или decimal равен 0, иначе - разделителем разрядов
+func parseAmount(s string, decimal rune) (money.Amount, error) {
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f', '\'':
+			return -1
+		}
+		return r
+	}, s)
+	separator := decimalSeparator(s, decimal)
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == separator:
+			return '.'
+		case r == ',' || r == '.':
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return 0, errors.New("не указана сумма")
+	}
+	return money.Parse(s)
+}
+
+// decimalSeparator возвращает десятичный разделитель суммы s или 0, если дробной части нет
+func decimalSeparator(s string, decimal rune) rune {
+	first, last := strings.IndexAny(s, ",."), strings.LastIndexAny(s, ",.")
+	if last < 0 {
+		return 0
+	}
+	separator := rune(s[last])
+	switch {
+	case s[first] != s[last]:
+		return separator
+	case first != last:
+		return 0
+	case decimal == 0 || decimal == separator:
+		return separator
+	}
+	return 0
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
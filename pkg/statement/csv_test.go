@@ -0,0 +1,149 @@
+package statement
+
+import (
+	"finance/pkg/money"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		decimal rune
+		want    money.Amount
+	}{
+		{"integer", "1234", '.', 123400},
+		{"decimal point", "12.50", '.', 1250},
+		{"decimal comma", "12,50", ',', 1250},
+		{"negative", "-25.30", '.', -2530},
+		{"explicit plus", "+25.30", '.', 2530},
+		{"thousands comma with decimal point", "1,234.56", '.', 123456},
+		// Последний разделитель - десятичный, даже если разметка ожидает другой
+		{"thousands comma with decimal point and comma mapping", "1,234.56", ',', 123456},
+		{"thousands point with decimal comma", "1.234,56", ',', 123456},
+		{"thousands point with decimal comma and point mapping", "1.234,56", '.', 123456},
+		{"thousands spaces", "1 234 567,89", ',', 123456789},
+		{"non-breaking spaces", "-1 234,50", ',', -123450},
+		{"apostrophes", "1'234.50", '.', 123450},
+		{"repeated thousands comma", "1,234,567", '.', 123456700},
+		{"repeated thousands point", "1.234.567", ',', 123456700},
+		{"single comma without comma mapping", "1,234", '.', 123400},
+		{"single point with comma mapping", "1.234", ',', 123400},
+		{"single comma with any separator", "12,5", 0, 1250},
+		{"single point with any separator", "12.5", 0, 1250},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAmount(tt.value, tt.decimal)
+			if err != nil {
+				t.Fatalf("parseAmount(%q) error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseAmount(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+
+	for _, value := range []string{"", "  ", "abc", "12.345", "1.234,56,7", "1/2"} {
+		if _, err := parseAmount(value, '.'); err == nil {
+			t.Errorf("parseAmount(%q): expected error", value)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "\ufeffDate,Amount,Description,Currency\n" +
+		"2026-03-01,-1250.50,Продукты,RUB\n" +
+		"\n" +
+		"2026-03-02,\"1,234.56\",Зарплата,usd\n" +
+		"2026-03-03,-15,\"Кофе, с собой\",\n"
+	// BOM и регистр в заголовке не мешают найти колонки
+	mapping := DefaultCSVMapping()
+	mapping.CurrencyColumn = "currency"
+
+	got, err := ParseCSV(strings.NewReader(input), mapping, 10)
+	if err != nil {
+		t.Fatalf("ParseCSV error: %v", err)
+	}
+	want := []Transaction{
+		{Line: 2, Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Amount: -125050, Currency: "RUB", Description: "Продукты"},
+		// Пустая строка пропускается, но номера строк остаются номерами в файле
+		{Line: 4, Date: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Amount: 123456, Currency: "usd", Description: "Зарплата"},
+		{Line: 5, Date: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), Amount: -1500, Description: "Кофе, с собой"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseCSV returned %d transactions, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("transaction %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseCSVMapping(t *testing.T) {
+	input := "Дата операции;Сумма;Назначение\n" +
+		"05.03.2026 14:30;-1 234,56;Аптека\n" +
+		"06.03.2026 09:00;1.000,00;Возврат\n" +
+		"07.03.2026 10:15;2,000.50;Перевод\n"
+	mapping := CSVMapping{
+		DateColumn:        "дата операции",
+		AmountColumn:      "СУММА",
+		DescriptionColumn: "Назначение",
+		DateFormat:        "DD.MM.YYYY HH:mm",
+		Delimiter:         ';',
+		DecimalComma:      true,
+	}
+
+	got, err := ParseCSV(strings.NewReader(input), mapping, 10)
+	if err != nil {
+		t.Fatalf("ParseCSV error: %v", err)
+	}
+	want := []struct {
+		date   time.Time
+		amount money.Amount
+	}{
+		{time.Date(2026, 3, 5, 14, 30, 0, 0, time.UTC), -123456},
+		{time.Date(2026, 3, 6, 9, 0, 0, 0, time.UTC), 100000},
+		{time.Date(2026, 3, 7, 10, 15, 0, 0, time.UTC), 200050},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ParseCSV returned %d transactions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !got[i].Date.Equal(want[i].date) || got[i].Amount != want[i].amount {
+			t.Errorf("transaction %d = %s %s, want %s %s", i, got[i].Date, got[i].Amount, want[i].date, want[i].amount)
+		}
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		limit   int
+		wantErr string
+	}{
+		{"empty file", "", 10, "заголовок"},
+		{"missing amount column", "date,description\n2026-03-01,Кофе\n", 10, `"amount"`},
+		{"invalid date", "date,amount,description\n01.03.2026,10\n", 10, "строка 2"},
+		{"missing amount", "date,amount,description\n2026-03-01,10\n2026-03-02,\n", 10, "строка 3"},
+		{"invalid amount", "date,amount,description\n2026-03-01,десять\n", 10, "строка 2"},
+		{"too many fraction digits", "date,amount,description\n2026-03-01,10.505\n", 10, "строка 2"},
+		{"unterminated quote", "date,amount,description\n2026-03-01,\"10\n", 10, "CSV"},
+		{"too many transactions", "date,amount,description\n2026-03-01,1\n2026-03-02,2\n", 1, "максимум 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(tt.input), DefaultCSVMapping(), tt.limit)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
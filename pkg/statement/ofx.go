@@ -0,0 +1,108 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// ParseOFX читает операции из выписки OFX или QFX. Поддерживаются OFX 1.x (SGML, элементы без
+// закрывающих тегов) и OFX 2.x (XML). Если операций больше limit, возвращается ошибка
+func ParseOFX(r io.Reader, limit int) ([]Transaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось прочитать OFX: %w", err)
+	}
+	content := string(data)
+	start := strings.Index(content, "<")
+	if start < 0 || !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, errors.New("файл не похож на OFX: нет элемента <OFX>")
+	}
+
+	var transactions []Transaction
+	var current *Transaction
+	var fields map[string]string
+	currency := ""
+	// Каждый фрагмент после '<' - тег и значение до следующего '<'
+	for _, chunk := range strings.Split(content[start:], "<")[1:] {
+		end := strings.Index(chunk, ">")
+		if end < 0 {
+			continue
+		}
+		tag := strings.ToUpper(strings.TrimSpace(chunk[:end]))
+		value := strings.TrimSpace(html.UnescapeString(chunk[end+1:]))
+		switch {
+		case strings.HasPrefix(tag, "?"), strings.HasPrefix(tag, "!"):
+			continue
+		case tag == "CURDEF":
+			currency = value
+		case tag == "STMTTRN":
+			if len(transactions) == limit {
+				return nil, fmt.Errorf("слишком много операций в выписке, максимум %d", limit)
+			}
+			current = &Transaction{Line: len(transactions) + 1}
+			fields = make(map[string]string)
+		case tag == "/STMTTRN":
+			if current == nil {
+				continue
+			}
+			transaction, err := buildOFXTransaction(*current, fields, currency)
+			if err != nil {
+				return nil, err
+			}
+			transactions = append(transactions, transaction)
+			current = nil
+		case current != nil && !strings.HasPrefix(tag, "/"):
+			fields[tag] = value
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("операция %d: нет закрывающего тега </STMTTRN>", current.Line)
+	}
+	return transactions, nil
+}
+
+func buildOFXTransaction(transaction Transaction, fields map[string]string, currency string) (Transaction, error) {
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("операция %d: %w", transaction.Line, err)
+	}
+	// Банки пишут TRNAMT и с точкой, и с запятой, поэтому единственный разделитель любого вида считается десятичным
+	amount, err := parseAmount(fields["TRNAMT"], 0)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("операция %d: %w", transaction.Line, err)
+	}
+	transaction.Date = date
+	transaction.Amount = amount
+	transaction.Currency = currency
+	// У операции в иностранной валюте своя валюта в CURSYM внутри CURRENCY или ORIGCURRENCY
+	if fields["CURSYM"] != "" {
+		transaction.Currency = fields["CURSYM"]
+	}
+	transaction.Description = fields["NAME"]
+	if transaction.Description == "" {
+		transaction.Description = fields["MEMO"]
+	}
+	return transaction, nil
+}
+
+// parseOFXDate разбирает дату OFX вида YYYYMMDD[HHMMSS[.XXX]][[-5:EST]]. Часовой пояс не учитывается
+func parseOFXDate(value string) (time.Time, error) {
+	digits := value
+	if i := strings.IndexAny(digits, ".["); i >= 0 {
+		digits = digits[:i]
+	}
+	switch len(digits) {
+	case 8:
+		return time.Parse("20060102", digits)
+	case 12:
+		return time.Parse("200601021504", digits)
+	case 14:
+		return time.Parse("20060102150405", digits)
+	default:
+		return time.Time{}, fmt.Errorf("некорректная дата операции %q", value)
+	}
+}
@@ -0,0 +1,172 @@
+package statement
+
+import (
+	"finance/pkg/money"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ofxSGML - выписка OFX 1.x: элементы без закрывающих тегов
+const ofxSGML = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>RUB
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20260301
+<TRNAMT>-1250.50
+<NAME>Продукты
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20260302103000.000[+3:MSK]
+<TRNAMT>1,234.56
+<MEMO>Зарплата
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>202603031415
+<TRNAMT>-15,5
+<NAME>Кофе &amp; выпечка
+<CURRENCY><CURRATE>90.5<CURSYM>USD</CURRENCY>
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+// ofxXML - выписка OFX 2.x в XML
+const ofxXML = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+    <CURDEF>EUR</CURDEF>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <TRNTYPE>DEBIT</TRNTYPE>
+        <DTPOSTED>20260310120000</DTPOSTED>
+        <TRNAMT>-1.234,50</TRNAMT>
+        <NAME>Hotel</NAME>
+        <MEMO>Booking</MEMO>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []Transaction
+	}{
+		{"sgml", ofxSGML, []Transaction{
+			{Line: 1, Date: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Amount: -125050, Currency: "RUB", Description: "Продукты"},
+			// Без NAME описанием становится MEMO, часовой пояс в дате не учитывается
+			{Line: 2, Date: time.Date(2026, 3, 2, 10, 30, 0, 0, time.UTC), Amount: 123456, Currency: "RUB", Description: "Зарплата"},
+			// Валюта операции из CURSYM важнее валюты выписки
+			{Line: 3, Date: time.Date(2026, 3, 3, 14, 15, 0, 0, time.UTC), Amount: -1550, Currency: "USD", Description: "Кофе & выпечка"},
+		}},
+		{"xml", ofxXML, []Transaction{
+			{Line: 1, Date: time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), Amount: -123450, Currency: "EUR", Description: "Hotel"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOFX(strings.NewReader(tt.input), 10)
+			if err != nil {
+				t.Fatalf("ParseOFX error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseOFX returned %d transactions, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("transaction %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	transaction := func(date, amount string) string {
+		return "<OFX><STMTTRN><DTPOSTED>" + date + "<TRNAMT>" + amount + "<NAME>Кофе</STMTTRN></OFX>"
+	}
+	tests := []struct {
+		name    string
+		input   string
+		limit   int
+		wantErr string
+	}{
+		{"not ofx", "date,amount\n2026-03-01,10\n", 10, "<OFX>"},
+		{"invalid date", transaction("2026-03-01", "-10.00"), 10, "некорректная дата"},
+		{"short date", transaction("202603", "-10.00"), 10, "некорректная дата"},
+		{"invalid month", transaction("20261301", "-10.00"), 10, "операция 1"},
+		{"missing amount", transaction("20260301", ""), 10, "не указана сумма"},
+		{"invalid amount", transaction("20260301", "ten"), 10, "операция 1"},
+		{"unclosed transaction", "<OFX><STMTTRN><DTPOSTED>20260301<TRNAMT>-10.00</OFX>", 10, "</STMTTRN>"},
+		{"too many transactions", "<OFX>" + strings.Repeat("<STMTTRN><DTPOSTED>20260301<TRNAMT>1</STMTTRN>", 2) + "</OFX>", 1, "максимум 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseOFX(strings.NewReader(tt.input), tt.limit)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error %q does not contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseOFXDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"20260301", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"202603011415", time.Date(2026, 3, 1, 14, 15, 0, 0, time.UTC)},
+		{"20260301141530", time.Date(2026, 3, 1, 14, 15, 30, 0, time.UTC)},
+		{"20260301141530.123", time.Date(2026, 3, 1, 14, 15, 30, 0, time.UTC)},
+		{"20260301141530[-5:EST]", time.Date(2026, 3, 1, 14, 15, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseOFXDate(tt.value)
+		if err != nil {
+			t.Errorf("parseOFXDate(%q) error: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseOFXDate(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+// Сумма OFX с разделителями разрядов не должна превращаться в дробную часть
+func TestParseOFXAmountSeparators(t *testing.T) {
+	tests := map[string]money.Amount{
+		"1,234.56":   123456,
+		"1.234,56":   123456,
+		"-1,234,567": -123456700,
+		"12,50":      1250,
+		"12.50":      1250,
+	}
+	for value, want := range tests {
+		input := "<OFX><STMTTRN><DTPOSTED>20260301<TRNAMT>" + value + "</STMTTRN></OFX>"
+		got, err := ParseOFX(strings.NewReader(input), 10)
+		if err != nil {
+			t.Errorf("TRNAMT %q: %v", value, err)
+			continue
+		}
+		if got[0].Amount != want {
+			t.Errorf("TRNAMT %q = %s, want %s", value, got[0].Amount, want)
+		}
+	}
+}
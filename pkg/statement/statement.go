@@ -0,0 +1,22 @@
+// Package statement разбирает банковские выписки в форматах CSV и OFX/QFX
+package statement
+
+import (
+	"finance/pkg/money"
+	"time"
+)
+
+// Форматы выписок
+const (
+	CSV = "csv"
+	OFX = "ofx"
+)
+
+// Transaction - операция из выписки. Списания имеют отрицательную сумму, поступления - положительную
+type Transaction struct {
+	Line        int // номер строки в файле CSV или порядковый номер операции в OFX
+	Date        time.Time
+	Amount      money.Amount
+	Currency    string // пусто, если валюта в выписке не указана
+	Description string
+}
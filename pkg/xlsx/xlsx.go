@@ -0,0 +1,310 @@
+// Package xlsx потоково записывает книги Excel (Office Open XML) без сторонних зависимостей.
+// Листы пишутся по одному прямо в zip-архив, поэтому в памяти не накапливаются ни строки, ни таблица строк
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRows - максимальное количество строк на листе Excel
+const MaxRows = 1048576
+
+// maxSheetName - максимальная длина названия листа
+const maxSheetName = 31
+
+// Стили ячеек из styles.xml
+const (
+	styleDefault  = 0
+	styleDate     = 1
+	styleDateTime = 2
+	styleHeader   = 3
+)
+
+// excelEpoch - нулевой день календаря Excel (с учетом ошибки Lotus с 29.02.1900)
+var excelEpoch = time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+
+// Number - число в десятичной записи, которое записывается в ячейку без преобразования во float64
+type Number string
+
+// Writer записывает книгу в w. Ячейки передаются значениями nil, string, bool, int, int64, uint,
+// uint64, float64, Number или time.Time. Время без часов и минут отображается как дата
+type Writer struct {
+	zw     *zip.Writer
+	buf    *bufio.Writer
+	sheets []string
+	row    int
+	open   bool
+	err    error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{
+		zw: zip.NewWriter(w),
+	}
+}
+
+// AddSheet завершает текущий лист и начинает новый. Дальнейшие строки пишутся в него
+func (w *Writer) AddSheet(name string) error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := validateSheetName(name, w.sheets); err != nil {
+		return err
+	}
+	if err := w.closeSheet(); err != nil {
+		return w.fail(err)
+	}
+	file, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)+1))
+	if err != nil {
+		return w.fail(err)
+	}
+	w.sheets = append(w.sheets, name)
+	w.buf = bufio.NewWriterSize(file, 32<<10)
+	w.row = 0
+	w.open = true
+	w.buf.WriteString(xml.Header)
+	w.buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return nil
+}
+
+// WriteHeader записывает строку заголовков полужирным шрифтом
+func (w *Writer) WriteHeader(names ...string) error {
+	values := make([]any, len(names))
+	for i, name := range names {
+		values[i] = name
+	}
+	return w.writeRow(values, styleHeader)
+}
+
+// WriteRow записывает строку значений в текущий лист
+func (w *Writer) WriteRow(values ...any) error {
+	return w.writeRow(values, styleDefault)
+}
+
+// Close завершает последний лист, записывает описание книги и закрывает архив.
+// Сам io.Writer, переданный в NewWriter, не закрывается
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if len(w.sheets) == 0 {
+		return w.fail(errors.New("в книге нет ни одного листа"))
+	}
+	if err := w.closeSheet(); err != nil {
+		return w.fail(err)
+	}
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", w.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", w.workbook()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRels()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		file, err := w.zw.Create(part.name)
+		if err != nil {
+			return w.fail(err)
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return w.fail(err)
+		}
+	}
+	return w.fail(w.zw.Close())
+}
+
+func (w *Writer) writeRow(values []any, style int) error {
+	if w.err != nil {
+		return w.err
+	}
+	if !w.open {
+		return errors.New("строка записывается до создания листа")
+	}
+	if w.row == MaxRows {
+		return w.fail(fmt.Errorf("на листе %q больше %d строк", w.sheets[len(w.sheets)-1], MaxRows))
+	}
+	w.row++
+	fmt.Fprintf(w.buf, `<row r="%d">`, w.row)
+	for i, value := range values {
+		if err := w.writeCell(cellRef(i, w.row), value, style); err != nil {
+			return w.fail(err)
+		}
+	}
+	if _, err := w.buf.WriteString(`</row>`); err != nil {
+		return w.fail(err)
+	}
+	return nil
+}
+
+func (w *Writer) writeCell(ref string, value any, style int) error {
+	styleAttr := ""
+	if style != styleDefault {
+		styleAttr = fmt.Sprintf(` s="%d"`, style)
+	}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if v == "" {
+			return nil
+		}
+		fmt.Fprintf(w.buf, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">`, ref, styleAttr)
+		if err := xml.EscapeText(w.buf, []byte(v)); err != nil {
+			return err
+		}
+		w.buf.WriteString(`</t></is></c>`)
+	case bool:
+		b := "0"
+		if v {
+			b = "1"
+		}
+		fmt.Fprintf(w.buf, `<c r="%s"%s t="b"><v>%s</v></c>`, ref, styleAttr, b)
+	case int:
+		fmt.Fprintf(w.buf, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+	case int64:
+		fmt.Fprintf(w.buf, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+	case uint:
+		fmt.Fprintf(w.buf, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+	case uint64:
+		fmt.Fprintf(w.buf, `<c r="%s"%s><v>%d</v></c>`, ref, styleAttr, v)
+	case float64:
+		fmt.Fprintf(w.buf, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, strconv.FormatFloat(v, 'f', -1, 64))
+	case Number:
+		if _, err := strconv.ParseFloat(string(v), 64); err != nil {
+			return fmt.Errorf("ячейка %s: некорректное число %q", ref, string(v))
+		}
+		fmt.Fprintf(w.buf, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, v)
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		if style == styleDefault {
+			style = styleDateTime
+			if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+				style = styleDate
+			}
+		}
+		fmt.Fprintf(w.buf, `<c r="%s" s="%d"><v>%s</v></c>`, ref, style, strconv.FormatFloat(excelSerial(v), 'f', -1, 64))
+	default:
+		return fmt.Errorf("ячейка %s: неподдерживаемый тип %T", ref, value)
+	}
+	return nil
+}
+
+func (w *Writer) closeSheet() error {
+	if !w.open {
+		return nil
+	}
+	w.open = false
+	w.buf.WriteString(`</sheetData></worksheet>`)
+	return w.buf.Flush()
+}
+
+// fail запоминает первую ошибку: после нее архив поврежден и дальнейшая запись бессмысленна
+func (w *Writer) fail(err error) error {
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	return err
+}
+
+func (w *Writer) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (w *Writer) workbook() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range w.sheets {
+		b.WriteString(`<sheet name="`)
+		xml.EscapeText(&b, []byte(name))
+		fmt.Fprintf(&b, `" sheetId="%d" r:id="rId%d"/>`, i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (w *Writer) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// styles - форматы даты и даты со временем и полужирный шрифт заголовков, порядок cellXfs совпадает с константами style*
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd"/><numFmt numFmtId="165" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+// cellRef возвращает адрес ячейки в формате A1 по номеру колонки с нуля и номеру строки с единицы
+func cellRef(column, row int) string {
+	var letters []byte
+	for column++; column > 0; column = (column - 1) / 26 {
+		letters = append([]byte{byte('A' + (column-1)%26)}, letters...)
+	}
+	return string(letters) + strconv.Itoa(row)
+}
+
+// excelSerial переводит время в порядковый номер дня Excel. Часовой пояс не хранится в ячейке,
+// поэтому записывается время по часам t
+func excelSerial(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(excelEpoch).Hours() / 24
+}
+
+func validateSheetName(name string, existing []string) error {
+	if name == "" || len([]rune(name)) > maxSheetName {
+		return fmt.Errorf("название листа должно содержать от 1 до %d символов", maxSheetName)
+	}
+	if strings.ContainsAny(name, `[]:*?/\`) {
+		return fmt.Errorf("название листа %q содержит недопустимые символы", name)
+	}
+	for _, sheet := range existing {
+		if strings.EqualFold(sheet, name) {
+			return fmt.Errorf("лист %q уже есть в книге", name)
+		}
+	}
+	return nil
+}
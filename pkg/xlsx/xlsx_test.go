@@ -0,0 +1,145 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readParts распаковывает книгу и возвращает содержимое всех файлов архива по именам
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("generated file is not a zip archive: %v", err)
+	}
+	parts := make(map[string]string, len(zr.File))
+	for _, file := range zr.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", file.Name, err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", file.Name, err)
+		}
+		// Каждая часть книги должна быть корректным XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s is not well-formed XML: %v", file.Name, err)
+			}
+		}
+		parts[file.Name] = string(content)
+	}
+	return parts
+}
+
+func TestWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewWriter(&out)
+	steps := []func() error{
+		func() error { return w.AddSheet("expenses") },
+		func() error { return w.WriteHeader("id", "description", "amount", "date") },
+		func() error {
+			return w.WriteRow(1, `Кафе "Ёлка" & <бар>`, Number("-1250.50"), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+		},
+		func() error {
+			return w.WriteRow(int64(2), "  с пробелами  ", 12.5, time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+		},
+		func() error { return w.WriteRow(uint(3), nil, "", true) },
+		func() error { return w.AddSheet("R&D <2026>") },
+		func() error { return w.WriteRow("only") },
+		w.Close,
+	}
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i+1, err)
+		}
+	}
+
+	parts := readParts(t, out.Bytes())
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml", "xl/worksheets/sheet2.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("archive has no %s", name)
+		}
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	want := []string{
+		`<row r="1"><c r="A1" s="3" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`,
+		// Спецсимволы в тексте экранируются, число записывается как есть
+		`<c r="B2" t="inlineStr"><is><t xml:space="preserve">Кафе &#34;Ёлка&#34; &amp; &lt;бар&gt;</t></is></c>`,
+		`<c r="C2"><v>-1250.50</v></c>`,
+		// Дата без времени получает формат даты, с временем - формат даты и времени
+		`<c r="D2" s="1"><v>46082</v></c>`,
+		`<c r="A3"><v>2</v></c>`,
+		`<t xml:space="preserve">  с пробелами  </t>`,
+		`<c r="C3"><v>12.5</v></c>`,
+		`<c r="D3" s="2"><v>46082.5</v></c>`,
+		// Пустые значения не создают ячеек
+		`<row r="4"><c r="A4"><v>3</v></c><c r="D4" t="b"><v>1</v></c></row>`,
+	}
+	for _, fragment := range want {
+		if !strings.Contains(sheet, fragment) {
+			t.Errorf("sheet1.xml does not contain %s\n%s", fragment, sheet)
+		}
+	}
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="R&amp;D &lt;2026&gt;" sheetId="2" r:id="rId2"/>`) {
+		t.Errorf("workbook.xml has no escaped second sheet name:\n%s", parts["xl/workbook.xml"])
+	}
+	if !strings.Contains(parts["[Content_Types].xml"], `PartName="/xl/worksheets/sheet2.xml"`) {
+		t.Error("[Content_Types].xml has no second sheet")
+	}
+	if !strings.Contains(parts["xl/_rels/workbook.xml.rels"], `Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"`) {
+		t.Error("workbook.xml.rels has no styles relationship after the sheets")
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	w := NewWriter(io.Discard)
+	if err := w.WriteRow("before sheet"); err == nil {
+		t.Error("expected error for a row before the first sheet")
+	}
+	for _, name := range []string{"", strings.Repeat("a", maxSheetName+1), "a/b", "[x]"} {
+		if err := w.AddSheet(name); err == nil {
+			t.Errorf("AddSheet(%q): expected error", name)
+		}
+	}
+	if err := w.AddSheet("Data"); err != nil {
+		t.Fatalf("AddSheet: %v", err)
+	}
+	if err := w.AddSheet("data"); err == nil {
+		t.Error("expected error for a duplicate sheet name")
+	}
+	if err := w.WriteRow(Number("1,5")); err == nil {
+		t.Error("expected error for an invalid number")
+	}
+	// После ошибки записи архив поврежден, и книга не закрывается
+	if err := w.Close(); err == nil {
+		t.Error("expected Close to return the first write error")
+	}
+
+	if err := NewWriter(io.Discard).Close(); err == nil {
+		t.Error("expected error for a workbook without sheets")
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	tests := map[int]string{0: "A1", 25: "Z1", 26: "AA1", 51: "AZ1", 52: "BA1", 701: "ZZ1", 702: "AAA1"}
+	for column, want := range tests {
+		if got := cellRef(column, 1); got != want {
+			t.Errorf("cellRef(%d, 1) = %s, want %s", column, got, want)
+		}
+	}
+}